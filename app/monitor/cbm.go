@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/blackchip-org/retro-cs/rcs/cbm"
 	"github.com/blackchip-org/retro-cs/system/c128"
 	"github.com/chzyer/readline"
 )
@@ -110,8 +111,8 @@ func (m *modC128MMU) Command(args []string) error {
 		return valueFunc8(m.out, m.mmu.ReadCR, m.mmu.WriteCR, args[1:])
 	case "info":
 		return m.cmdInfo(args[1:])
-	// case "mode":
-	// 	return valueUint8(m.out, &m.mmu.Mode, args[1:])
+	case "mode":
+		return valueFunc8(m.out, m.mmu.ReadMode, m.mmu.WriteMode, args[1:])
 	case "watch", "w":
 		return m.cmdWatch(args[1:])
 	}
@@ -129,6 +130,8 @@ func (m *modC128MMU) cmdWatch(args []string) error {
 		return valueRW(m.out, &m.mmu.WatchLCR, args[1:])
 	case "pcr": // pre-configuration register
 		return valueRW(m.out, &m.mmu.WatchPCR, args[1:])
+	case "mode": // mode configuration register
+		return valueRW(m.out, &m.mmu.WatchMode, args[1:])
 	case "all":
 		return terminal(args[1:], func() error {
 			m.mmu.WatchCR.W = true
@@ -137,6 +140,8 @@ func (m *modC128MMU) cmdWatch(args []string) error {
 			m.mmu.WatchLCR.R = true
 			m.mmu.WatchPCR.W = true
 			m.mmu.WatchPCR.R = true
+			m.mmu.WatchMode.W = true
+			m.mmu.WatchMode.R = true
 			return nil
 		})
 	case "none":
@@ -153,6 +158,7 @@ func (m *modC128MMU) cmdInfo(args []string) error {
 cr : %v
 lcr: %v %v %v %v
 pcr: %v %v %v %v
+mcr: %v
 `,
 		rcs.X8(uint8(m.mmu.Mem.Bank())),
 		rcs.X8(m.mmu.LCR[0]),
@@ -163,6 +169,7 @@ pcr: %v %v %v %v
 		rcs.X8(m.mmu.PCR[1]),
 		rcs.X8(m.mmu.PCR[2]),
 		rcs.X8(m.mmu.PCR[3]),
+		rcs.X8(m.mmu.Mode),
 	)
 	m.out.Println(strings.TrimSpace(info))
 	return nil
@@ -171,11 +178,12 @@ pcr: %v %v %v %v
 func (m *modC128MMU) AutoComplete() []readline.PrefixCompleterInterface {
 	return []readline.PrefixCompleterInterface{
 		readline.PcItem("cr"),
-		// readline.PcItem("mode"),
+		readline.PcItem("mode"),
 		readline.PcItem("watch",
 			readline.PcItem("cr", acRW...),
 			readline.PcItem("lcr", acRW...),
 			readline.PcItem("pcr", acRW...),
+			readline.PcItem("mode", acRW...),
 			readline.PcItem("all"),
 			readline.PcItem("none"),
 		),
@@ -189,6 +197,8 @@ func (m *modC128MMU) Silence() error {
 	m.mmu.WatchLCR.R = false
 	m.mmu.WatchPCR.W = false
 	m.mmu.WatchPCR.R = false
+	m.mmu.WatchMode.W = false
+	m.mmu.WatchMode.R = false
 	return nil
 }
 
@@ -280,3 +290,63 @@ func (m *modC128VDC) Silence() error {
 	m.vdc.WatchData.R = false
 	return nil
 }
+
+type modCBMVIC struct {
+	mon *Monitor
+	out *log.Logger
+	vic *cbm.VIC
+}
+
+func newModCBMVIC(mon *Monitor, comp rcs.Component) module {
+	return &modCBMVIC{
+		mon: mon,
+		out: mon.out,
+		vic: comp.C.(*cbm.VIC),
+	}
+}
+
+func (m *modCBMVIC) Command(args []string) error {
+	if len(args) == 0 {
+		return m.info(args[0:])
+	}
+	switch args[0] {
+	case "blank":
+		return valueBool(m.out, &m.vic.Blank, args[1:])
+	case "info":
+		return m.info(args[1:])
+	case "raster":
+		return valueInt(m.out, &m.vic.Raster, args[1:])
+	}
+	return fmt.Errorf("no such command: %v", args[0])
+}
+
+func (m *modCBMVIC) info(args []string) error {
+	if err := checkLen(args, 0, 0); err != nil {
+		return err
+	}
+	format := strings.TrimSpace(`
+border: %v
+bg    : %v
+raster: %v
+blank : %v
+			`)
+	m.out.Printf(format,
+		rcs.X8(m.vic.BorderColor),
+		rcs.X8(m.vic.BgColor),
+		m.vic.Raster,
+		m.vic.Blank,
+	)
+	return nil
+}
+
+func (m *modCBMVIC) AutoComplete() []readline.PrefixCompleterInterface {
+	return []readline.PrefixCompleterInterface{
+		readline.PcItem("blank"),
+		readline.PcItem("info"),
+		readline.PcItem("raster"),
+	}
+}
+
+func (m *modCBMVIC) Silence() error {
+	return nil
+}
@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/chzyer/readline"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/blackchip-org/retro-cs/system/digdug"
+)
+
+type modDigDug struct {
+	mon    *Monitor
+	out    *log.Logger
+	digdug *digdug.System
+}
+
+func newModDigDug(mon *Monitor, comp rcs.Component) module {
+	return &modDigDug{
+		mon:    mon,
+		out:    mon.out,
+		digdug: comp.C.(*digdug.System),
+	}
+}
+
+func (m *modDigDug) Command(args []string) error {
+	if err := checkLen(args, 1, maxArgs); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "interrupt-enable1":
+		return valueBit(m.out, &m.digdug.InterruptEnable0, (1 << 0), args[1:])
+	case "interrupt-enable2":
+		return valueBit(m.out, &m.digdug.InterruptEnable1, (1 << 0), args[1:])
+	case "interrupt-enable3":
+		return valueBit(m.out, &m.digdug.InterruptEnable2, (1 << 0), args[1:])
+	}
+	return fmt.Errorf("no such command: %v", args[0])
+}
+
+func (m *modDigDug) AutoComplete() []readline.PrefixCompleterInterface {
+	return []readline.PrefixCompleterInterface{
+		readline.PcItem("interrupt-enable1"),
+		readline.PcItem("interrupt-enable2"),
+		readline.PcItem("interrupt-enable3"),
+	}
+}
+
+func (m *modDigDug) Silence() error {
+	return nil
+}
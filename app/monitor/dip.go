@@ -0,0 +1,64 @@
+package monitor
+
+import (
+	"log"
+
+	"github.com/chzyer/readline"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+type modDIP struct {
+	mon *Monitor
+	out *log.Logger
+	dip *rcs.DIPSwitches
+}
+
+func newModDIP(mon *Monitor, comp rcs.Component) module {
+	return &modDIP{
+		mon: mon,
+		out: mon.out,
+		dip: comp.C.(*rcs.DIPSwitches),
+	}
+}
+
+func (m *modDIP) Command(args []string) error {
+	if err := checkLen(args, 0, 2); err != nil {
+		return err
+	}
+	switch len(args) {
+	case 0:
+		for _, s := range m.dip.Table {
+			v, err := m.dip.Get(s.Name)
+			if err != nil {
+				return err
+			}
+			m.out.Printf("%-12s %v", s.Name, v)
+		}
+		return nil
+	case 1:
+		v, err := m.dip.Get(args[0])
+		if err != nil {
+			return err
+		}
+		m.out.Println(v)
+		return nil
+	}
+	return m.dip.Set(args[0], args[1])
+}
+
+func (m *modDIP) AutoComplete() []readline.PrefixCompleterInterface {
+	var items []readline.PrefixCompleterInterface
+	for _, s := range m.dip.Table {
+		var values []readline.PrefixCompleterInterface
+		for _, v := range s.Values {
+			values = append(values, readline.PcItem(v.Name))
+		}
+		items = append(items, readline.PcItem(s.Name, values...))
+	}
+	return items
+}
+
+func (m *modDIP) Silence() error {
+	return nil
+}
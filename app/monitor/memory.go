@@ -47,6 +47,8 @@ func (m *modMemory) Command(args []string) error {
 		return m.cmdDump(args[1:])
 	case "fill":
 		return m.cmdFill(args[1:])
+	case "map":
+		return m.cmdMap(args[1:])
 	case "peek":
 		return m.cmdPeek(args[1:])
 	case "poke":
@@ -115,6 +117,77 @@ func (m *modMemory) cmdFill(args []string) error {
 	return nil
 }
 
+func (m *modMemory) cmdMap(args []string) error {
+	if err := checkLen(args, 0, 1); err != nil {
+		return err
+	}
+	bank := m.mem.Bank()
+	if len(args) > 0 {
+		v, err := parseValue(args[0])
+		if err != nil {
+			return err
+		}
+		if v < 0 || v >= m.mem.NBank {
+			return fmt.Errorf("invalid bank: %v", args[0])
+		}
+		bank = v
+	}
+	reads, writes := m.mem.Regions(bank)
+	rows := mapRows(reads, writes)
+	width := len("read")
+	for _, row := range rows {
+		if len(row[1]) > width {
+			width = len(row[1])
+		}
+	}
+	lines := []string{fmt.Sprintf("%v%-11v  %-*v  %v", m.prefix(), "range", width, "read", "write")}
+	for _, row := range rows {
+		lines = append(lines, fmt.Sprintf("%v%v  %-*v  %v", m.prefix(), row[0], width, row[1], row[2]))
+	}
+	for _, mirror := range m.mem.Mirrors(bank) {
+		lines = append(lines, fmt.Sprintf("%vmirror %v", m.prefix(), mirror))
+	}
+	m.mon.out.Print(strings.Join(lines, "\n"))
+	return nil
+}
+
+// mapRows splits the address space into ranges where both the read and
+// write regions do not change. Each row is the range and the read and
+// write sources.
+func mapRows(reads []rcs.MemoryRegion, writes []rcs.MemoryRegion) [][3]string {
+	var rows [][3]string
+	addr := 0
+	for i, j := 0, 0; i < len(reads) && j < len(writes); {
+		r, w := reads[i], writes[j]
+		rend, wend := r.Addr+r.Size, w.Addr+w.Size
+		end := rend
+		if wend < end {
+			end = wend
+		}
+		rows = append(rows, [3]string{
+			fmt.Sprintf("$%04x-$%04x", addr, end-1),
+			regionAt(r, addr).Source(),
+			regionAt(w, addr).Source(),
+		})
+		addr = end
+		if rend == end {
+			i++
+		}
+		if wend == end {
+			j++
+		}
+	}
+	return rows
+}
+
+// regionAt returns the part of the region that starts at addr.
+func regionAt(r rcs.MemoryRegion, addr int) rcs.MemoryRegion {
+	r.Offset += addr - r.Addr
+	r.Size -= addr - r.Addr
+	r.Addr = addr
+	return r
+}
+
 func (m *modMemory) cmdPeek(args []string) error {
 	if err := checkLen(args, 1, 1); err != nil {
 		return err
@@ -271,6 +344,7 @@ func (m *modMemory) AutoComplete() []readline.PrefixCompleterInterface {
 	return []readline.PrefixCompleterInterface{
 		readline.PcItem("dump"),
 		readline.PcItem("fill"),
+		readline.PcItem("map"),
 		readline.PcItem("peek"),
 		readline.PcItem("poke"),
 		readline.PcItem("watch-clear"),
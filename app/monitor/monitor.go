@@ -36,13 +36,20 @@ var modules = map[string]func(m *Monitor, comp rcs.Component) module{
 	"c64":      newModC64,
 	"c128/mmu": newModC128MMU,
 	"c128/vdc": newModC128VDC,
+	"cbm/vic":  newModCBMVIC,
 	"cpu":      newModCPU,
+	"digdug":   newModDigDug,
+	"dip":      newModDIP,
 	"galaga":   newModGalaga,
 	"m6502":    newModM6502,
 	"mem":      newModMemory,
+	"n05xx":    newModN05XX,
 	"n06xx":    newModN06XX,
 	"n51xx":    newModN51XX,
+	"n53xx":    newModN53XX,
 	"n54xx":    newModN54XX,
+	"pacman":   newModPacman,
+	"wsg":      newModWSG,
 	"z80":      newModZ80,
 }
 
@@ -261,6 +261,16 @@ $0000  00 00 00 00 ff ff ff ff  ff ff ff ff 00 00 00 00  ................
 + m 0
 $0000  00 00 00 00 ff ff ff ff  ff ff ff ff 00 00 00 00  ................
 		`,
+	}, {
+		"memory map",
+		[]string{"mem map", "mem map 1"},
+		`
++ mem map
+range        read       write
+$0000-$ffff  ram $0000  ram $0000
++ mem map 1
+invalid bank: 1
+		`,
 	}, {
 		"next",
 		[]string{"n"},
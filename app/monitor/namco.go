@@ -10,6 +10,57 @@ import (
 	"github.com/blackchip-org/retro-cs/rcs/namco"
 )
 
+type modN05XX struct {
+	mon   *Monitor
+	out   *log.Logger
+	n05xx *namco.N05XX
+}
+
+func newModN05XX(mon *Monitor, comp rcs.Component) module {
+	return &modN05XX{
+		mon:   mon,
+		out:   mon.out,
+		n05xx: comp.C.(*namco.N05XX),
+	}
+}
+
+func (m *modN05XX) Command(args []string) error {
+	if err := checkLen(args, 1, maxArgs); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "enable":
+		return valueBit(m.out, &m.n05xx.Control[namco.StarEnable], (1 << 0), args[1:])
+	case "scroll":
+		return valueInt(m.out, &m.n05xx.Scroll, args[1:])
+	case "set-a":
+		return valueBit(m.out, &m.n05xx.Control[namco.StarSetA], (1 << 0), args[1:])
+	case "set-b":
+		return valueBit(m.out, &m.n05xx.Control[namco.StarSetB], (1 << 0), args[1:])
+	case "speed":
+		return valueFunc8(m.out, m.n05xx.Speed, m.n05xx.SetSpeed, args[1:])
+	case "watch-write":
+		return valueBool(m.out, &m.n05xx.WatchW, args[1:])
+	}
+	return fmt.Errorf("no such command: %v", args[0])
+}
+
+func (m *modN05XX) AutoComplete() []readline.PrefixCompleterInterface {
+	return []readline.PrefixCompleterInterface{
+		readline.PcItem("enable"),
+		readline.PcItem("scroll"),
+		readline.PcItem("set-a"),
+		readline.PcItem("set-b"),
+		readline.PcItem("speed"),
+		readline.PcItem("watch-write"),
+	}
+}
+
+func (m *modN05XX) Silence() error {
+	m.n05xx.WatchW = false
+	return nil
+}
+
 type modN06XX struct {
 	mon   *Monitor
 	out   *log.Logger
@@ -94,6 +145,8 @@ func (m *modN51XX) Command(args []string) error {
 		return err
 	}
 	switch args[0] {
+	case "credits":
+		return valueInt(m.out, &m.n51xx.Credits, args[1:])
 	case "watch-write":
 		return valueBool(m.out, &m.n51xx.WatchW, args[1:])
 	case "watch-read":
@@ -112,6 +165,7 @@ func (m *modN51XX) Command(args []string) error {
 
 func (m *modN51XX) AutoComplete() []readline.PrefixCompleterInterface {
 	return []readline.PrefixCompleterInterface{
+		readline.PcItem("credits"),
 		readline.PcItem("watch-write"),
 		readline.PcItem("watch-read"),
 		readline.PcItem("watch-all"),
@@ -125,6 +179,51 @@ func (m *modN51XX) Silence() error {
 	return nil
 }
 
+type modN53XX struct {
+	mon   *Monitor
+	out   *log.Logger
+	n53xx *namco.N53XX
+}
+
+func newModN53XX(mon *Monitor, comp rcs.Component) module {
+	return &modN53XX{
+		mon:   mon,
+		out:   mon.out,
+		n53xx: comp.C.(*namco.N53XX),
+	}
+}
+
+func (m *modN53XX) Command(args []string) error {
+	if err := checkLen(args, 1, maxArgs); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "watch-read":
+		return valueBool(m.out, &m.n53xx.WatchR, args[1:])
+	case "watch-all":
+		return terminal(args[1:], func() error {
+			m.n53xx.WatchR = true
+			return nil
+		})
+	case "watch-none":
+		return terminal(args[1:], m.Silence)
+	}
+	return fmt.Errorf("no such command: %v", args[0])
+}
+
+func (m *modN53XX) AutoComplete() []readline.PrefixCompleterInterface {
+	return []readline.PrefixCompleterInterface{
+		readline.PcItem("watch-read"),
+		readline.PcItem("watch-all"),
+		readline.PcItem("watch-none"),
+	}
+}
+
+func (m *modN53XX) Silence() error {
+	m.n53xx.WatchR = false
+	return nil
+}
+
 type modN54XX struct {
 	mon   *Monitor
 	out   *log.Logger
@@ -174,3 +273,49 @@ func (m *modN54XX) Silence() error {
 	m.n54xx.WatchR = false
 	return nil
 }
+
+type modWSG struct {
+	mon *Monitor
+	out *log.Logger
+	wsg *namco.WSG
+}
+
+func newModWSG(mon *Monitor, comp rcs.Component) module {
+	return &modWSG{
+		mon: mon,
+		out: mon.out,
+		wsg: comp.C.(*namco.WSG),
+	}
+}
+
+func (m *modWSG) Command(args []string) error {
+	if err := checkLen(args, 1, maxArgs); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "enable":
+		return valueBit(m.out, &m.wsg.Enable, (1 << 0), args[1:])
+	case "info", "i":
+		return terminal(args[1:], m.cmdInfo)
+	}
+	return fmt.Errorf("no such command: %v", args[0])
+}
+
+func (m *modWSG) cmdInfo() error {
+	m.out.Println("    acc     freq    wave vol")
+	for i, v := range m.wsg.Voices {
+		m.out.Printf("%v:  %05x   %05x   %v    %v", i, v.Acc, v.Freq, v.Waveform, v.Vol)
+	}
+	return nil
+}
+
+func (m *modWSG) AutoComplete() []readline.PrefixCompleterInterface {
+	return []readline.PrefixCompleterInterface{
+		readline.PcItem("enable"),
+		readline.PcItem("info"),
+	}
+}
+
+func (m *modWSG) Silence() error {
+	return nil
+}
@@ -0,0 +1,150 @@
+package monitor
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/chzyer/readline"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/blackchip-org/retro-cs/system/pacman"
+)
+
+type modPacman struct {
+	mon    *Monitor
+	out    *log.Logger
+	pacman *pacman.System
+}
+
+func newModPacman(mon *Monitor, comp rcs.Component) module {
+	return &modPacman{
+		mon:    mon,
+		out:    mon.out,
+		pacman: comp.C.(*pacman.System),
+	}
+}
+
+func (m *modPacman) Command(args []string) error {
+	if err := checkLen(args, 1, maxArgs); err != nil {
+		return err
+	}
+	switch args[0] {
+	case "flip-screen":
+		return valueBit(m.out, &m.pacman.FlipScreen, (1 << 0), args[1:])
+	case "inputs":
+		return m.cmdInputs(args[1:])
+	case "interrupt-enable":
+		return valueBit(m.out, &m.pacman.InterruptEnable, (1 << 0), args[1:])
+	case "latches":
+		return m.cmdLatches(args[1:])
+	case "state":
+		return m.cmdState(args[1:])
+	case "watchdog":
+		return valueBool(m.out, &m.pacman.Watchdog, args[1:])
+	case "watch-watchdog":
+		return valueBool(m.out, &m.pacman.WatchWatchdog, args[1:])
+	}
+	return fmt.Errorf("no such command: %v", args[0])
+}
+
+func (m *modPacman) cmdState(args []string) error {
+	if err := checkLen(args, 0, 0); err != nil {
+		return err
+	}
+	st := m.pacman.State()
+	scatter := "chase"
+	if st.Scatter {
+		scatter = "scatter"
+	}
+	info := fmt.Sprintf(`
+level : %v
+lives : %v
+player: %v
+score : %v %v
+credit: %v
+dots  : %v
+mode  : %v, timer %v
+blue  : timer %v
+
+         x    y    tile-x tile-y mode        dots
+pac-man  $%02x  $%02x  $%02x    $%02x
+`,
+		st.Level, st.Lives, st.Player, st.Score[0], st.Score[1], st.Credits,
+		st.DotsEaten, scatter, st.ModeTimer, st.BlueTimer,
+		st.PacMan.X, st.PacMan.Y, st.PacMan.TileX, st.PacMan.TileY,
+	)
+	for _, g := range st.Ghosts {
+		info += fmt.Sprintf("%-8v $%02x  $%02x  $%02x    $%02x   %-11v %v\n",
+			g.Name, g.X, g.Y, g.TileX, g.TileY, g.Mode, g.Dots)
+	}
+	m.out.Println(strings.TrimSpace(info))
+	return nil
+}
+
+func (m *modPacman) cmdLatches(args []string) error {
+	if err := checkLen(args, 0, 0); err != nil {
+		return err
+	}
+	p := m.pacman
+	info := fmt.Sprintf(`
+interrupt-enable: %v
+sound-enable    : %v
+flip-screen     : %v
+lamp-player1    : %v
+lamp-player2    : %v
+coin-lockout    : %v
+coin-counter    : %v
+`,
+		p.InterruptEnable&1, p.SoundEnable()&1, p.FlipScreen&1,
+		p.LampPlayer1&1, p.LampPlayer2&1, p.CoinLockout&1, p.CoinCounter&1,
+	)
+	m.out.Println(strings.TrimSpace(info))
+	return nil
+}
+
+var (
+	pacmanIN0 = []string{"up", "left", "right", "down", "rack-test", "coin1", "coin2", "service"}
+	pacmanIN1 = []string{"up2", "left2", "right2", "down2", "board-test", "start1", "start2", "cabinet"}
+)
+
+// cmdInputs shows the value of each bit in the input ports. The joystick
+// bits are active low. The coin, rack test, and start bits are set while
+// the key or button is held down.
+func (m *modPacman) cmdInputs(args []string) error {
+	if err := checkLen(args, 0, 0); err != nil {
+		return err
+	}
+	ports := []struct {
+		name  string
+		value uint8
+		bits  []string
+	}{
+		{"in0", m.pacman.IN0, pacmanIN0},
+		{"in1", m.pacman.IN1, pacmanIN1},
+	}
+	for _, port := range ports {
+		m.out.Printf("%v: %v", port.name, rcs.X8(port.value))
+		for i, name := range port.bits {
+			m.out.Printf("  %-10v %v", name, port.value>>uint(i)&1)
+		}
+	}
+	return nil
+}
+
+func (m *modPacman) AutoComplete() []readline.PrefixCompleterInterface {
+	return []readline.PrefixCompleterInterface{
+		readline.PcItem("flip-screen"),
+		readline.PcItem("inputs"),
+		readline.PcItem("interrupt-enable"),
+		readline.PcItem("latches"),
+		readline.PcItem("state"),
+		readline.PcItem("watchdog"),
+		readline.PcItem("watch-watchdog"),
+	}
+}
+
+func (m *modPacman) Silence() error {
+	m.pacman.WatchWatchdog = false
+	return nil
+}
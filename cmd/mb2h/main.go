@@ -4,23 +4,16 @@ import (
 	"flag"
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
+
+	"github.com/blackchip-org/retro-cs/rcs"
 )
 
 func mb2h(in string) (string, error) {
-	in = strings.Replace(in, "-", "0", -1)
-	strlo := strings.Replace(in, "x", "0", -1)
-	strhi := strings.Replace(in, "x", "1", -1)
-
-	lo, err := strconv.ParseUint(strlo, 2, 16)
-	if err != nil {
-		return "", err
-	}
-	hi, err := strconv.ParseUint(strhi, 2, 16)
+	lo, mask, err := rcs.ParseAddrMask(in)
 	if err != nil {
 		return "", err
 	}
+	hi := lo | mask
 	if lo == hi {
 		return fmt.Sprintf("%04x", lo), nil
 	}
@@ -7,6 +7,7 @@ import (
 	"github.com/blackchip-org/retro-cs/rcs/cbm"
 	"github.com/blackchip-org/retro-cs/rcs/namco"
 	"github.com/blackchip-org/retro-cs/system/c64"
+	"github.com/blackchip-org/retro-cs/system/digdug"
 	"github.com/blackchip-org/retro-cs/system/galaga"
 	"github.com/blackchip-org/retro-cs/system/pacman"
 	"github.com/veandco/go-sdl2/sdl"
@@ -27,6 +28,69 @@ var views = map[string]view{
 			return rcs.NewColorSheet(r, palettes)
 		},
 	},
+	"digdug:chars": view{
+		system: "digdug",
+		roms:   digdug.ROM["digdug"],
+		render: func(r *sdl.Renderer, d map[string][]byte) (rcs.TileSheet, error) {
+			return namco.NewTileSheet(r, d["chars"], digdug.CharLayout,
+				namco.ViewerPalette)
+		},
+	},
+	"digdug:colors": view{
+		system: "digdug",
+		roms:   digdug.ROM["digdug"],
+		render: func(r *sdl.Renderer, d map[string][]byte) (rcs.TileSheet, error) {
+			colors := namco.ColorTable(digdug.VideoConfig, d["colors"])
+			return rcs.NewColorSheet(r, [][]color.RGBA{colors})
+		},
+	},
+	"digdug:palettes": view{
+		system: "digdug",
+		roms:   digdug.ROM["digdug"],
+		render: func(r *sdl.Renderer, d map[string][]byte) (rcs.TileSheet, error) {
+			config := digdug.VideoConfig
+			colors := namco.ColorTable(config, d["colors"])
+			tiles := namco.PaletteTable(config, d["palettes"], colors)
+			sprites := namco.PaletteTable(config, d["spritepalettes"], colors[0x10:])
+			return rcs.NewColorSheet(r, append(tiles, sprites...))
+		},
+	},
+	"digdug:sprites": view{
+		system: "digdug",
+		roms:   digdug.ROM["digdug"],
+		render: func(r *sdl.Renderer, d map[string][]byte) (rcs.TileSheet, error) {
+			return namco.NewTileSheet(r, d["sprites"],
+				digdug.VideoConfig.SpriteLayout, namco.ViewerPalette)
+		},
+	},
+	"digdug:tiles": view{
+		system: "digdug",
+		roms:   digdug.ROM["digdug"],
+		render: func(r *sdl.Renderer, d map[string][]byte) (rcs.TileSheet, error) {
+			return namco.NewTileSheet(r, d["tiles"],
+				digdug.VideoConfig.TileLayout, namco.ViewerPalette)
+		},
+	},
+	"galaga:colors": view{
+		system: "galaga",
+		roms:   galaga.ROM["galaga"],
+		render: func(r *sdl.Renderer, d map[string][]byte) (rcs.TileSheet, error) {
+			config := galaga.VideoConfig
+			colors := namco.ColorTable(config, d["colors"])
+			return rcs.NewColorSheet(r, [][]color.RGBA{colors})
+		},
+	},
+	"galaga:palettes": view{
+		system: "galaga",
+		roms:   galaga.ROM["galaga"],
+		render: func(r *sdl.Renderer, d map[string][]byte) (rcs.TileSheet, error) {
+			config := galaga.VideoConfig
+			colors := namco.ColorTable(config, d["colors"])
+			tiles := namco.PaletteTable(config, d["palettes"], colors[config.TileColorBase:])
+			sprites := namco.PaletteTable(config, d["spritepalettes"], colors)
+			return rcs.NewColorSheet(r, append(tiles, sprites...))
+		},
+	},
 	"galaga:sprites": view{
 		system: "galaga",
 		roms:   galaga.ROM["galaga"],
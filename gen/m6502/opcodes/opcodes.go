@@ -0,0 +1,278 @@
+package main
+
+//go:generate go run .
+//go:generate go fmt ../../../rcs/m6502/opcodes.go
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var (
+	root      = filepath.Join("..", "..", "..")
+	targetDir = filepath.Join(root, "rcs", "m6502")
+)
+
+// Instruction tables to generate and the disassembler tables they are
+// generated from.
+var tables = []struct {
+	name string
+	dasm string
+	file string
+}{
+	{"opcodes", "dasmTable", "dasm.go"},
+	{"cmosOpcodes", "cmosDasmTable", "cmos.go"},
+	{"bitOpcodes", "bitDasmTable", "cmos.go"},
+	{"wdcOpcodes", "wdcDasmTable", "cmos.go"},
+}
+
+type op struct {
+	inst string
+	mode string
+}
+
+// instructions that only have the implied mode
+var implied = map[string]string{
+	"brk": "brk(c)",
+	"clc": "c.SR &^= FlagC",
+	"cld": "c.SR &^= FlagD",
+	"cli": "c.SR &^= FlagI",
+	"clv": "c.SR &^= FlagV",
+	"dex": "dec(c, c.storeX, c.loadX)",
+	"dey": "dec(c, c.storeY, c.loadY)",
+	"inx": "inc(c, c.storeX, c.loadX)",
+	"iny": "inc(c, c.storeY, c.loadY)",
+	"jam": "jam(c)",
+	"jsr": "jsr(c)",
+	"pha": "c.push(c.A)",
+	"php": "php(c)",
+	"phx": "c.push(c.X)",
+	"phy": "c.push(c.Y)",
+	"pla": "pla(c)",
+	"plp": "plp(c)",
+	"plx": "ld(c, c.storeX, c.pull)",
+	"ply": "ld(c, c.storeY, c.pull)",
+	"rti": "rti(c)",
+	"rts": "c.pc = c.pull2()",
+	"sec": "c.SR |= FlagC",
+	"sed": "c.SR |= FlagD",
+	"sei": "c.SR |= FlagI",
+	"stp": "jam(c)",
+	"tax": "ld(c, c.storeX, c.loadA)",
+	"tay": "ld(c, c.storeY, c.loadA)",
+	"tsx": "ld(c, c.storeX, c.loadSP)",
+	"txa": "ld(c, c.storeA, c.loadX)",
+	"txs": "c.storeSP(c.loadX())",
+	"tya": "ld(c, c.storeA, c.loadY)",
+	"wai": "c.Wait = true",
+}
+
+var branches = map[string]string{
+	"bcc": "c.SR&FlagC == 0",
+	"bcs": "c.SR&FlagC != 0",
+	"beq": "c.SR&FlagZ != 0",
+	"bmi": "c.SR&FlagN != 0",
+	"bne": "c.SR&FlagZ == 0",
+	"bpl": "c.SR&FlagN == 0",
+	"bra": "true",
+	"bvc": "c.SR&FlagV == 0",
+	"bvs": "c.SR&FlagV != 0",
+}
+
+// instructions that read a value
+var reads = map[string]bool{
+	"adc": true, "alr": true, "anc": true, "and": true, "arr": true,
+	"eor": true, "lax": true, "ora": true, "sbc": true, "sbx": true,
+	"trb": true, "tsb": true,
+}
+
+// instructions that read, modify, and write back a value
+var modifies = map[string]bool{
+	"asl": true, "dcp": true, "dec": true, "inc": true, "isc": true,
+	"lsr": true, "rla": true, "rol": true, "ror": true, "rra": true,
+	"slo": true, "sre": true,
+}
+
+var registers = map[string]string{
+	"a": "A", "x": "X", "y": "Y",
+}
+
+var notes = map[string]string{
+	"txs": "does not set NZ",
+}
+
+func mode(m string) string {
+	return strings.ToUpper(m[:1]) + m[1:]
+}
+
+func call(o op) (string, error) {
+	inst, m := o.inst, mode(o.mode)
+	if fn, ok := implied[inst]; ok {
+		return fn, nil
+	}
+	if cond, ok := branches[inst]; ok {
+		return fmt.Sprintf("branch(c, %v)", cond), nil
+	}
+	if reads[inst] {
+		return fmt.Sprintf("%v(c, c.load%v)", inst, m), nil
+	}
+	if modifies[inst] {
+		if o.mode == "accumulator" {
+			return fmt.Sprintf("%v(c, c.storeA, c.loadA)", inst), nil
+		}
+		return fmt.Sprintf("%v(c, c.storeBack, c.load%v)", inst, m), nil
+	}
+	switch inst {
+	case "nop":
+		if o.mode == "implied" {
+			return "", nil
+		}
+		return fmt.Sprintf("nop(c, c.load%v)", m), nil
+	case "bit":
+		if o.mode == "immediate" {
+			return "bitImmediate(c, c.loadImmediate)", nil
+		}
+		return fmt.Sprintf("bit(c, c.load%v)", m), nil
+	case "jmp":
+		switch o.mode {
+		case "absolute":
+			return "jmp(c)", nil
+		case "indirect":
+			return "jmpIndirect(c)", nil
+		case "absoluteIndirectX":
+			return "jmpIndirectX(c)", nil
+		}
+	case "lda", "ldx", "ldy":
+		return fmt.Sprintf("ld(c, c.store%v, c.load%v)", registers[inst[2:]], m), nil
+	case "sta", "stx", "sty":
+		return fmt.Sprintf("st(c, c.store%v, c.load%v)", m, registers[inst[2:]]), nil
+	case "stz":
+		return fmt.Sprintf("st(c, c.store%v, c.loadZero)", m), nil
+	case "sax":
+		return fmt.Sprintf("sax(c, c.store%v)", m), nil
+	case "cmp":
+		return fmt.Sprintf("cmp(c, c.loadA, c.load%v)", m), nil
+	case "cpx", "cpy":
+		return fmt.Sprintf("cmp(c, c.load%v, c.load%v)", registers[inst[2:]], m), nil
+	}
+	// rmb0 to rmb7, smb0 to smb7, bbr0 to bbr7, bbs0 to bbs7
+	if len(inst) == 4 {
+		switch inst[:3] {
+		case "rmb", "smb", "bbr", "bbs":
+			return fmt.Sprintf("%v(c, %v)", inst[:3], inst[3:]), nil
+		}
+	}
+	return "", fmt.Errorf("no instruction for %v %v", inst, o.mode)
+}
+
+// parse reads the entries in a disassembler table from the source
+func parse(filename string, name string) (map[int]op, error) {
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, filename, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	ops := make(map[int]op)
+	found := false
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok || len(spec.Names) != 1 || spec.Names[0].Name != name {
+			return true
+		}
+		found = true
+		lit := spec.Values[0].(*ast.CompositeLit)
+		for _, elt := range lit.Elts {
+			kv := elt.(*ast.KeyValueExpr)
+			key, e := strconv.ParseInt(kv.Key.(*ast.BasicLit).Value, 0, 0)
+			if e != nil {
+				err = e
+				return false
+			}
+			val := kv.Value.(*ast.CompositeLit)
+			inst, _ := strconv.Unquote(val.Elts[0].(*ast.BasicLit).Value)
+			mode := val.Elts[1].(*ast.Ident).Name
+			ops[int(key)] = op{inst: inst, mode: mode}
+		}
+		return false
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("%v: table %v not found", filename, name)
+	}
+	return ops, nil
+}
+
+func process(out *bytes.Buffer, ops map[int]op) error {
+	first := true
+	for i := 0; i < 0x100; i++ {
+		o, ok := ops[i]
+		if !ok {
+			continue
+		}
+		if i%0x10 == 0 && !first {
+			out.WriteString("\n")
+		}
+		first = false
+		fn, err := call(o)
+		if err != nil {
+			return fmt.Errorf("$%02x: %v", i, err)
+		}
+		line := fmt.Sprintf("0x%02x: func(c *CPU) {%v},", i, fn)
+		if !strings.HasPrefix(fn, o.inst+"(") {
+			line += " // " + o.inst
+			if note, ok := notes[o.inst]; ok {
+				line += ": " + note
+			}
+		}
+		out.WriteString(line + "\n")
+	}
+	return nil
+}
+
+func main() {
+	var out bytes.Buffer
+
+	out.WriteString(`
+// Code generated by gen/m6502/opcodes/opcodes.go. DO NOT EDIT.
+
+package m6502
+
+// http://www.6502.org/tutorials/6502opcodes.html
+// http://www.oxyron.de/html/opcodes02.html
+
+// Instructions are indexed by opcode and are generated from the
+// disassembler tables. Entries for opcodes that are not implemented are
+// nil.
+
+`)
+	for _, table := range tables {
+		ops, err := parse(filepath.Join(targetDir, table.file), table.dasm)
+		if err != nil {
+			fmt.Printf("unable to parse: %v\n", err)
+			os.Exit(1)
+		}
+		out.WriteString(fmt.Sprintf("var %v = [256]func(*CPU){\n", table.name))
+		if err := process(&out, ops); err != nil {
+			fmt.Printf("%v: %v\n", table.name, err)
+			os.Exit(1)
+		}
+		out.WriteString("}\n\n")
+	}
+
+	filename := filepath.Join(targetDir, "opcodes.go")
+	err := ioutil.WriteFile(filename, out.Bytes(), 0644)
+	if err != nil {
+		fmt.Printf("unable to write file: %v", err)
+		os.Exit(1)
+	}
+}
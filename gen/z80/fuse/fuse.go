@@ -159,6 +159,7 @@ func parseTest(name string, scanner *bufio.Scanner) {
 	t["iy"] = f1[9]
 	t["sp"] = f1[10]
 	t["pc"] = f1[11]
+	t["memptr"] = f1[12]
 
 	scanner.Scan()
 	text2 := whitespace.ReplaceAllString(scanner.Text(), " ")
@@ -213,6 +214,7 @@ var testTemplate = template.Must(template.New("").Parse(`fuseTest{
 	iy: 0x{{.iy}},
 	sp: 0x{{.sp}},
 	pc: 0x{{.pc}},
+	memptr: 0x{{.memptr}},
 	i: 0x{{.i}},
 	r: 0x{{.r}},
 	iff1: {{.iff1}},
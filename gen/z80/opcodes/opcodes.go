@@ -320,7 +320,7 @@ func processMain(tab *regtab, op uint8) string {
 					return "exx(c)"
 				}
 				if p == 2 {
-					return fmt.Sprintf("jpa(c, c.load%v)", rp2[2])
+					return fmt.Sprintf("jpr(c, c.load%v)", rp2[2])
 				}
 				if p == 3 {
 					return fmt.Sprintf("ld16(c, c.storeSP, c.load%v)", rp2[2])
@@ -452,6 +452,9 @@ func processCB(tab *regtab, op uint8) string {
 		}
 	}
 	if x == 1 {
+		if z == 6 {
+			return fmt.Sprintf("biti(c, %v, c.load%v)", y, r[z])
+		}
 		return fmt.Sprintf("bit(c, %v, c.load%v)", y, r[z])
 	}
 	if x == 2 {
@@ -756,32 +759,35 @@ func main() {
 
 package z80
 
+// Instructions are indexed by opcode. Entries for opcodes that are not
+// implemented are nil.
+
 `)
-	out.WriteString("var opcodes = map[uint8]func(c *CPU){\n")
+	out.WriteString("var opcodes = [256]func(c *CPU){\n")
 	process(&out, processMain, un)
 	out.WriteString("}\n")
 
-	out.WriteString("var opcodesCB = map[uint8]func(c *CPU){\n")
+	out.WriteString("var opcodesCB = [256]func(c *CPU){\n")
 	process(&out, processCB, un)
 	out.WriteString("}\n")
 
-	out.WriteString("var opcodesED = map[uint8]func(c *CPU){\n")
+	out.WriteString("var opcodesED = [256]func(c *CPU){\n")
 	process(&out, processED, un)
 	out.WriteString("}\n")
 
-	out.WriteString("var opcodesDD = map[uint8]func(c *CPU){\n")
+	out.WriteString("var opcodesDD = [256]func(c *CPU){\n")
 	process(&out, processMain, dd)
 	out.WriteString("}\n")
 
-	out.WriteString("var opcodesFD = map[uint8]func(c *CPU){\n")
+	out.WriteString("var opcodesFD = [256]func(c *CPU){\n")
 	process(&out, processMain, fd)
 	out.WriteString("}\n")
 
-	out.WriteString("var opcodesDDCB = map[uint8]func(c *CPU){\n")
+	out.WriteString("var opcodesDDCB = [256]func(c *CPU){\n")
 	process(&out, processXCB, ddcb)
 	out.WriteString("}\n")
 
-	out.WriteString("var opcodesFDCB = map[uint8]func(c *CPU){\n")
+	out.WriteString("var opcodesFDCB = [256]func(c *CPU){\n")
 	process(&out, processXCB, fdcb)
 	out.WriteString("}\n")
 
@@ -16,6 +16,9 @@ const (
 	borderH    = (screenH - height) / 2
 	charSheetW = 32
 	charSheetH = 16
+
+	rasterLines  = 263 // NTSC
+	stepsPerLine = 76  // machine steps for each raster line
 )
 
 type VIC struct {
@@ -25,7 +28,10 @@ type VIC struct {
 
 	BorderColor uint8
 	BgColor     uint8
+	Raster      int  // current raster line
+	Blank       bool // only the border is drawn when set
 
+	steps     int
 	charSheet rcs.TileSheet
 	mem       *rcs.Memory
 }
@@ -54,12 +60,36 @@ func (v *VIC) Draw(r *sdl.Renderer) error {
 	r.SetRenderTarget(v.Texture)
 	r.SetDrawBlendMode(sdl.BLENDMODE_BLEND)
 	v.drawBorder(r)
-	v.drawBackground(r)
-	v.drawCharacters(r)
+	if v.Blank {
+		v.drawBlank(r)
+	} else {
+		v.drawBackground(r)
+		v.drawCharacters(r)
+	}
 	r.SetRenderTarget(nil)
 	return nil
 }
 
+// Next advances the raster beam. Systems that need a raster counter
+// should add the VIC as a component so this is called on each step of
+// the machine.
+func (v *VIC) Next() {
+	v.steps++
+	if v.steps < stepsPerLine {
+		return
+	}
+	v.steps = 0
+	v.Raster++
+	if v.Raster >= rasterLines {
+		v.Raster = 0
+	}
+}
+
+// ReadRaster returns the lower 8 bits of the raster line.
+func (v *VIC) ReadRaster() uint8 {
+	return uint8(v.Raster)
+}
+
 func (v *VIC) drawBorder(r *sdl.Renderer) {
 	c := Palette[v.BorderColor&0x0f]
 	r.SetDrawColor(c.R, c.G, c.B, c.A)
@@ -93,6 +123,18 @@ func (v *VIC) drawBorder(r *sdl.Renderer) {
 	r.FillRect(&rightBorder)
 }
 
+func (v *VIC) drawBlank(r *sdl.Renderer) {
+	c := Palette[v.BorderColor&0x0f]
+	r.SetDrawColor(c.R, c.G, c.B, c.A)
+	screen := sdl.Rect{
+		X: borderW,
+		Y: borderH,
+		W: width,
+		H: height,
+	}
+	r.FillRect(&screen)
+}
+
 func (v *VIC) drawBackground(r *sdl.Renderer) {
 	c := Palette[v.BgColor&0x0f]
 	r.SetDrawColor(c.R, c.G, c.B, c.A)
@@ -0,0 +1,130 @@
+package rcs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// DIPValue is a named position for the switches in a DIPSetting.
+type DIPValue struct {
+	Name string
+	Bits uint8
+}
+
+// DIPSetting is a group of switches, selected by Mask, in one of the
+// DIP switch banks found on a board.
+type DIPSetting struct {
+	Name    string
+	Bank    int
+	Mask    uint8
+	Default string
+	Values  []DIPValue
+}
+
+// DIPSwitches binds a table of settings to the switch banks of a board.
+// The banks are the values read by the hardware.
+type DIPSwitches struct {
+	Table []DIPSetting
+	Banks []*uint8
+}
+
+// NewDIPSwitches creates the switches for the settings in table and sets
+// each to its default value.
+func NewDIPSwitches(table []DIPSetting, banks ...*uint8) *DIPSwitches {
+	d := &DIPSwitches{
+		Table: table,
+		Banks: banks,
+	}
+	d.Reset()
+	return d
+}
+
+// Reset sets all switches to their default values.
+func (d *DIPSwitches) Reset() {
+	for _, s := range d.Table {
+		if err := d.Set(s.Name, s.Default); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func (d *DIPSwitches) setting(name string) (DIPSetting, error) {
+	for _, s := range d.Table {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return DIPSetting{}, fmt.Errorf("no such setting: %v", name)
+}
+
+// Get returns the name of the current value for a setting. If the switches
+// are in a position not found in the table, the bits are returned instead.
+func (d *DIPSwitches) Get(name string) (string, error) {
+	s, err := d.setting(name)
+	if err != nil {
+		return "", err
+	}
+	bits := *d.Banks[s.Bank] & s.Mask
+	for _, v := range s.Values {
+		if v.Bits == bits {
+			return v.Name, nil
+		}
+	}
+	return B8(bits), nil
+}
+
+// Set changes the switches for a setting to the named value.
+func (d *DIPSwitches) Set(name string, value string) error {
+	s, err := d.setting(name)
+	if err != nil {
+		return err
+	}
+	for _, v := range s.Values {
+		if v.Name == value {
+			bank := d.Banks[s.Bank]
+			*bank = *bank&^s.Mask | v.Bits&s.Mask
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid value for %v: %v", name, value)
+}
+
+// Load reads settings, one per line, in the form of "name value". Blank
+// lines and lines starting with '#' are ignored.
+func (d *DIPSwitches) Load(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return fmt.Errorf("line %v: expected setting and value", n)
+		}
+		if err := d.Set(fields[0], fields[1]); err != nil {
+			return fmt.Errorf("line %v: %v", n, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// LoadFile reads settings from filename. It is not an error if the file
+// does not exist.
+func (d *DIPSwitches) LoadFile(filename string) error {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if err := d.Load(f); err != nil {
+		return fmt.Errorf("%v: %v", filename, err)
+	}
+	return nil
+}
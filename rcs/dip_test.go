@@ -0,0 +1,93 @@
+package rcs
+
+import (
+	"strings"
+	"testing"
+)
+
+var testDIP = []DIPSetting{
+	{"coinage", 0, 0x03, "1c1c", []DIPValue{
+		{"free-play", 0x00},
+		{"1c1c", 0x01},
+		{"1c2c", 0x02},
+		{"2c1c", 0x03},
+	}},
+	{"lives", 0, 0x0c, "3", []DIPValue{
+		{"1", 0x00},
+		{"2", 0x04},
+		{"3", 0x08},
+		{"5", 0x0c},
+	}},
+	{"cabinet", 1, 0x80, "upright", []DIPValue{
+		{"upright", 0x80},
+		{"cocktail", 0x00},
+	}},
+}
+
+func TestDIPDefaults(t *testing.T) {
+	bank0, bank1 := uint8(0xf0), uint8(0x00)
+	NewDIPSwitches(testDIP, &bank0, &bank1)
+	if bank0 != 0xf9 {
+		t.Errorf("\n have: %02x \n want: %02x", bank0, 0xf9)
+	}
+	if bank1 != 0x80 {
+		t.Errorf("\n have: %02x \n want: %02x", bank1, 0x80)
+	}
+}
+
+func TestDIPSet(t *testing.T) {
+	bank0, bank1 := uint8(0), uint8(0)
+	d := NewDIPSwitches(testDIP, &bank0, &bank1)
+	if err := d.Set("lives", "5"); err != nil {
+		t.Fatal(err)
+	}
+	if bank0 != 0x0d {
+		t.Errorf("\n have: %02x \n want: %02x", bank0, 0x0d)
+	}
+	have, err := d.Get("lives")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have != "5" {
+		t.Errorf("\n have: %v \n want: %v", have, "5")
+	}
+}
+
+func TestDIPSetInvalid(t *testing.T) {
+	bank0, bank1 := uint8(0), uint8(0)
+	d := NewDIPSwitches(testDIP, &bank0, &bank1)
+	if err := d.Set("lives", "4"); err == nil {
+		t.Errorf("expected error for invalid value")
+	}
+	if err := d.Set("difficulty", "hard"); err == nil {
+		t.Errorf("expected error for invalid setting")
+	}
+}
+
+func TestDIPLoad(t *testing.T) {
+	bank0, bank1 := uint8(0), uint8(0)
+	d := NewDIPSwitches(testDIP, &bank0, &bank1)
+	conf := `
+# testing
+coinage free-play
+cabinet  cocktail
+`
+	if err := d.Load(strings.NewReader(conf)); err != nil {
+		t.Fatal(err)
+	}
+	if bank0 != 0x08 {
+		t.Errorf("\n have: %02x \n want: %02x", bank0, 0x08)
+	}
+	if bank1 != 0x00 {
+		t.Errorf("\n have: %02x \n want: %02x", bank1, 0x00)
+	}
+}
+
+func TestDIPLoadError(t *testing.T) {
+	bank0, bank1 := uint8(0), uint8(0)
+	d := NewDIPSwitches(testDIP, &bank0, &bank1)
+	err := d.Load(strings.NewReader("coinage\n"))
+	if err == nil || !strings.HasPrefix(err.Error(), "line 1:") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -54,12 +54,27 @@ func (c *CPU) loadIndirectX() uint8 {
 }
 
 func (c *CPU) loadIndirectY() uint8 {
-	c.addrLoad = c.mem.ReadLE(int(c.fetch()))
-	indexed := c.addrLoad + int(c.Y)
-	if c.addrLoad&0xff00 != indexed&0xff00 {
+	base := c.mem.ReadLE(int(c.fetch()))
+	c.addrLoad = base + int(c.Y)
+	if base&0xff00 != c.addrLoad&0xff00 {
 		c.pageCross = true
 	}
-	return c.mem.Read(indexed)
+	return c.mem.Read(c.addrLoad)
+}
+
+// The pointer for (zp) addressing wraps within the zero page
+func (c *CPU) indirectZeroPage() int {
+	zp := c.fetch()
+	return int(c.mem.Read(int(zp))) | int(c.mem.Read(int(zp+1)))<<8
+}
+
+func (c *CPU) loadIndirectZeroPage() uint8 {
+	c.addrLoad = c.indirectZeroPage()
+	return c.mem.Read(c.addrLoad)
+}
+
+func (c *CPU) loadZero() uint8 {
+	return 0
 }
 
 func (c *CPU) loadZeroPage() uint8 {
@@ -99,6 +114,10 @@ func (c *CPU) storeIndirectY(v uint8) {
 	c.mem.Write(addr, v)
 }
 
+func (c *CPU) storeIndirectZeroPage(v uint8) {
+	c.mem.Write(c.indirectZeroPage(), v)
+}
+
 func (c *CPU) storeZeroPage(v uint8) {
 	c.mem.Write(int(c.fetch()), v)
 }
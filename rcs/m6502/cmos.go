@@ -0,0 +1,390 @@
+package m6502
+
+import (
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+// http://6502.org/tutorials/65c02opcodes.html
+
+// Variant selects the instruction set and behavior of the processor.
+type Variant int
+
+const (
+	NMOS6502  Variant = iota // MOS 6502, 6510, and 8502
+	CMOS65C02                // 65C02 without the bit instructions
+	R65C02                   // Rockwell 65C02
+	W65C02                   // WDC 65C02
+)
+
+func (v Variant) String() string {
+	switch v {
+	case NMOS6502:
+		return "6502"
+	case CMOS65C02:
+		return "65c02"
+	case R65C02:
+		return "r65c02"
+	case W65C02:
+		return "w65c02"
+	}
+	return "?"
+}
+
+// Instruction and disassembler tables for each variant. The CMOS variants
+// start with the documented instructions from the NMOS table and replace
+// everything else.
+var (
+	variantOps  = map[Variant]*[256]func(*CPU){}
+	variantDasm = map[Variant]map[uint8]op{}
+)
+
+func init() {
+	variantOps[NMOS6502] = &opcodes
+	variantDasm[NMOS6502] = dasmTable
+
+	variantOps[CMOS65C02] = mergeOps(&opcodes, &cmosOpcodes)
+	variantDasm[CMOS65C02] = mergeDasm(dasmTable, cmosDasmTable)
+
+	variantOps[R65C02] = mergeOps(variantOps[CMOS65C02], &bitOpcodes)
+	variantDasm[R65C02] = mergeDasm(variantDasm[CMOS65C02], bitDasmTable)
+
+	variantOps[W65C02] = mergeOps(variantOps[R65C02], &wdcOpcodes)
+	variantDasm[W65C02] = mergeDasm(variantDasm[R65C02], wdcDasmTable)
+}
+
+func mergeOps(base *[256]func(*CPU), add *[256]func(*CPU)) *[256]func(*CPU) {
+	out := *base
+	for k, v := range add {
+		if v != nil {
+			out[k] = v
+		}
+	}
+	return &out
+}
+
+func mergeDasm(base map[uint8]op, add map[uint8]op) map[uint8]op {
+	out := make(map[uint8]op)
+	for k, v := range base {
+		out[k] = v
+	}
+	for k, v := range add {
+		out[k] = v
+	}
+	return out
+}
+
+// Instructions added on the 65C02 and replacements for the undocumented
+// instructions on the 6502. All other opcodes are no operation but some
+// still read an operand. The instruction tables in opcodes.go are
+// generated from this table and those that follow.
+var cmosDasmTable = map[uint8]op{
+	0x02: op{"nop", immediate},
+	0x03: op{"nop", implied},
+	0x04: op{"tsb", zeroPage},
+	0x07: op{"nop", implied},
+	0x0b: op{"nop", implied},
+	0x0c: op{"tsb", absolute},
+	0x0f: op{"nop", implied},
+
+	0x12: op{"ora", indirectZeroPage},
+	0x13: op{"nop", implied},
+	0x14: op{"trb", zeroPage},
+	0x17: op{"nop", implied},
+	0x1a: op{"inc", accumulator},
+	0x1b: op{"nop", implied},
+	0x1c: op{"trb", absolute},
+	0x1f: op{"nop", implied},
+
+	0x22: op{"nop", immediate},
+	0x23: op{"nop", implied},
+	0x27: op{"nop", implied},
+	0x2b: op{"nop", implied},
+	0x2f: op{"nop", implied},
+
+	0x32: op{"and", indirectZeroPage},
+	0x33: op{"nop", implied},
+	0x34: op{"bit", zeroPageX},
+	0x37: op{"nop", implied},
+	0x3a: op{"dec", accumulator},
+	0x3b: op{"nop", implied},
+	0x3c: op{"bit", absoluteX},
+	0x3f: op{"nop", implied},
+
+	0x42: op{"nop", immediate},
+	0x43: op{"nop", implied},
+	0x44: op{"nop", zeroPage},
+	0x47: op{"nop", implied},
+	0x4b: op{"nop", implied},
+	0x4f: op{"nop", implied},
+
+	0x52: op{"eor", indirectZeroPage},
+	0x53: op{"nop", implied},
+	0x54: op{"nop", zeroPageX},
+	0x57: op{"nop", implied},
+	0x5a: op{"phy", implied},
+	0x5b: op{"nop", implied},
+	0x5c: op{"nop", absolute},
+	0x5f: op{"nop", implied},
+
+	0x62: op{"nop", immediate},
+	0x63: op{"nop", implied},
+	0x64: op{"stz", zeroPage},
+	0x67: op{"nop", implied},
+	0x6b: op{"nop", implied},
+	0x6f: op{"nop", implied},
+
+	0x72: op{"adc", indirectZeroPage},
+	0x73: op{"nop", implied},
+	0x74: op{"stz", zeroPageX},
+	0x77: op{"nop", implied},
+	0x7a: op{"ply", implied},
+	0x7b: op{"nop", implied},
+	0x7c: op{"jmp", absoluteIndirectX},
+	0x7f: op{"nop", implied},
+
+	0x80: op{"bra", relative},
+	0x82: op{"nop", immediate},
+	0x83: op{"nop", implied},
+	0x87: op{"nop", implied},
+	0x89: op{"bit", immediate},
+	0x8b: op{"nop", implied},
+	0x8f: op{"nop", implied},
+
+	0x92: op{"sta", indirectZeroPage},
+	0x93: op{"nop", implied},
+	0x97: op{"nop", implied},
+	0x9b: op{"nop", implied},
+	0x9c: op{"stz", absolute},
+	0x9e: op{"stz", absoluteX},
+	0x9f: op{"nop", implied},
+
+	0xa3: op{"nop", implied},
+	0xa7: op{"nop", implied},
+	0xab: op{"nop", implied},
+	0xaf: op{"nop", implied},
+
+	0xb2: op{"lda", indirectZeroPage},
+	0xb3: op{"nop", implied},
+	0xb7: op{"nop", implied},
+	0xbb: op{"nop", implied},
+	0xbf: op{"nop", implied},
+
+	0xc2: op{"nop", immediate},
+	0xc3: op{"nop", implied},
+	0xc7: op{"nop", implied},
+	0xcb: op{"nop", implied},
+	0xcf: op{"nop", implied},
+
+	0xd2: op{"cmp", indirectZeroPage},
+	0xd3: op{"nop", implied},
+	0xd4: op{"nop", zeroPageX},
+	0xd7: op{"nop", implied},
+	0xda: op{"phx", implied},
+	0xdb: op{"nop", implied},
+	0xdc: op{"nop", absolute},
+	0xdf: op{"nop", implied},
+
+	0xe2: op{"nop", immediate},
+	0xe3: op{"nop", implied},
+	0xe7: op{"nop", implied},
+	0xeb: op{"nop", implied},
+	0xef: op{"nop", implied},
+
+	0xf2: op{"sbc", indirectZeroPage},
+	0xf3: op{"nop", implied},
+	0xf4: op{"nop", zeroPageX},
+	0xf7: op{"nop", implied},
+	0xfa: op{"plx", implied},
+	0xfb: op{"nop", implied},
+	0xfc: op{"nop", absolute},
+	0xff: op{"nop", implied},
+}
+
+// Bit instructions on the Rockwell and WDC parts.
+var bitDasmTable = map[uint8]op{
+	0x07: op{"rmb0", zeroPage},
+	0x0f: op{"bbr0", zeroPageRelative},
+
+	0x17: op{"rmb1", zeroPage},
+	0x1f: op{"bbr1", zeroPageRelative},
+
+	0x27: op{"rmb2", zeroPage},
+	0x2f: op{"bbr2", zeroPageRelative},
+
+	0x37: op{"rmb3", zeroPage},
+	0x3f: op{"bbr3", zeroPageRelative},
+
+	0x47: op{"rmb4", zeroPage},
+	0x4f: op{"bbr4", zeroPageRelative},
+
+	0x57: op{"rmb5", zeroPage},
+	0x5f: op{"bbr5", zeroPageRelative},
+
+	0x67: op{"rmb6", zeroPage},
+	0x6f: op{"bbr6", zeroPageRelative},
+
+	0x77: op{"rmb7", zeroPage},
+	0x7f: op{"bbr7", zeroPageRelative},
+
+	0x87: op{"smb0", zeroPage},
+	0x8f: op{"bbs0", zeroPageRelative},
+
+	0x97: op{"smb1", zeroPage},
+	0x9f: op{"bbs1", zeroPageRelative},
+
+	0xa7: op{"smb2", zeroPage},
+	0xaf: op{"bbs2", zeroPageRelative},
+
+	0xb7: op{"smb3", zeroPage},
+	0xbf: op{"bbs3", zeroPageRelative},
+
+	0xc7: op{"smb4", zeroPage},
+	0xcf: op{"bbs4", zeroPageRelative},
+
+	0xd7: op{"smb5", zeroPage},
+	0xdf: op{"bbs5", zeroPageRelative},
+
+	0xe7: op{"smb6", zeroPage},
+	0xef: op{"bbs6", zeroPageRelative},
+
+	0xf7: op{"smb7", zeroPage},
+	0xff: op{"bbs7", zeroPageRelative},
+}
+
+var wdcDasmTable = map[uint8]op{
+	0xcb: op{"wai", implied},
+	0xdb: op{"stp", implied},
+}
+
+// add binary-coded decimal on the 65C02. The negative and zero flags are
+// valid for the result.
+//
+// http://www.6502.org/tutorials/decimal_mode.html#A
+func adcdCMOS(c *CPU, load rcs.Load8) {
+	in0, in1 := int(c.A), int(load())
+	carry := 0
+	if c.SR&FlagC != 0 {
+		carry = 1
+	}
+
+	lo := in0&0x0f + in1&0x0f + carry
+	if lo >= 0x0a {
+		lo = (lo+0x06)&0x0f + 0x10
+	}
+	out := in0&0xf0 + in1&0xf0 + lo
+	signed := int(int8(in0&0xf0)) + int(int8(in1&0xf0)) + lo
+	if out >= 0xa0 {
+		out += 0x60
+	}
+
+	c.SR &^= FlagN | FlagV | FlagZ | FlagC
+	if out >= 0x100 {
+		c.SR |= FlagC
+	}
+	if signed < -128 || signed > 127 {
+		c.SR |= FlagV
+	}
+	if out&(1<<7) != 0 {
+		c.SR |= FlagN
+	}
+	if out&0xff == 0 {
+		c.SR |= FlagZ
+	}
+	c.A = uint8(out)
+}
+
+// subtract binary-coded decimal on the 65C02. The carry and overflow flags
+// are the same as in binary mode and the negative and zero flags are valid
+// for the result.
+//
+// http://www.6502.org/tutorials/decimal_mode.html#A
+func sbcdCMOS(c *CPU, load rcs.Load8) {
+	in0, in1 := int(c.A), int(load())
+	borrow := 0
+	if c.SR&FlagC == 0 {
+		borrow = 1
+	}
+
+	_, fc, _, fv := rcs.Sub(c.A, uint8(in1), borrow == 1)
+	lo := in0&0x0f - in1&0x0f - borrow
+	out := in0 - in1 - borrow
+	if out < 0 {
+		out -= 0x60
+	}
+	if lo < 0 {
+		out -= 0x06
+	}
+
+	c.SR &^= FlagN | FlagV | FlagZ | FlagC
+	if !fc {
+		c.SR |= FlagC
+	}
+	if fv {
+		c.SR |= FlagV
+	}
+	if out&(1<<7) != 0 {
+		c.SR |= FlagN
+	}
+	if out&0xff == 0 {
+		c.SR |= FlagZ
+	}
+	c.A = uint8(out)
+}
+
+// branch if a bit in zero page is clear
+func bbr(c *CPU, n uint) {
+	in := c.loadZeroPage()
+	branch(c, in&(1<<n) == 0)
+}
+
+// branch if a bit in zero page is set
+func bbs(c *CPU, n uint) {
+	in := c.loadZeroPage()
+	branch(c, in&(1<<n) != 0)
+}
+
+// test bits, immediate. Only the zero flag is changed.
+func bitImmediate(c *CPU, load rcs.Load8) {
+	if c.A&load() == 0 {
+		c.SR |= FlagZ
+	} else {
+		c.SR &^= FlagZ
+	}
+}
+
+// jump indirect, indexed by x
+func jmpIndirectX(c *CPU) {
+	c.pc = uint16(c.mem.ReadLE(c.fetch2()+int(c.X)) - 1)
+}
+
+// reset a bit in zero page
+func rmb(c *CPU, n uint) {
+	c.storeBack(c.loadZeroPage() &^ (1 << n))
+}
+
+// set a bit in zero page
+func smb(c *CPU, n uint) {
+	c.storeBack(c.loadZeroPage() | 1<<n)
+}
+
+// test and reset bits
+func trb(c *CPU, load rcs.Load8) {
+	in := load()
+	if c.A&in == 0 {
+		c.SR |= FlagZ
+	} else {
+		c.SR &^= FlagZ
+	}
+	c.storeBack(in &^ c.A)
+}
+
+// test and set bits
+func tsb(c *CPU, load rcs.Load8) {
+	in := load()
+	if c.A&in == 0 {
+		c.SR |= FlagZ
+	} else {
+		c.SR &^= FlagZ
+	}
+	c.storeBack(in | c.A)
+}
@@ -0,0 +1,358 @@
+package m6502
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/mock"
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+func newTestCMOS(v Variant) *CPU {
+	mock.ResetMemory()
+	cpu := NewVariant(mock.TestMemory, v)
+	cpu.SP = 0xff
+	cpu.SetPC(0x1ff)
+	return cpu
+}
+
+func TestCMOSTables(t *testing.T) {
+	for _, v := range []Variant{CMOS65C02, R65C02, W65C02} {
+		n := 0
+		for _, fn := range variantOps[v] {
+			if fn != nil {
+				n++
+			}
+		}
+		if n != 256 {
+			t.Errorf("%v: have %v opcodes, want 256", v, n)
+		}
+		if len(variantDasm[v]) != 256 {
+			t.Errorf("%v: have %v dasm entries, want 256", v, len(variantDasm[v]))
+		}
+	}
+	// Bit instructions are not on the base 65C02
+	if variantDasm[CMOS65C02][0x07].inst != "nop" {
+		t.Errorf("expected nop for $07 on the 65c02")
+	}
+}
+
+func TestBra(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0x80, 0x10) // bra $0212
+	c.Next()
+	want := 0x0212
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestStzAbsoluteX(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0x9e, 0xab, 0x02) // stz $02ab,x
+	c.mem.Write(0x02ad, 0x44)
+	c.X = 0x02
+	testRunCPU(t, c)
+	want := uint8(0x00)
+	have := c.mem.Read(0x02ad)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+}
+
+func TestTsb(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0x04, 0x34) // tsb $34
+	c.mem.Write(0x34, 0xf0)
+	c.A = 0x0f
+	testRunCPU(t, c)
+	want := uint8(0xff)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagZ | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestTrb(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0x1c, 0xab, 0x02) // trb $02ab
+	c.mem.Write(0x02ab, 0xff)
+	c.A = 0x0f
+	testRunCPU(t, c)
+	want := uint8(0xf0)
+	have := c.mem.Read(0x02ab)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestPhxPly(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0xda, 0x7a) // phx, ply
+	c.X = 0x80
+	testRunCPU(t, c)
+	want := uint8(0x80)
+	have := c.Y
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagN | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestIncA(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0x1a) // inc a
+	c.A = 0xff
+	testRunCPU(t, c)
+	want := uint8(0x00)
+	have := c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagZ | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestLdaIndirectZeroPage(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0xb2, 0xff) // lda ($ff)
+	c.mem.Write(0xff, 0xab)
+	c.mem.Write(0x00, 0x02) // pointer wraps to the start of zero page
+	c.mem.Write(0x02ab, 0x42)
+	testRunCPU(t, c)
+	want := uint8(0x42)
+	have := c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+}
+
+func TestBitImmediate(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0x89, 0xc0) // bit #$c0
+	c.A = 0x01
+	testRunCPU(t, c)
+	// only the zero flag is changed
+	want := FlagZ | Flag5
+	have := c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestJmpIndirectX(t *testing.T) {
+	c := newTestCMOS(CMOS65C02)
+	c.mem.WriteN(0x0200, 0x7c, 0xab, 0x02) // jmp ($02ab,x)
+	c.mem.WriteLE(0x02ad, 0x1234)
+	c.X = 0x02
+	c.Next()
+	want := 0x1234
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestJmpIndirectPageWrap(t *testing.T) {
+	var tests = []struct {
+		variant Variant
+		want    int
+	}{
+		{NMOS6502, 0x5634}, // high byte from the start of the page
+		{CMOS65C02, 0x1234},
+	}
+	for _, test := range tests {
+		t.Run(test.variant.String(), func(t *testing.T) {
+			c := newTestCMOS(test.variant)
+			c.mem.WriteN(0x0200, 0x6c, 0xff, 0x03) // jmp ($03ff)
+			c.mem.Write(0x03ff, 0x34)
+			c.mem.Write(0x0400, 0x12)
+			c.mem.Write(0x0300, 0x56)
+			c.Next()
+			have := c.PC() + c.Offset()
+			if test.want != have {
+				t.Errorf("\n want: %04x \n have: %04x \n", test.want, have)
+			}
+		})
+	}
+}
+
+func TestRmbSmb(t *testing.T) {
+	c := newTestCMOS(R65C02)
+	c.mem.WriteN(0x0200, 0x17, 0x34, 0x87, 0x34) // rmb1 $34, smb0 $34
+	c.mem.Write(0x34, 0x02)
+	testRunCPU(t, c)
+	want := uint8(0x01)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+}
+
+func TestBbs(t *testing.T) {
+	c := newTestCMOS(R65C02)
+	c.mem.WriteN(0x0200, 0xff, 0x34, 0x10) // bbs7 $34,$0213
+	c.mem.Write(0x34, 0x80)
+	c.Next()
+	want := 0x0213
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestBbr(t *testing.T) {
+	c := newTestCMOS(R65C02)
+	c.mem.WriteN(0x0200, 0x7f, 0x34, 0x10) // bbr7 $34,$0213
+	c.mem.Write(0x34, 0x80)
+	c.Next()
+	want := 0x0203
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestWai(t *testing.T) {
+	c := newTestCMOS(W65C02)
+	c.mem.WriteN(0x0200, 0xcb, 0xea) // wai, nop
+	c.mem.WriteLE(0xfffe, 0x1234)
+	c.Next()
+	c.Next()
+	if !c.Wait {
+		t.Fatalf("expected wait")
+	}
+	want := 0x0201
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	c.IRQ = true
+	c.Next()
+	if c.Wait {
+		t.Errorf("expected wait to end")
+	}
+	want = 0x1234
+	have = c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestStp(t *testing.T) {
+	c := newTestCMOS(W65C02)
+	c.mem.WriteN(0x0200, 0xdb) // stp
+	c.Next()
+	c.Next()
+	if !c.Jam {
+		t.Errorf("expected stop")
+	}
+}
+
+func TestCMOSInterruptClearsDecimal(t *testing.T) {
+	var tests = []struct {
+		variant Variant
+		want    uint8
+	}{
+		{NMOS6502, FlagD},
+		{CMOS65C02, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.variant.String(), func(t *testing.T) {
+			c := newTestCMOS(test.variant)
+			c.mem.WriteN(0x0200, 0xea) // nop
+			c.SR = FlagD
+			c.IRQ = true
+			c.Next()
+			have := c.SR & FlagD
+			if test.want != have {
+				flagError(t, test.want, have)
+			}
+		})
+	}
+}
+
+func TestCMOSDecimal(t *testing.T) {
+	var tests = []struct {
+		name   string
+		opcode uint8
+		a      uint8
+		value  uint8
+		sr     uint8
+		wantA  uint8
+		wantSR uint8
+	}{
+		{"adc", 0x69, 0x99, 0x01, FlagD, 0x00, FlagD | FlagZ | FlagC | Flag5},
+		{"adc", 0x69, 0x45, 0x45, FlagD, 0x90, FlagD | FlagN | FlagV | Flag5},
+		{"sbc", 0xe9, 0x00, 0x01, FlagD | FlagC, 0x99, FlagD | FlagN | Flag5},
+		{"sbc", 0xe9, 0x50, 0x50, FlagD | FlagC, 0x00, FlagD | FlagZ | FlagC | Flag5},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("%v %02x %02x", test.name, test.a, test.value)
+		t.Run(name, func(t *testing.T) {
+			c := newTestCMOS(CMOS65C02)
+			c.mem.WriteN(0x0200, test.opcode, test.value)
+			c.A = test.a
+			c.SR = test.sr
+			testRunCPU(t, c)
+			if test.wantA != c.A {
+				t.Errorf("\n want: %02x \n have: %02x \n", test.wantA, c.A)
+			}
+			if test.wantSR != c.SR {
+				flagError(t, test.wantSR, c.SR)
+			}
+		})
+	}
+}
+
+func TestCMOSDisassembler(t *testing.T) {
+	var tests = []struct {
+		variant Variant
+		bytes   []uint8
+		want    string
+	}{
+		{NMOS6502, []uint8{0x80, 0x10}, "$1234:  80 10     nop #$10"},
+		{CMOS65C02, []uint8{0x80, 0x10}, "$1234:  80 10     bra $1246"},
+		{CMOS65C02, []uint8{0x12, 0x56}, "$1234:  12 56     ora ($56)"},
+		{CMOS65C02, []uint8{0x7c, 0x78, 0x56}, "$1234:  7c 78 56  jmp ($5678,x)"},
+		{CMOS65C02, []uint8{0x03}, "$1234:  03        nop"},
+		{CMOS65C02, []uint8{0x8f, 0x56, 0x10}, "$1234:  8f        nop"},
+		{R65C02, []uint8{0x8f, 0x56, 0x10}, "$1234:  8f 56 10  bbs0 $56,$1247"},
+		{R65C02, []uint8{0x0f, 0x56, 0xfd}, "$1234:  0f 56 fd  bbr0 $56,$1234"},
+		{R65C02, []uint8{0xcb}, "$1234:  cb        nop"},
+		{W65C02, []uint8{0xcb}, "$1234:  cb        wai"},
+		{W65C02, []uint8{0xdb}, "$1234:  db        stp"},
+	}
+	for _, test := range tests {
+		name := fmt.Sprintf("%v $%02x", test.variant, test.bytes[0])
+		t.Run(name, func(t *testing.T) {
+			mock.ResetMemory()
+			mem := mock.TestMemory
+			mem.WriteN(0x1234, test.bytes...)
+			d := rcs.NewDisassembler(mem, ReaderFor(test.variant), Formatter())
+			d.SetPC(0x1234)
+			have := d.Next()
+			if test.want != have {
+				t.Errorf("\n want: %v \n have: %v", test.want, have)
+			}
+		})
+	}
+}
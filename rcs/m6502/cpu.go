@@ -11,7 +11,9 @@ import (
 
 const (
 	addrStack = 0x0100 // starting address of the stack
+	addrNMI   = 0xfffa // non-maskable interrupt vector
 	addrReset = 0xfffc // reset vector
+	addrIRQ   = 0xfffe // interrupt request and break vector
 )
 
 // CPU is the MOS Technology 6502 series processor.
@@ -24,17 +26,36 @@ type CPU struct {
 	SP   uint8  // stack pointer
 	SR   uint8  // status register
 
-	IRQ bool // interrupt request
+	Variant Variant
+
+	// IRQ is an interrupt request that is held until the CPU takes the
+	// interrupt.
+	//
+	// Deprecated: Use SetIRQ to hold the line for a source and release it
+	// when the device is serviced.
+	IRQ bool
+
+	// Interrupt and reset requests that are cleared once seen by the CPU.
+	// Devices that hold a line until serviced should use SetNMI instead.
+	NMI   bool
+	RESET bool
+
+	Halt bool // RDY held low by another device, the CPU is stalled
+	Jam  bool // halted by a jam or stp instruction
+	Wait bool // waiting for an interrupt after a wai instruction
 
 	BreakFunc  func()
 	WatchIRQ   bool
 	WatchBRK   bool
 	WatchStack bool
 
-	mem       *rcs.Memory          // CPU's view into memory
-	ops       map[uint8]func(*CPU) // opcode table
-	addrLoad  int                  // memory address where the last value was loaded from
-	pageCross bool                 // if set, add a one cycle penalty for crossing a page boundary
+	irqLines  uint32           // IRQ held by each source
+	nmiLines  uint32           // NMI held by each source
+	mem       *rcs.Memory      // CPU's view into memory
+	ops       *[256]func(*CPU) // opcode table
+	cmos      bool             // 65C02 behavior
+	addrLoad  int              // memory address where the last value was loaded from
+	pageCross bool             // if set, add a one cycle penalty for crossing a page boundary
 }
 
 const (
@@ -63,53 +84,155 @@ const (
 	FlagN = uint8(1 << 7)
 )
 
-// New creates a new CPU with a view of the provided memory.
+// New creates a new NMOS 6502 with a view of the provided memory.
 func New(mem *rcs.Memory) *CPU {
+	return NewVariant(mem, NMOS6502)
+}
+
+// NewVariant creates a new CPU of the given variant with a view of the
+// provided memory.
+func NewVariant(mem *rcs.Memory, v Variant) *CPU {
 	return &CPU{
-		Name: "cpu",
-		mem:  mem,
-		pc:   uint16(mem.ReadLE(addrReset) - 1), // reset vector
-		ops:  opcodes,
+		Name:    "cpu",
+		Variant: v,
+		mem:     mem,
+		pc:      uint16(mem.ReadLE(addrReset) - 1), // reset vector
+		ops:     variantOps[v],
+		cmos:    v != NMOS6502,
 	}
 }
 
 // Next executes the next instruction.
 func (c *CPU) Next() {
+	if c.RESET {
+		c.RESET = false
+		c.Reset()
+		return
+	}
+	if c.Halt || c.Jam {
+		return
+	}
+	if c.Wait {
+		// An interrupt request ends the wait even when interrupts are
+		// disabled
+		if !c.IRQ && c.irqLines == 0 && !c.NMI {
+			return
+		}
+		c.Wait = false
+	} else if !c.execute() {
+		return
+	}
+
+	if c.NMI {
+		c.NMI = false
+		c.nmiAck()
+		return
+	}
+	if (c.IRQ || c.irqLines != 0) && c.SR&FlagI == 0 {
+		c.IRQ = false
+		c.irqAck(false)
+	}
+}
+
+// SetIRQ sets the state of the interrupt request line for the given
+// source, numbered 0 to 31. The line is low, and an interrupt is
+// requested, while any source is active. Interrupts are requested after
+// each instruction until each source releases the line.
+func (c *CPU) SetIRQ(source int, active bool) {
+	if active {
+		c.irqLines |= 1 << uint(source)
+	} else {
+		c.irqLines &^= 1 << uint(source)
+	}
+}
+
+// SetNMI sets the state of the non-maskable interrupt line for the given
+// source, numbered 0 to 31. An interrupt is only requested when the first
+// source becomes active. The line must be released by all sources before
+// another interrupt can be requested.
+func (c *CPU) SetNMI(source int, active bool) {
+	prev := c.nmiLines
+	if active {
+		c.nmiLines |= 1 << uint(source)
+	} else {
+		c.nmiLines &^= 1 << uint(source)
+	}
+	if prev == 0 && c.nmiLines != 0 {
+		c.NMI = true
+	}
+}
+
+// IRQLine returns true if any source is holding the interrupt request
+// line.
+func (c *CPU) IRQLine() bool {
+	return c.irqLines != 0
+}
+
+// NMILine returns true if any source is holding the non-maskable
+// interrupt line.
+func (c *CPU) NMILine() bool {
+	return c.nmiLines != 0
+}
+
+// Reset puts the CPU in the state found after the reset line is released.
+// The stack pointer is decremented by three as the processor goes through
+// the motions of an interrupt without writing to the stack.
+func (c *CPU) Reset() {
+	c.SP -= 3
+	c.SR |= FlagI | Flag5
+	if c.cmos {
+		c.SR &^= FlagD
+	}
+	c.IRQ = false
+	c.NMI = false
+	c.Jam = false
+	c.Wait = false
+	c.pc = uint16(c.mem.ReadLE(addrReset) - 1)
+}
+
+func (c *CPU) execute() bool {
 	here := uint16(c.PC() + 1)
 	c.pageCross = false
 	opcode := c.fetch()
-	execute, ok := c.ops[opcode]
-	if !ok {
+	execute := c.ops[opcode]
+	if execute == nil {
 		log.Printf("(!) %v: illegal instruction %v, pc %v", c.Name, rcs.X8(opcode), rcs.X16(here))
-		return
+		return false
 	}
 	execute(c)
 	c.SR |= Flag5
 	c.SR &^= FlagB
-
-	if c.IRQ {
-		c.IRQ = false
-		if c.SR&FlagI == 0 {
-			c.irqAck(false)
-		}
-	}
+	return true
 }
 
 // interrupt handler
 func (c *CPU) irqAck(brk bool) {
 	here := uint16(c.pc)
-	// http://www.6502.org/tutorials/6502opcodes.html#RTI
-	// Note that unlike RTS, the return address on the stack is the
-	// actual address rather than the address-1.
 	ret := c.pc + 1
-	vector := uint16(c.mem.ReadLE(0xfffe))
+	vector := uint16(c.mem.ReadLE(addrIRQ))
 	if !brk && c.WatchIRQ {
 		log.Printf("%v: irq, vector %v, return %v", c.Name, rcs.X16(vector), rcs.X16(ret))
 	}
 	if brk && c.WatchBRK {
 		log.Printf("%v: brk, vector %v, pc %v", c.Name, rcs.X16(vector), rcs.X16(here))
 	}
+	c.interrupt(ret, vector, brk)
+}
+
+// non-maskable interrupt handler
+func (c *CPU) nmiAck() {
+	ret := c.pc + 1
+	vector := uint16(c.mem.ReadLE(addrNMI))
+	if c.WatchIRQ {
+		log.Printf("%v: nmi, vector %v, return %v", c.Name, rcs.X16(vector), rcs.X16(ret))
+	}
+	c.interrupt(ret, vector, false)
+}
 
+func (c *CPU) interrupt(ret uint16, vector uint16, brk bool) {
+	// http://www.6502.org/tutorials/6502opcodes.html#RTI
+	// Note that unlike RTS, the return address on the stack is the
+	// actual address rather than the address-1.
 	c.push2(ret)
 	sr := c.SR | Flag5
 	if brk {
@@ -117,6 +240,9 @@ func (c *CPU) irqAck(brk bool) {
 	}
 	c.push(sr)
 	c.SR |= FlagI
+	if c.cmos {
+		c.SR &^= FlagD
+	}
 	c.pc = vector - 1
 }
 
@@ -145,13 +271,14 @@ func (c *CPU) Memory() *rcs.Memory {
 // NewDisassembler creates a disassembler that can handle 6502 machine
 // code.
 func (c *CPU) NewDisassembler() *rcs.Disassembler {
-	dasm := rcs.NewDisassembler(c.mem, Reader, Formatter())
+	dasm := rcs.NewDisassembler(c.mem, ReaderFor(c.Variant), Formatter())
 	return dasm
 }
 
 // String returns the status of the CPU in the form of:
-// 		 pc  sr ac xr yr sp  n v - b d i z c
-// 		1234 20 00 00 00 ff  . . * . . . . .
+//
+//	 pc  sr ac xr yr sp  n v - b d i z c
+//	1234 20 00 00 00 ff  . . * . . . . .
 func (c *CPU) String() string {
 	b := func(v bool) string {
 		if v {
@@ -234,6 +361,13 @@ func (c *CPU) Save(enc *rcs.Encoder) {
 	enc.Encode(c.Y)
 	enc.Encode(c.SP)
 	enc.Encode(c.SR)
+	enc.Encode(c.Jam)
+	enc.Encode(c.Wait)
+	enc.Encode(c.IRQ)
+	enc.Encode(c.NMI)
+	enc.Encode(c.Halt)
+	enc.Encode(c.irqLines)
+	enc.Encode(c.nmiLines)
 }
 
 func (c *CPU) Load(dec *rcs.Decoder) {
@@ -243,4 +377,11 @@ func (c *CPU) Load(dec *rcs.Decoder) {
 	dec.Decode(&c.Y)
 	dec.Decode(&c.SP)
 	dec.Decode(&c.SR)
+	dec.Decode(&c.Jam)
+	dec.Decode(&c.Wait)
+	dec.Decode(&c.IRQ)
+	dec.Decode(&c.NMI)
+	dec.Decode(&c.Halt)
+	dec.Decode(&c.irqLines)
+	dec.Decode(&c.nmiLines)
 }
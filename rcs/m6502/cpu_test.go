@@ -95,3 +95,195 @@ func TestCPUString(t *testing.T) {
 		}
 	}
 }
+
+func TestIRQ(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xea) // nop
+	c.mem.WriteLE(0xfffe, 0x1234)
+	c.IRQ = true
+	c.Next()
+	want := 0x1234
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	if c.IRQ {
+		t.Errorf("expected request to be cleared")
+	}
+	if c.SR&FlagI == 0 {
+		t.Errorf("expected interrupts to be disabled")
+	}
+	// return address and status register with the break flag clear
+	if c.mem.ReadLE(0x1fe) != 0x0201 || c.mem.Read(0x1fd) != Flag5 {
+		t.Errorf("unexpected stack: %04x %02x", c.mem.ReadLE(0x1fe), c.mem.Read(0x1fd))
+	}
+}
+
+func TestIRQMasked(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xea, 0x58, 0xea) // nop, cli, nop
+	c.mem.WriteLE(0xfffe, 0x1234)
+	c.SR = FlagI
+	c.IRQ = true
+	c.Next()
+	want := 0x0201
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Fatalf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	if !c.IRQ {
+		t.Fatalf("expected request to be held while masked")
+	}
+	c.Next()
+	want = 0x1234
+	have = c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	if c.IRQ {
+		t.Errorf("expected request to be cleared")
+	}
+}
+
+func TestIRQLine(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x58, 0xea) // cli, nop
+	c.mem.WriteN(0x1234, 0x58, 0xea) // cli, nop
+	c.mem.WriteLE(0xfffe, 0x1234)
+	c.SR = FlagI
+	c.SetIRQ(0, true)
+	c.SetIRQ(1, true)
+	c.Next()
+	want := 0x1234
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Fatalf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	// still held by source 1 after source 0 is released
+	c.SetIRQ(0, false)
+	c.Next()
+	have = c.PC() + c.Offset()
+	if want != have {
+		t.Fatalf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	c.SetIRQ(1, false)
+	if c.IRQLine() {
+		t.Fatalf("expected line to be released")
+	}
+	c.Next()
+	c.Next()
+	want = 0x1236
+	have = c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestNMI(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xea) // nop
+	c.mem.WriteN(0x1234, 0xea) // nop
+	c.mem.WriteLE(0xfffa, 0x1234)
+	c.SR = FlagI
+	c.SetNMI(0, true)
+	c.Next()
+	want := 0x1234
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Fatalf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	// edge triggered, another source on the line does not interrupt again
+	c.SetNMI(1, true)
+	c.Next()
+	want = 0x1235
+	have = c.PC() + c.Offset()
+	if want != have {
+		t.Fatalf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	c.SetNMI(0, false)
+	c.SetNMI(1, false)
+	c.SetNMI(1, true)
+	if !c.NMI {
+		t.Errorf("expected interrupt after the line is released")
+	}
+}
+
+func TestNMIBeforeIRQ(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xea) // nop
+	c.mem.WriteLE(0xfffa, 0x1234)
+	c.mem.WriteLE(0xfffe, 0x5678)
+	c.SetIRQ(0, true)
+	c.NMI = true
+	c.Next()
+	want := 0x1234
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestReset(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x02) // jam
+	c.mem.WriteLE(0xfffc, 0x1234)
+	c.Next()
+	c.RESET = true
+	c.Next()
+	want := 0x1234
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	if c.Jam || c.RESET {
+		t.Errorf("expected jam and reset to be cleared")
+	}
+	if c.SR&FlagI == 0 {
+		t.Errorf("expected interrupts to be disabled")
+	}
+	if c.SP != 0xfc {
+		t.Errorf("\n want: fc \n have: %02x \n", c.SP)
+	}
+}
+
+func TestHalt(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xea) // nop
+	c.Halt = true
+	c.IRQ = true
+	c.Next()
+	want := 0x0200
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+	if !c.IRQ {
+		t.Errorf("expected request to be held while stalled")
+	}
+	c.IRQ = false
+	c.Halt = false
+	c.Next()
+	want = 0x0201
+	have = c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func BenchmarkNext(b *testing.B) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200,
+		0xa9, 0x00, // lda #$00
+		0x18,       // clc
+		0x69, 0x01, // adc #$01
+		0x85, 0x10, // sta $10
+		0xe8,       // inx
+		0xd0, 0xf8, // bne $0202
+		0x4c, 0x00, 0x02, // jmp $0200
+	)
+	c.SetPC(0x01ff)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Next()
+	}
+}
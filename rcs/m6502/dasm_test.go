@@ -21,7 +21,25 @@ func TestDisassembler(t *testing.T) {
 		bytes []uint8
 		want  string
 	}{
-		{b(0x02, 0x00, 0x00), "$1234:  02        ?02"},
+		{b(0x8b, 0x00, 0x00), "$1234:  8b        ?8b"},
+
+		{b(0x02, 0x00, 0x00), "$1234:  02        jam"},
+		{b(0x1a, 0x00, 0x00), "$1234:  1a        nop"},
+		{b(0x80, 0x56, 0x00), "$1234:  80 56     nop #$56"},
+		{b(0x1c, 0x78, 0x56), "$1234:  1c 78 56  nop $5678,x"},
+		{b(0x03, 0x56, 0x00), "$1234:  03 56     slo ($56,x)"},
+		{b(0x33, 0x56, 0x00), "$1234:  33 56     rla ($56),y"},
+		{b(0x4f, 0x78, 0x56), "$1234:  4f 78 56  sre $5678"},
+		{b(0x7b, 0x78, 0x56), "$1234:  7b 78 56  rra $5678,y"},
+		{b(0x97, 0x56, 0x00), "$1234:  97 56     sax $56,y"},
+		{b(0xbf, 0x78, 0x56), "$1234:  bf 78 56  lax $5678,y"},
+		{b(0xd7, 0x56, 0x00), "$1234:  d7 56     dcp $56,x"},
+		{b(0xff, 0x78, 0x56), "$1234:  ff 78 56  isc $5678,x"},
+		{b(0x0b, 0x56, 0x00), "$1234:  0b 56     anc #$56"},
+		{b(0x4b, 0x56, 0x00), "$1234:  4b 56     alr #$56"},
+		{b(0x6b, 0x56, 0x00), "$1234:  6b 56     arr #$56"},
+		{b(0xcb, 0x56, 0x00), "$1234:  cb 56     sbx #$56"},
+		{b(0xeb, 0x56, 0x00), "$1234:  eb 56     sbc #$56"},
 
 		{b(0x69, 0x56, 0x00), "$1234:  69 56     adc #$56"},
 		{b(0x65, 0x56, 0x00), "$1234:  65 56     adc $56"},
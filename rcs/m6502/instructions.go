@@ -6,6 +6,10 @@ import (
 
 // add with carry
 func adc(cpu *CPU, load rcs.Load8) {
+	if cpu.SR&FlagD != 0 && cpu.cmos {
+		adcdCMOS(cpu, load)
+		return
+	}
 	if cpu.SR&FlagD != 0 {
 		adcd(cpu, load)
 		return
@@ -67,7 +71,7 @@ func and(c *CPU, load rcs.Load8) {
 }
 
 // arithmetic shift left
-func asl(c *CPU, store rcs.Store8, load rcs.Load8) {
+func asl(c *CPU, store rcs.Store8, load rcs.Load8) uint8 {
 	in := load()
 	carryOut := in&(1<<7) != 0
 	out := in << 1
@@ -83,6 +87,7 @@ func asl(c *CPU, store rcs.Store8, load rcs.Load8) {
 		c.SR |= FlagZ
 	}
 	store(out)
+	return out
 }
 
 // test bits
@@ -148,7 +153,7 @@ func cmp(c *CPU, load0 rcs.Load8, load1 rcs.Load8) {
 }
 
 // decrement
-func dec(c *CPU, store rcs.Store8, load rcs.Load8) {
+func dec(c *CPU, store rcs.Store8, load rcs.Load8) uint8 {
 	out := load() - 1
 	c.SR &^= FlagN | FlagZ
 	if out&(1<<7) != 0 {
@@ -158,6 +163,7 @@ func dec(c *CPU, store rcs.Store8, load rcs.Load8) {
 		c.SR |= FlagZ
 	}
 	store(out)
+	return out
 }
 
 // exclusive or
@@ -174,7 +180,7 @@ func eor(c *CPU, load rcs.Load8) {
 }
 
 // increment
-func inc(c *CPU, store rcs.Store8, load rcs.Load8) {
+func inc(c *CPU, store rcs.Store8, load rcs.Load8) uint8 {
 	out := load() + 1
 	c.SR &^= FlagN | FlagZ
 	if out&(1<<7) != 0 {
@@ -184,6 +190,7 @@ func inc(c *CPU, store rcs.Store8, load rcs.Load8) {
 		c.SR |= FlagZ
 	}
 	store(out)
+	return out
 }
 
 // jump
@@ -191,9 +198,16 @@ func jmp(c *CPU) {
 	c.pc = uint16(c.fetch2() - 1)
 }
 
-// jump indirect
+// jump indirect. On the NMOS 6502, the high byte of the target is read
+// from the start of the same page when the pointer is at the end of a page.
 func jmpIndirect(c *CPU) {
-	c.pc = uint16(c.mem.ReadLE(c.fetch2()) - 1)
+	ptr := c.fetch2()
+	hi := ptr + 1
+	if !c.cmos && ptr&0xff == 0xff {
+		hi = ptr & 0xff00
+	}
+	target := int(c.mem.Read(ptr)) | int(c.mem.Read(hi))<<8
+	c.pc = uint16(target - 1)
 }
 
 // jump to subroutine
@@ -217,7 +231,7 @@ func ld(c *CPU, store rcs.Store8, load rcs.Load8) {
 }
 
 // logical shift right
-func lsr(c *CPU, store rcs.Store8, load rcs.Load8) {
+func lsr(c *CPU, store rcs.Store8, load rcs.Load8) uint8 {
 	in := load()
 	carryOut := in&(1<<0) != 0
 	out := in >> 1
@@ -233,6 +247,7 @@ func lsr(c *CPU, store rcs.Store8, load rcs.Load8) {
 		c.SR |= FlagZ
 	}
 	store(out)
+	return out
 }
 
 // logical or
@@ -276,7 +291,7 @@ func plp(c *CPU) {
 }
 
 // rotate left
-func rol(c *CPU, store rcs.Store8, load rcs.Load8) {
+func rol(c *CPU, store rcs.Store8, load rcs.Load8) uint8 {
 	in := load()
 	carryOut := in&(1<<7) != 0
 	out := in << 1
@@ -295,10 +310,11 @@ func rol(c *CPU, store rcs.Store8, load rcs.Load8) {
 		c.SR |= FlagZ
 	}
 	store(out)
+	return out
 }
 
 // rotate right
-func ror(c *CPU, store rcs.Store8, load rcs.Load8) {
+func ror(c *CPU, store rcs.Store8, load rcs.Load8) uint8 {
 	in := load()
 	carryOut := in&(1<<0) != 0
 	out := in >> 1
@@ -317,6 +333,7 @@ func ror(c *CPU, store rcs.Store8, load rcs.Load8) {
 		c.SR |= FlagZ
 	}
 	store(out)
+	return out
 }
 
 // return from interrupt
@@ -330,6 +347,10 @@ func rti(c *CPU) {
 
 // subtract with carry
 func sbc(c *CPU, load rcs.Load8) {
+	if c.SR&FlagD != 0 && c.cmos {
+		sbcdCMOS(c, load)
+		return
+	}
 	if c.SR&FlagD != 0 {
 		sbcd(c, load)
 		return
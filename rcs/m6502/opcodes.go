@@ -1,172 +1,425 @@
+// Code generated by gen/m6502/opcodes/opcodes.go. DO NOT EDIT.
+
 package m6502
 
 // http://www.6502.org/tutorials/6502opcodes.html
+// http://www.oxyron.de/html/opcodes02.html
+
+// Instructions are indexed by opcode and are generated from the
+// disassembler tables. Entries for opcodes that are not implemented are
+// nil.
 
-var opcodes = map[uint8]func(*CPU){
+var opcodes = [256]func(*CPU){
 	0x00: func(c *CPU) { brk(c) },
 	0x01: func(c *CPU) { ora(c, c.loadIndirectX) },
+	0x02: func(c *CPU) { jam(c) },
+	0x03: func(c *CPU) { slo(c, c.storeBack, c.loadIndirectX) },
+	0x04: func(c *CPU) { nop(c, c.loadZeroPage) },
 	0x05: func(c *CPU) { ora(c, c.loadZeroPage) },
 	0x06: func(c *CPU) { asl(c, c.storeBack, c.loadZeroPage) },
-	0x08: func(c *CPU) { php(c) }, // php
+	0x07: func(c *CPU) { slo(c, c.storeBack, c.loadZeroPage) },
+	0x08: func(c *CPU) { php(c) },
 	0x09: func(c *CPU) { ora(c, c.loadImmediate) },
 	0x0a: func(c *CPU) { asl(c, c.storeA, c.loadA) },
+	0x0b: func(c *CPU) { anc(c, c.loadImmediate) },
+	0x0c: func(c *CPU) { nop(c, c.loadAbsolute) },
 	0x0d: func(c *CPU) { ora(c, c.loadAbsolute) },
 	0x0e: func(c *CPU) { asl(c, c.storeBack, c.loadAbsolute) },
+	0x0f: func(c *CPU) { slo(c, c.storeBack, c.loadAbsolute) },
 
 	0x10: func(c *CPU) { branch(c, c.SR&FlagN == 0) }, // bpl
 	0x11: func(c *CPU) { ora(c, c.loadIndirectY) },
+	0x12: func(c *CPU) { jam(c) },
+	0x13: func(c *CPU) { slo(c, c.storeBack, c.loadIndirectY) },
+	0x14: func(c *CPU) { nop(c, c.loadZeroPageX) },
 	0x15: func(c *CPU) { ora(c, c.loadZeroPageX) },
 	0x16: func(c *CPU) { asl(c, c.storeBack, c.loadZeroPageX) },
+	0x17: func(c *CPU) { slo(c, c.storeBack, c.loadZeroPageX) },
 	0x18: func(c *CPU) { c.SR &^= FlagC }, // clc
 	0x19: func(c *CPU) { ora(c, c.loadAbsoluteY) },
+	0x1a: func(c *CPU) {}, // nop
+	0x1b: func(c *CPU) { slo(c, c.storeBack, c.loadAbsoluteY) },
+	0x1c: func(c *CPU) { nop(c, c.loadAbsoluteX) },
 	0x1d: func(c *CPU) { ora(c, c.loadAbsoluteX) },
 	0x1e: func(c *CPU) { asl(c, c.storeBack, c.loadAbsoluteX) },
+	0x1f: func(c *CPU) { slo(c, c.storeBack, c.loadAbsoluteX) },
 
 	0x20: func(c *CPU) { jsr(c) },
 	0x21: func(c *CPU) { and(c, c.loadIndirectX) },
+	0x22: func(c *CPU) { jam(c) },
+	0x23: func(c *CPU) { rla(c, c.storeBack, c.loadIndirectX) },
 	0x24: func(c *CPU) { bit(c, c.loadZeroPage) },
 	0x25: func(c *CPU) { and(c, c.loadZeroPage) },
 	0x26: func(c *CPU) { rol(c, c.storeBack, c.loadZeroPage) },
+	0x27: func(c *CPU) { rla(c, c.storeBack, c.loadZeroPage) },
 	0x28: func(c *CPU) { plp(c) },
 	0x29: func(c *CPU) { and(c, c.loadImmediate) },
 	0x2a: func(c *CPU) { rol(c, c.storeA, c.loadA) },
+	0x2b: func(c *CPU) { anc(c, c.loadImmediate) },
 	0x2c: func(c *CPU) { bit(c, c.loadAbsolute) },
 	0x2d: func(c *CPU) { and(c, c.loadAbsolute) },
 	0x2e: func(c *CPU) { rol(c, c.storeBack, c.loadAbsolute) },
+	0x2f: func(c *CPU) { rla(c, c.storeBack, c.loadAbsolute) },
 
 	0x30: func(c *CPU) { branch(c, c.SR&FlagN != 0) }, // bmi
 	0x31: func(c *CPU) { and(c, c.loadIndirectY) },
+	0x32: func(c *CPU) { jam(c) },
+	0x33: func(c *CPU) { rla(c, c.storeBack, c.loadIndirectY) },
+	0x34: func(c *CPU) { nop(c, c.loadZeroPageX) },
 	0x35: func(c *CPU) { and(c, c.loadZeroPageX) },
 	0x36: func(c *CPU) { rol(c, c.storeBack, c.loadZeroPageX) },
+	0x37: func(c *CPU) { rla(c, c.storeBack, c.loadZeroPageX) },
 	0x38: func(c *CPU) { c.SR |= FlagC }, // sec
 	0x39: func(c *CPU) { and(c, c.loadAbsoluteY) },
+	0x3a: func(c *CPU) {}, // nop
+	0x3b: func(c *CPU) { rla(c, c.storeBack, c.loadAbsoluteY) },
+	0x3c: func(c *CPU) { nop(c, c.loadAbsoluteX) },
 	0x3d: func(c *CPU) { and(c, c.loadAbsoluteX) },
 	0x3e: func(c *CPU) { rol(c, c.storeBack, c.loadAbsoluteX) },
+	0x3f: func(c *CPU) { rla(c, c.storeBack, c.loadAbsoluteX) },
 
 	0x40: func(c *CPU) { rti(c) },
 	0x41: func(c *CPU) { eor(c, c.loadIndirectX) },
+	0x42: func(c *CPU) { jam(c) },
+	0x43: func(c *CPU) { sre(c, c.storeBack, c.loadIndirectX) },
+	0x44: func(c *CPU) { nop(c, c.loadZeroPage) },
 	0x45: func(c *CPU) { eor(c, c.loadZeroPage) },
 	0x46: func(c *CPU) { lsr(c, c.storeBack, c.loadZeroPage) },
+	0x47: func(c *CPU) { sre(c, c.storeBack, c.loadZeroPage) },
 	0x48: func(c *CPU) { c.push(c.A) }, // pha
 	0x49: func(c *CPU) { eor(c, c.loadImmediate) },
 	0x4a: func(c *CPU) { lsr(c, c.storeA, c.loadA) },
+	0x4b: func(c *CPU) { alr(c, c.loadImmediate) },
 	0x4c: func(c *CPU) { jmp(c) },
 	0x4d: func(c *CPU) { eor(c, c.loadAbsolute) },
 	0x4e: func(c *CPU) { lsr(c, c.storeBack, c.loadAbsolute) },
+	0x4f: func(c *CPU) { sre(c, c.storeBack, c.loadAbsolute) },
 
 	0x50: func(c *CPU) { branch(c, c.SR&FlagV == 0) }, // bvc
 	0x51: func(c *CPU) { eor(c, c.loadIndirectY) },
+	0x52: func(c *CPU) { jam(c) },
+	0x53: func(c *CPU) { sre(c, c.storeBack, c.loadIndirectY) },
+	0x54: func(c *CPU) { nop(c, c.loadZeroPageX) },
 	0x55: func(c *CPU) { eor(c, c.loadZeroPageX) },
 	0x56: func(c *CPU) { lsr(c, c.storeBack, c.loadZeroPageX) },
+	0x57: func(c *CPU) { sre(c, c.storeBack, c.loadZeroPageX) },
 	0x58: func(c *CPU) { c.SR &^= FlagI }, // cli
 	0x59: func(c *CPU) { eor(c, c.loadAbsoluteY) },
+	0x5a: func(c *CPU) {}, // nop
+	0x5b: func(c *CPU) { sre(c, c.storeBack, c.loadAbsoluteY) },
+	0x5c: func(c *CPU) { nop(c, c.loadAbsoluteX) },
 	0x5d: func(c *CPU) { eor(c, c.loadAbsoluteX) },
 	0x5e: func(c *CPU) { lsr(c, c.storeBack, c.loadAbsoluteX) },
+	0x5f: func(c *CPU) { sre(c, c.storeBack, c.loadAbsoluteX) },
 
 	0x60: func(c *CPU) { c.pc = c.pull2() }, // rts
 	0x61: func(c *CPU) { adc(c, c.loadIndirectX) },
+	0x62: func(c *CPU) { jam(c) },
+	0x63: func(c *CPU) { rra(c, c.storeBack, c.loadIndirectX) },
+	0x64: func(c *CPU) { nop(c, c.loadZeroPage) },
 	0x65: func(c *CPU) { adc(c, c.loadZeroPage) },
 	0x66: func(c *CPU) { ror(c, c.storeBack, c.loadZeroPage) },
+	0x67: func(c *CPU) { rra(c, c.storeBack, c.loadZeroPage) },
 	0x68: func(c *CPU) { pla(c) },
 	0x69: func(c *CPU) { adc(c, c.loadImmediate) },
 	0x6a: func(c *CPU) { ror(c, c.storeA, c.loadA) },
-	0x6c: func(c *CPU) { jmpIndirect(c) },
+	0x6b: func(c *CPU) { arr(c, c.loadImmediate) },
+	0x6c: func(c *CPU) { jmpIndirect(c) }, // jmp
 	0x6d: func(c *CPU) { adc(c, c.loadAbsolute) },
 	0x6e: func(c *CPU) { ror(c, c.storeBack, c.loadAbsolute) },
+	0x6f: func(c *CPU) { rra(c, c.storeBack, c.loadAbsolute) },
 
 	0x70: func(c *CPU) { branch(c, c.SR&FlagV != 0) }, // bvs
 	0x71: func(c *CPU) { adc(c, c.loadIndirectY) },
+	0x72: func(c *CPU) { jam(c) },
+	0x73: func(c *CPU) { rra(c, c.storeBack, c.loadIndirectY) },
+	0x74: func(c *CPU) { nop(c, c.loadZeroPageX) },
 	0x75: func(c *CPU) { adc(c, c.loadZeroPageX) },
 	0x76: func(c *CPU) { ror(c, c.storeBack, c.loadZeroPageX) },
+	0x77: func(c *CPU) { rra(c, c.storeBack, c.loadZeroPageX) },
 	0x78: func(c *CPU) { c.SR |= FlagI }, // sei
 	0x79: func(c *CPU) { adc(c, c.loadAbsoluteY) },
+	0x7a: func(c *CPU) {}, // nop
+	0x7b: func(c *CPU) { rra(c, c.storeBack, c.loadAbsoluteY) },
+	0x7c: func(c *CPU) { nop(c, c.loadAbsoluteX) },
 	0x7d: func(c *CPU) { adc(c, c.loadAbsoluteX) },
 	0x7e: func(c *CPU) { ror(c, c.storeBack, c.loadAbsoluteX) },
+	0x7f: func(c *CPU) { rra(c, c.storeBack, c.loadAbsoluteX) },
+
+	0x80: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x81: func(c *CPU) { st(c, c.storeIndirectX, c.loadA) }, // sta
+	0x82: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x83: func(c *CPU) { sax(c, c.storeIndirectX) },
+	0x84: func(c *CPU) { st(c, c.storeZeroPage, c.loadY) }, // sty
+	0x85: func(c *CPU) { st(c, c.storeZeroPage, c.loadA) }, // sta
+	0x86: func(c *CPU) { st(c, c.storeZeroPage, c.loadX) }, // stx
+	0x87: func(c *CPU) { sax(c, c.storeZeroPage) },
+	0x88: func(c *CPU) { dec(c, c.storeY, c.loadY) }, // dey
+	0x89: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x8a: func(c *CPU) { ld(c, c.storeA, c.loadX) },        // txa
+	0x8c: func(c *CPU) { st(c, c.storeAbsolute, c.loadY) }, // sty
+	0x8d: func(c *CPU) { st(c, c.storeAbsolute, c.loadA) }, // sta
+	0x8e: func(c *CPU) { st(c, c.storeAbsolute, c.loadX) }, // stx
+	0x8f: func(c *CPU) { sax(c, c.storeAbsolute) },
+
+	0x90: func(c *CPU) { branch(c, c.SR&FlagC == 0) },       // bcc
+	0x91: func(c *CPU) { st(c, c.storeIndirectY, c.loadA) }, // sta
+	0x92: func(c *CPU) { jam(c) },
+	0x94: func(c *CPU) { st(c, c.storeZeroPageX, c.loadY) }, // sty
+	0x95: func(c *CPU) { st(c, c.storeZeroPageX, c.loadA) }, // sta
+	0x96: func(c *CPU) { st(c, c.storeZeroPageY, c.loadX) }, // stx
+	0x97: func(c *CPU) { sax(c, c.storeZeroPageY) },
+	0x98: func(c *CPU) { ld(c, c.storeA, c.loadY) },         // tya
+	0x99: func(c *CPU) { st(c, c.storeAbsoluteY, c.loadA) }, // sta
+	0x9a: func(c *CPU) { c.storeSP(c.loadX()) },             // txs: does not set NZ
+	0x9d: func(c *CPU) { st(c, c.storeAbsoluteX, c.loadA) }, // sta
 
-	0x81: func(c *CPU) { st(c, c.storeIndirectX, c.loadA) },
-	0x84: func(c *CPU) { st(c, c.storeZeroPage, c.loadY) },
-	0x85: func(c *CPU) { st(c, c.storeZeroPage, c.loadA) },
-	0x86: func(c *CPU) { st(c, c.storeZeroPage, c.loadX) },
-	0x88: func(c *CPU) { dec(c, c.storeY, c.loadY) },
-	0x8a: func(c *CPU) { ld(c, c.storeA, c.loadX) }, // txa
-	0x8c: func(c *CPU) { st(c, c.storeAbsolute, c.loadY) },
-	0x8d: func(c *CPU) { st(c, c.storeAbsolute, c.loadA) },
-	0x8e: func(c *CPU) { st(c, c.storeAbsolute, c.loadX) },
-
-	0x90: func(c *CPU) { branch(c, c.SR&FlagC == 0) }, // bcc
-	0x91: func(c *CPU) { st(c, c.storeIndirectY, c.loadA) },
-	0x94: func(c *CPU) { st(c, c.storeZeroPageX, c.loadY) },
-	0x95: func(c *CPU) { st(c, c.storeZeroPageX, c.loadA) },
-	0x96: func(c *CPU) { st(c, c.storeZeroPageY, c.loadX) },
-	0x98: func(c *CPU) { ld(c, c.storeA, c.loadY) }, // tya
-	0x99: func(c *CPU) { st(c, c.storeAbsoluteY, c.loadA) },
-	0x9a: func(c *CPU) { c.storeSP(c.loadX()) }, // txs: does not set NZ
-	0x9d: func(c *CPU) { st(c, c.storeAbsoluteX, c.loadA) },
-
-	0xa0: func(c *CPU) { ld(c, c.storeY, c.loadImmediate) },
-	0xa1: func(c *CPU) { ld(c, c.storeA, c.loadIndirectX) },
-	0xa2: func(c *CPU) { ld(c, c.storeX, c.loadImmediate) },
-	0xa4: func(c *CPU) { ld(c, c.storeY, c.loadZeroPage) },
-	0xa5: func(c *CPU) { ld(c, c.storeA, c.loadZeroPage) },
-	0xa6: func(c *CPU) { ld(c, c.storeX, c.loadZeroPage) },
-	0xa8: func(c *CPU) { ld(c, c.storeY, c.loadA) }, // tay
-	0xa9: func(c *CPU) { ld(c, c.storeA, c.loadImmediate) },
-	0xaa: func(c *CPU) { ld(c, c.storeX, c.loadA) }, // tax
-	0xac: func(c *CPU) { ld(c, c.storeY, c.loadAbsolute) },
-	0xad: func(c *CPU) { ld(c, c.storeA, c.loadAbsolute) },
-	0xae: func(c *CPU) { ld(c, c.storeX, c.loadAbsolute) },
-
-	0xb0: func(c *CPU) { branch(c, c.SR&FlagC != 0) }, // bcs
-	0xb1: func(c *CPU) { ld(c, c.storeA, c.loadIndirectY) },
-	0xb4: func(c *CPU) { ld(c, c.storeY, c.loadZeroPageX) },
-	0xb5: func(c *CPU) { ld(c, c.storeA, c.loadZeroPageX) },
-	0xb6: func(c *CPU) { ld(c, c.storeX, c.loadZeroPageY) },
-	0xb8: func(c *CPU) { c.SR &^= FlagV }, // clv
-	0xb9: func(c *CPU) { ld(c, c.storeA, c.loadAbsoluteY) },
-	0xba: func(c *CPU) { ld(c, c.storeX, c.loadSP) }, // tsx
-	0xbc: func(c *CPU) { ld(c, c.storeY, c.loadAbsoluteX) },
-	0xbd: func(c *CPU) { ld(c, c.storeA, c.loadAbsoluteX) },
-	0xbe: func(c *CPU) { ld(c, c.storeX, c.loadAbsoluteY) },
-
-	0xc0: func(c *CPU) { cmp(c, c.loadY, c.loadImmediate) },
+	0xa0: func(c *CPU) { ld(c, c.storeY, c.loadImmediate) }, // ldy
+	0xa1: func(c *CPU) { ld(c, c.storeA, c.loadIndirectX) }, // lda
+	0xa2: func(c *CPU) { ld(c, c.storeX, c.loadImmediate) }, // ldx
+	0xa3: func(c *CPU) { lax(c, c.loadIndirectX) },
+	0xa4: func(c *CPU) { ld(c, c.storeY, c.loadZeroPage) }, // ldy
+	0xa5: func(c *CPU) { ld(c, c.storeA, c.loadZeroPage) }, // lda
+	0xa6: func(c *CPU) { ld(c, c.storeX, c.loadZeroPage) }, // ldx
+	0xa7: func(c *CPU) { lax(c, c.loadZeroPage) },
+	0xa8: func(c *CPU) { ld(c, c.storeY, c.loadA) },         // tay
+	0xa9: func(c *CPU) { ld(c, c.storeA, c.loadImmediate) }, // lda
+	0xaa: func(c *CPU) { ld(c, c.storeX, c.loadA) },         // tax
+	0xac: func(c *CPU) { ld(c, c.storeY, c.loadAbsolute) },  // ldy
+	0xad: func(c *CPU) { ld(c, c.storeA, c.loadAbsolute) },  // lda
+	0xae: func(c *CPU) { ld(c, c.storeX, c.loadAbsolute) },  // ldx
+	0xaf: func(c *CPU) { lax(c, c.loadAbsolute) },
+
+	0xb0: func(c *CPU) { branch(c, c.SR&FlagC != 0) },       // bcs
+	0xb1: func(c *CPU) { ld(c, c.storeA, c.loadIndirectY) }, // lda
+	0xb2: func(c *CPU) { jam(c) },
+	0xb3: func(c *CPU) { lax(c, c.loadIndirectY) },
+	0xb4: func(c *CPU) { ld(c, c.storeY, c.loadZeroPageX) }, // ldy
+	0xb5: func(c *CPU) { ld(c, c.storeA, c.loadZeroPageX) }, // lda
+	0xb6: func(c *CPU) { ld(c, c.storeX, c.loadZeroPageY) }, // ldx
+	0xb7: func(c *CPU) { lax(c, c.loadZeroPageY) },
+	0xb8: func(c *CPU) { c.SR &^= FlagV },                   // clv
+	0xb9: func(c *CPU) { ld(c, c.storeA, c.loadAbsoluteY) }, // lda
+	0xba: func(c *CPU) { ld(c, c.storeX, c.loadSP) },        // tsx
+	0xbc: func(c *CPU) { ld(c, c.storeY, c.loadAbsoluteX) }, // ldy
+	0xbd: func(c *CPU) { ld(c, c.storeA, c.loadAbsoluteX) }, // lda
+	0xbe: func(c *CPU) { ld(c, c.storeX, c.loadAbsoluteY) }, // ldx
+	0xbf: func(c *CPU) { lax(c, c.loadAbsoluteY) },
+
+	0xc0: func(c *CPU) { cmp(c, c.loadY, c.loadImmediate) }, // cpy
 	0xc1: func(c *CPU) { cmp(c, c.loadA, c.loadIndirectX) },
-	0xc4: func(c *CPU) { cmp(c, c.loadY, c.loadZeroPage) },
+	0xc2: func(c *CPU) { nop(c, c.loadImmediate) },
+	0xc3: func(c *CPU) { dcp(c, c.storeBack, c.loadIndirectX) },
+	0xc4: func(c *CPU) { cmp(c, c.loadY, c.loadZeroPage) }, // cpy
 	0xc5: func(c *CPU) { cmp(c, c.loadA, c.loadZeroPage) },
 	0xc6: func(c *CPU) { dec(c, c.storeBack, c.loadZeroPage) },
-	0xc8: func(c *CPU) { inc(c, c.storeY, c.loadY) },
+	0xc7: func(c *CPU) { dcp(c, c.storeBack, c.loadZeroPage) },
+	0xc8: func(c *CPU) { inc(c, c.storeY, c.loadY) }, // iny
 	0xc9: func(c *CPU) { cmp(c, c.loadA, c.loadImmediate) },
-	0xca: func(c *CPU) { dec(c, c.storeX, c.loadX) },
-	0xcc: func(c *CPU) { cmp(c, c.loadY, c.loadAbsolute) },
+	0xca: func(c *CPU) { dec(c, c.storeX, c.loadX) }, // dex
+	0xcb: func(c *CPU) { sbx(c, c.loadImmediate) },
+	0xcc: func(c *CPU) { cmp(c, c.loadY, c.loadAbsolute) }, // cpy
 	0xcd: func(c *CPU) { cmp(c, c.loadA, c.loadAbsolute) },
 	0xce: func(c *CPU) { dec(c, c.storeBack, c.loadAbsolute) },
+	0xcf: func(c *CPU) { dcp(c, c.storeBack, c.loadAbsolute) },
 
 	0xd0: func(c *CPU) { branch(c, c.SR&FlagZ == 0) }, // bne
 	0xd1: func(c *CPU) { cmp(c, c.loadA, c.loadIndirectY) },
+	0xd2: func(c *CPU) { jam(c) },
+	0xd3: func(c *CPU) { dcp(c, c.storeBack, c.loadIndirectY) },
+	0xd4: func(c *CPU) { nop(c, c.loadZeroPageX) },
 	0xd5: func(c *CPU) { cmp(c, c.loadA, c.loadZeroPageX) },
 	0xd6: func(c *CPU) { dec(c, c.storeBack, c.loadZeroPageX) },
+	0xd7: func(c *CPU) { dcp(c, c.storeBack, c.loadZeroPageX) },
 	0xd8: func(c *CPU) { c.SR &^= FlagD }, // cld
 	0xd9: func(c *CPU) { cmp(c, c.loadA, c.loadAbsoluteY) },
+	0xda: func(c *CPU) {}, // nop
+	0xdb: func(c *CPU) { dcp(c, c.storeBack, c.loadAbsoluteY) },
+	0xdc: func(c *CPU) { nop(c, c.loadAbsoluteX) },
 	0xdd: func(c *CPU) { cmp(c, c.loadA, c.loadAbsoluteX) },
 	0xde: func(c *CPU) { dec(c, c.storeBack, c.loadAbsoluteX) },
+	0xdf: func(c *CPU) { dcp(c, c.storeBack, c.loadAbsoluteX) },
 
-	0xe0: func(c *CPU) { cmp(c, c.loadX, c.loadImmediate) },
+	0xe0: func(c *CPU) { cmp(c, c.loadX, c.loadImmediate) }, // cpx
 	0xe1: func(c *CPU) { sbc(c, c.loadIndirectX) },
-	0xe4: func(c *CPU) { cmp(c, c.loadX, c.loadZeroPage) },
+	0xe2: func(c *CPU) { nop(c, c.loadImmediate) },
+	0xe3: func(c *CPU) { isc(c, c.storeBack, c.loadIndirectX) },
+	0xe4: func(c *CPU) { cmp(c, c.loadX, c.loadZeroPage) }, // cpx
 	0xe5: func(c *CPU) { sbc(c, c.loadZeroPage) },
 	0xe6: func(c *CPU) { inc(c, c.storeBack, c.loadZeroPage) },
-	0xe8: func(c *CPU) { inc(c, c.storeX, c.loadX) },
+	0xe7: func(c *CPU) { isc(c, c.storeBack, c.loadZeroPage) },
+	0xe8: func(c *CPU) { inc(c, c.storeX, c.loadX) }, // inx
 	0xe9: func(c *CPU) { sbc(c, c.loadImmediate) },
 	0xea: func(c *CPU) {}, // nop
-	0xec: func(c *CPU) { cmp(c, c.loadX, c.loadAbsolute) },
+	0xeb: func(c *CPU) { sbc(c, c.loadImmediate) },
+	0xec: func(c *CPU) { cmp(c, c.loadX, c.loadAbsolute) }, // cpx
 	0xed: func(c *CPU) { sbc(c, c.loadAbsolute) },
 	0xee: func(c *CPU) { inc(c, c.storeBack, c.loadAbsolute) },
+	0xef: func(c *CPU) { isc(c, c.storeBack, c.loadAbsolute) },
 
 	0xf0: func(c *CPU) { branch(c, c.SR&FlagZ != 0) }, // beq
 	0xf1: func(c *CPU) { sbc(c, c.loadIndirectY) },
+	0xf2: func(c *CPU) { jam(c) },
+	0xf3: func(c *CPU) { isc(c, c.storeBack, c.loadIndirectY) },
+	0xf4: func(c *CPU) { nop(c, c.loadZeroPageX) },
 	0xf5: func(c *CPU) { sbc(c, c.loadZeroPageX) },
 	0xf6: func(c *CPU) { inc(c, c.storeBack, c.loadZeroPageX) },
+	0xf7: func(c *CPU) { isc(c, c.storeBack, c.loadZeroPageX) },
 	0xf8: func(c *CPU) { c.SR |= FlagD }, // sed
 	0xf9: func(c *CPU) { sbc(c, c.loadAbsoluteY) },
+	0xfa: func(c *CPU) {}, // nop
+	0xfb: func(c *CPU) { isc(c, c.storeBack, c.loadAbsoluteY) },
+	0xfc: func(c *CPU) { nop(c, c.loadAbsoluteX) },
 	0xfd: func(c *CPU) { sbc(c, c.loadAbsoluteX) },
 	0xfe: func(c *CPU) { inc(c, c.storeBack, c.loadAbsoluteX) },
+	0xff: func(c *CPU) { isc(c, c.storeBack, c.loadAbsoluteX) },
+}
+
+var cmosOpcodes = [256]func(*CPU){
+	0x02: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x03: func(c *CPU) {}, // nop
+	0x04: func(c *CPU) { tsb(c, c.loadZeroPage) },
+	0x07: func(c *CPU) {}, // nop
+	0x0b: func(c *CPU) {}, // nop
+	0x0c: func(c *CPU) { tsb(c, c.loadAbsolute) },
+	0x0f: func(c *CPU) {}, // nop
+	0x12: func(c *CPU) { ora(c, c.loadIndirectZeroPage) },
+	0x13: func(c *CPU) {}, // nop
+	0x14: func(c *CPU) { trb(c, c.loadZeroPage) },
+	0x17: func(c *CPU) {}, // nop
+	0x1a: func(c *CPU) { inc(c, c.storeA, c.loadA) },
+	0x1b: func(c *CPU) {}, // nop
+	0x1c: func(c *CPU) { trb(c, c.loadAbsolute) },
+	0x1f: func(c *CPU) {}, // nop
+	0x22: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x23: func(c *CPU) {}, // nop
+	0x27: func(c *CPU) {}, // nop
+	0x2b: func(c *CPU) {}, // nop
+	0x2f: func(c *CPU) {}, // nop
+	0x32: func(c *CPU) { and(c, c.loadIndirectZeroPage) },
+	0x33: func(c *CPU) {}, // nop
+	0x34: func(c *CPU) { bit(c, c.loadZeroPageX) },
+	0x37: func(c *CPU) {}, // nop
+	0x3a: func(c *CPU) { dec(c, c.storeA, c.loadA) },
+	0x3b: func(c *CPU) {}, // nop
+	0x3c: func(c *CPU) { bit(c, c.loadAbsoluteX) },
+	0x3f: func(c *CPU) {}, // nop
+	0x42: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x43: func(c *CPU) {}, // nop
+	0x44: func(c *CPU) { nop(c, c.loadZeroPage) },
+	0x47: func(c *CPU) {}, // nop
+	0x4b: func(c *CPU) {}, // nop
+	0x4f: func(c *CPU) {}, // nop
+	0x52: func(c *CPU) { eor(c, c.loadIndirectZeroPage) },
+	0x53: func(c *CPU) {}, // nop
+	0x54: func(c *CPU) { nop(c, c.loadZeroPageX) },
+	0x57: func(c *CPU) {},              // nop
+	0x5a: func(c *CPU) { c.push(c.Y) }, // phy
+	0x5b: func(c *CPU) {},              // nop
+	0x5c: func(c *CPU) { nop(c, c.loadAbsolute) },
+	0x5f: func(c *CPU) {}, // nop
+	0x62: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x63: func(c *CPU) {},                                     // nop
+	0x64: func(c *CPU) { st(c, c.storeZeroPage, c.loadZero) }, // stz
+	0x67: func(c *CPU) {},                                     // nop
+	0x6b: func(c *CPU) {},                                     // nop
+	0x6f: func(c *CPU) {},                                     // nop
+	0x72: func(c *CPU) { adc(c, c.loadIndirectZeroPage) },
+	0x73: func(c *CPU) {},                                      // nop
+	0x74: func(c *CPU) { st(c, c.storeZeroPageX, c.loadZero) }, // stz
+	0x77: func(c *CPU) {},                                      // nop
+	0x7a: func(c *CPU) { ld(c, c.storeY, c.pull) },             // ply
+	0x7b: func(c *CPU) {},                                      // nop
+	0x7c: func(c *CPU) { jmpIndirectX(c) },                     // jmp
+	0x7f: func(c *CPU) {},                                      // nop
+
+	0x80: func(c *CPU) { branch(c, true) }, // bra
+	0x82: func(c *CPU) { nop(c, c.loadImmediate) },
+	0x83: func(c *CPU) {},                                          // nop
+	0x87: func(c *CPU) {},                                          // nop
+	0x89: func(c *CPU) { bitImmediate(c, c.loadImmediate) },        // bit
+	0x8b: func(c *CPU) {},                                          // nop
+	0x8f: func(c *CPU) {},                                          // nop
+	0x92: func(c *CPU) { st(c, c.storeIndirectZeroPage, c.loadA) }, // sta
+	0x93: func(c *CPU) {},                                          // nop
+	0x97: func(c *CPU) {},                                          // nop
+	0x9b: func(c *CPU) {},                                          // nop
+	0x9c: func(c *CPU) { st(c, c.storeAbsolute, c.loadZero) },      // stz
+	0x9e: func(c *CPU) { st(c, c.storeAbsoluteX, c.loadZero) },     // stz
+	0x9f: func(c *CPU) {},                                          // nop
+	0xa3: func(c *CPU) {},                                          // nop
+	0xa7: func(c *CPU) {},                                          // nop
+	0xab: func(c *CPU) {},                                          // nop
+	0xaf: func(c *CPU) {},                                          // nop
+	0xb2: func(c *CPU) { ld(c, c.storeA, c.loadIndirectZeroPage) }, // lda
+	0xb3: func(c *CPU) {},                                          // nop
+	0xb7: func(c *CPU) {},                                          // nop
+	0xbb: func(c *CPU) {},                                          // nop
+	0xbf: func(c *CPU) {},                                          // nop
+	0xc2: func(c *CPU) { nop(c, c.loadImmediate) },
+	0xc3: func(c *CPU) {}, // nop
+	0xc7: func(c *CPU) {}, // nop
+	0xcb: func(c *CPU) {}, // nop
+	0xcf: func(c *CPU) {}, // nop
+	0xd2: func(c *CPU) { cmp(c, c.loadA, c.loadIndirectZeroPage) },
+	0xd3: func(c *CPU) {}, // nop
+	0xd4: func(c *CPU) { nop(c, c.loadZeroPageX) },
+	0xd7: func(c *CPU) {},              // nop
+	0xda: func(c *CPU) { c.push(c.X) }, // phx
+	0xdb: func(c *CPU) {},              // nop
+	0xdc: func(c *CPU) { nop(c, c.loadAbsolute) },
+	0xdf: func(c *CPU) {}, // nop
+	0xe2: func(c *CPU) { nop(c, c.loadImmediate) },
+	0xe3: func(c *CPU) {}, // nop
+	0xe7: func(c *CPU) {}, // nop
+	0xeb: func(c *CPU) {}, // nop
+	0xef: func(c *CPU) {}, // nop
+	0xf2: func(c *CPU) { sbc(c, c.loadIndirectZeroPage) },
+	0xf3: func(c *CPU) {}, // nop
+	0xf4: func(c *CPU) { nop(c, c.loadZeroPageX) },
+	0xf7: func(c *CPU) {},                          // nop
+	0xfa: func(c *CPU) { ld(c, c.storeX, c.pull) }, // plx
+	0xfb: func(c *CPU) {},                          // nop
+	0xfc: func(c *CPU) { nop(c, c.loadAbsolute) },
+	0xff: func(c *CPU) {}, // nop
+}
+
+var bitOpcodes = [256]func(*CPU){
+	0x07: func(c *CPU) { rmb(c, 0) }, // rmb0
+	0x0f: func(c *CPU) { bbr(c, 0) }, // bbr0
+	0x17: func(c *CPU) { rmb(c, 1) }, // rmb1
+	0x1f: func(c *CPU) { bbr(c, 1) }, // bbr1
+	0x27: func(c *CPU) { rmb(c, 2) }, // rmb2
+	0x2f: func(c *CPU) { bbr(c, 2) }, // bbr2
+	0x37: func(c *CPU) { rmb(c, 3) }, // rmb3
+	0x3f: func(c *CPU) { bbr(c, 3) }, // bbr3
+	0x47: func(c *CPU) { rmb(c, 4) }, // rmb4
+	0x4f: func(c *CPU) { bbr(c, 4) }, // bbr4
+	0x57: func(c *CPU) { rmb(c, 5) }, // rmb5
+	0x5f: func(c *CPU) { bbr(c, 5) }, // bbr5
+	0x67: func(c *CPU) { rmb(c, 6) }, // rmb6
+	0x6f: func(c *CPU) { bbr(c, 6) }, // bbr6
+	0x77: func(c *CPU) { rmb(c, 7) }, // rmb7
+	0x7f: func(c *CPU) { bbr(c, 7) }, // bbr7
+	0x87: func(c *CPU) { smb(c, 0) }, // smb0
+	0x8f: func(c *CPU) { bbs(c, 0) }, // bbs0
+	0x97: func(c *CPU) { smb(c, 1) }, // smb1
+	0x9f: func(c *CPU) { bbs(c, 1) }, // bbs1
+	0xa7: func(c *CPU) { smb(c, 2) }, // smb2
+	0xaf: func(c *CPU) { bbs(c, 2) }, // bbs2
+	0xb7: func(c *CPU) { smb(c, 3) }, // smb3
+	0xbf: func(c *CPU) { bbs(c, 3) }, // bbs3
+	0xc7: func(c *CPU) { smb(c, 4) }, // smb4
+	0xcf: func(c *CPU) { bbs(c, 4) }, // bbs4
+	0xd7: func(c *CPU) { smb(c, 5) }, // smb5
+	0xdf: func(c *CPU) { bbs(c, 5) }, // bbs5
+	0xe7: func(c *CPU) { smb(c, 6) }, // smb6
+	0xef: func(c *CPU) { bbs(c, 6) }, // bbs6
+	0xf7: func(c *CPU) { smb(c, 7) }, // smb7
+	0xff: func(c *CPU) { bbs(c, 7) }, // bbs7
+}
+
+var wdcOpcodes = [256]func(*CPU){
+	0xcb: func(c *CPU) { c.Wait = true }, // wai
+	0xdb: func(c *CPU) { jam(c) },        // stp
 }
@@ -7,11 +7,25 @@ import (
 	"github.com/blackchip-org/retro-cs/rcs"
 )
 
+// Reader disassembles the instructions of the NMOS 6502.
 func Reader(e rcs.StmtEval) {
+	read(e, dasmTable)
+}
+
+// ReaderFor returns a reader that disassembles the instructions of the
+// variant.
+func ReaderFor(v Variant) rcs.CodeReader {
+	table := variantDasm[v]
+	return func(e rcs.StmtEval) {
+		read(e, table)
+	}
+}
+
+func read(e rcs.StmtEval, table map[uint8]op) {
 	e.Stmt.Addr = e.Ptr.Addr()
 	opcode := e.Ptr.Fetch()
 	e.Stmt.Bytes = append(e.Stmt.Bytes, opcode)
-	op, ok := dasmTable[opcode]
+	op, ok := table[opcode]
 	if !ok {
 		e.Stmt.Op = fmt.Sprintf("?%02x", opcode)
 		return
@@ -56,6 +70,13 @@ func formatOp(op op, operand int, addr int) string {
 				value = addr - int(value8*-1) + 2
 			}
 		}
+		// Branch on a bit in zero page has the zero page address in the
+		// low byte and the displacement in the high byte. Add three as the
+		// instruction is one byte longer than the other branches.
+		if op.mode == zeroPageRelative {
+			target := addr + int(int8(operand>>8)) + 3
+			return " " + fmt.Sprintf(format, operand&0xff, target)
+		}
 		// If the format does not contain a formatting directive, just use as is.
 		// For example: "asl a"
 		if strings.Contains(format, "%") {
@@ -0,0 +1,150 @@
+package m6502
+
+import (
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+// Undocumented instructions on the NMOS 6502. Only those that are stable
+// across chips are implemented.
+//
+// http://www.oxyron.de/html/opcodes02.html
+
+// value returns a load of v. The read-modify-write instructions use the
+// value that was stored instead of reading the bus again.
+func value(v uint8) rcs.Load8 {
+	return func() uint8 { return v }
+}
+
+// and, then copy the negative flag to carry
+func anc(c *CPU, load rcs.Load8) {
+	and(c, load)
+	c.SR &^= FlagC
+	if c.SR&FlagN != 0 {
+		c.SR |= FlagC
+	}
+}
+
+// and, then logical shift right the accumulator
+func alr(c *CPU, load rcs.Load8) {
+	and(c, load)
+	lsr(c, c.storeA, c.loadA)
+}
+
+// and, then rotate right the accumulator. Carry and overflow are set
+// from bits 6 and 5 of the result instead of the rotation.
+func arr(c *CPU, load rcs.Load8) {
+	in := c.A & load()
+	out := in >> 1
+	if c.SR&FlagC != 0 {
+		out |= 1 << 7
+	}
+
+	c.SR &^= FlagN | FlagV | FlagZ | FlagC
+	if out&(1<<7) != 0 {
+		c.SR |= FlagN
+	}
+	if out == 0 {
+		c.SR |= FlagZ
+	}
+	if c.SR&FlagD == 0 {
+		if out&(1<<6) != 0 {
+			c.SR |= FlagC
+		}
+		if (out>>6^out>>5)&1 != 0 {
+			c.SR |= FlagV
+		}
+		c.A = out
+		return
+	}
+
+	// In decimal mode, each nibble is adjusted after the rotation
+	if (in^out)&(1<<6) != 0 {
+		c.SR |= FlagV
+	}
+	lo, hi := in&0x0f, in>>4
+	if lo+lo&1 > 5 {
+		out = out&0xf0 | (out+6)&0x0f
+	}
+	if hi+hi&1 > 5 {
+		c.SR |= FlagC
+		out += 0x60
+	}
+	c.A = out
+}
+
+// decrement, then compare with the accumulator
+func dcp(c *CPU, store rcs.Store8, load rcs.Load8) {
+	out := dec(c, store, load)
+	cmp(c, c.loadA, value(out))
+}
+
+// increment, then subtract from the accumulator
+func isc(c *CPU, store rcs.Store8, load rcs.Load8) {
+	out := inc(c, store, load)
+	sbc(c, value(out))
+}
+
+// halt the processor. The program counter stays on this instruction and
+// interrupts are no longer serviced.
+func jam(c *CPU) {
+	c.Jam = true
+	c.pc--
+}
+
+// load both the accumulator and x register
+func lax(c *CPU, load rcs.Load8) {
+	ld(c, c.storeA, load)
+	c.X = c.A
+}
+
+// no operation, but the operand is still read
+func nop(c *CPU, load rcs.Load8) {
+	load()
+}
+
+// rotate left, then and with the accumulator
+func rla(c *CPU, store rcs.Store8, load rcs.Load8) {
+	out := rol(c, store, load)
+	and(c, value(out))
+}
+
+// rotate right, then add to the accumulator
+func rra(c *CPU, store rcs.Store8, load rcs.Load8) {
+	out := ror(c, store, load)
+	adc(c, value(out))
+}
+
+// store the accumulator and-ed with the x register
+func sax(c *CPU, store rcs.Store8) {
+	store(c.A & c.X)
+}
+
+// subtract from the accumulator and-ed with the x register and store in the
+// x register. Flags are set as in compare.
+func sbx(c *CPU, load rcs.Load8) {
+	out := int16(c.A&c.X) - int16(load())
+
+	c.SR &^= FlagC | FlagN | FlagZ
+	if out >= 0 {
+		c.SR |= FlagC
+	}
+	if out&(1<<7) != 0 {
+		c.SR |= FlagN
+	}
+	if out&0xff == 0 {
+		c.SR |= FlagZ
+	}
+	c.X = uint8(out)
+}
+
+// arithmetic shift left, then or with the accumulator
+func slo(c *CPU, store rcs.Store8, load rcs.Load8) {
+	out := asl(c, store, load)
+	ora(c, value(out))
+}
+
+// logical shift right, then exclusive or with the accumulator
+func sre(c *CPU, store rcs.Store8, load rcs.Load8) {
+	out := lsr(c, store, load)
+	eor(c, value(out))
+}
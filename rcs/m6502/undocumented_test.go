@@ -0,0 +1,351 @@
+package m6502
+
+import (
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+func TestAlr(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x4b, 0x0f) // alr #$0f
+	c.A = 0x33
+	testRunCPU(t, c)
+	want := uint8(0x01)
+	have := c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestAnc(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x0b, 0xf0) // anc #$f0
+	c.A = 0x81
+	testRunCPU(t, c)
+	want := uint8(0x80)
+	have := c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagN | FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestArr(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x6b, 0xc0) // arr #$c0
+	c.A = 0xff
+	c.SR = FlagC
+	testRunCPU(t, c)
+	want := uint8(0xe0)
+	have := c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	// bit 6 set for carry, bits 6 and 5 equal for no overflow
+	want = FlagN | FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestArrOverflow(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x6b, 0x80) // arr #$80
+	c.A = 0xff
+	testRunCPU(t, c)
+	want := uint8(0x40)
+	have := c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagV | FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestDcpZeroPage(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xc7, 0x34) // dcp $34
+	c.mem.Write(0x34, 0x43)
+	c.A = 0x42
+	testRunCPU(t, c)
+	want := uint8(0x42)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagZ | FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+// The result of the decrement is not read back from memory. A second read
+// of a device register could have side effects.
+func TestDcpReadOnce(t *testing.T) {
+	mem := rcs.NewMemory(1, 0x10000)
+	mem.MapRAM(0, make([]uint8, 0x10000, 0x10000))
+	reads := 0
+	reg := uint8(0x43)
+	mem.MapLoad(0x0300, func() uint8 {
+		reads++
+		return reg
+	})
+	mem.MapStore(0x0300, func(v uint8) { reg = v })
+	c := New(mem)
+	c.SP = 0xff
+	c.SetPC(0x1ff)
+	c.mem.WriteN(0x0200, 0xcf, 0x00, 0x03) // dcp $0300
+	c.A = 0x42
+	testRunCPU(t, c)
+	if reads != 1 {
+		t.Errorf("\n want: 1 read \n have: %v reads", reads)
+	}
+	want := FlagZ | FlagC | Flag5
+	have := c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestIscZeroPage(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xe7, 0x34) // isc $34
+	c.mem.Write(0x34, 0x01)
+	c.A = 0x05
+	c.SR = FlagC
+	testRunCPU(t, c)
+	want := uint8(0x02)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = uint8(0x03)
+	have = c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestJam(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x02) // jam
+	c.Next()
+	c.IRQ = true
+	c.Next()
+	if !c.Jam {
+		t.Errorf("expected jam")
+	}
+	want := 0x0200
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestLaxZeroPage(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xa7, 0x34) // lax $34
+	c.mem.Write(0x34, 0x88)
+	testRunCPU(t, c)
+	if c.A != 0x88 || c.X != 0x88 {
+		t.Errorf("\n want: a=88 x=88 \n have: a=%02x x=%02x \n", c.A, c.X)
+	}
+	want := FlagN | Flag5
+	have := c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestNopAbsoluteX(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x1c, 0x34, 0x12) // nop $1234,x
+	c.Next()
+	want := 0x0203
+	have := c.PC() + c.Offset()
+	if want != have {
+		t.Errorf("\n want: %04x \n have: %04x \n", want, have)
+	}
+}
+
+func TestRlaZeroPage(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x27, 0x34) // rla $34
+	c.mem.Write(0x34, 0x81)
+	c.A = 0x0f
+	c.SR = FlagC
+	testRunCPU(t, c)
+	want := uint8(0x03)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = uint8(0x03)
+	have = c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestRraZeroPage(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x67, 0x34) // rra $34
+	c.mem.Write(0x34, 0x03)
+	c.A = 0x10
+	testRunCPU(t, c)
+	want := uint8(0x01)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	// carry from the rotate is added
+	want = uint8(0x12)
+	have = c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestSaxZeroPage(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x87, 0x34) // sax $34
+	c.A = 0xf0
+	c.X = 0x3c
+	testRunCPU(t, c)
+	want := uint8(0x30)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestSbx(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xcb, 0x04) // sbx #$04
+	c.A = 0x0f
+	c.X = 0xfc
+	testRunCPU(t, c)
+	want := uint8(0x08)
+	have := c.X
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestSbxBorrow(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0xcb, 0x01) // sbx #$01
+	c.A = 0xff
+	c.X = 0x00
+	testRunCPU(t, c)
+	want := uint8(0xff)
+	have := c.X
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagN | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestSloAbsolute(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x0f, 0xab, 0x02) // slo $02ab
+	c.mem.Write(0x02ab, 0x81)
+	c.A = 0x01
+	testRunCPU(t, c)
+	want := uint8(0x02)
+	have := c.mem.Read(0x02ab)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = uint8(0x03)
+	have = c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
+
+func TestSloIndirectY(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x13, 0x34) // slo ($34),y
+	c.mem.WriteLE(0x34, 0x02ab)
+	c.mem.Write(0x02ad, 0x01)
+	c.Y = 0x02
+	testRunCPU(t, c)
+	want := uint8(0x02)
+	have := c.mem.Read(0x02ad)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+}
+
+func TestSreZeroPage(t *testing.T) {
+	c := newTestCPU()
+	c.mem.WriteN(0x0200, 0x47, 0x34) // sre $34
+	c.mem.Write(0x34, 0x03)
+	c.A = 0x81
+	testRunCPU(t, c)
+	want := uint8(0x01)
+	have := c.mem.Read(0x34)
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = uint8(0x80)
+	have = c.A
+	if want != have {
+		t.Errorf("\n want: %02x \n have: %02x \n", want, have)
+	}
+	want = FlagN | FlagC | Flag5
+	have = c.SR
+	if want != have {
+		flagError(t, want, have)
+	}
+}
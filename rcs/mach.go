@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"time"
 	"unsafe"
 
+	"github.com/blackchip-org/retro-cs/config"
 	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
 )
@@ -20,10 +22,19 @@ const (
 )
 
 const (
-	vblank   = time.Duration(16670 * time.Microsecond)
-	perJiffy = 20000 // instructions per jiffy
+	vblank = time.Duration(16670 * time.Microsecond)
+
+	// PerJiffy is the number of times each processor is stepped between
+	// vertical blanks.
+	PerJiffy = 20000
 )
 
+// Steps returns the number of times each processor is stepped during
+// the duration. Used to schedule events that happen during a frame.
+func Steps(d time.Duration) int {
+	return int(int64(PerJiffy) * int64(d) / int64(vblank))
+}
+
 func (s Status) String() string {
 	switch s {
 	case Pause:
@@ -74,6 +85,7 @@ type Mach struct {
 	Keyboard        func(*sdl.KeyboardEvent) error
 	ButtonHandler   func(*sdl.ControllerButtonEvent) error
 	AxisHandler     func(*sdl.ControllerAxisEvent) error
+	NVRAM           *NVRAM
 
 	CPU         map[string]CPU
 	Proc        map[string]Proc
@@ -84,6 +96,8 @@ type Mach struct {
 	At          int    // address of the executing instruction
 
 	stuck     map[string]bool
+	suspended map[string]bool
+	speed     map[string]int
 	tracing   map[string]bool
 	scanLines *sdl.Texture
 	init      bool
@@ -91,6 +105,7 @@ type Mach struct {
 	cmd       chan message
 	snapT     *sdl.Texture
 	snapS     *sdl.Surface
+	nvramOK   bool
 }
 
 func (m *Mach) Init() error {
@@ -157,6 +172,9 @@ func (m *Mach) Init() error {
 			m.scanLines = scanLines
 		}
 	}
+	if m.NVRAM != nil && m.NVRAM.Persistent {
+		m.loadNVRAM()
+	}
 	m.init = true
 	return nil
 }
@@ -183,6 +201,7 @@ func (m *Mach) Run() error {
 			break
 		}
 	}
+	m.saveNVRAM()
 	panicked = false
 	return nil
 }
@@ -208,29 +227,48 @@ func (m *Mach) jiffy() {
 	m.sdl()
 	if m.Status == Run {
 		m.VBlankFunc()
+		m.loadNVRAM()
+	}
+}
+
+// loadNVRAM restores the non-volatile memory once the game has initialized
+// it. The memory is only saved on quit if it was ready to be restored.
+func (m *Mach) loadNVRAM() {
+	if m.NVRAM == nil || m.nvramOK || !m.NVRAM.Valid() {
+		return
+	}
+	m.nvramOK = true
+	filename := filepath.Join(config.VarDir, m.NVRAM.Name)
+	if err := m.NVRAM.Load(filename); err != nil {
+		m.event(ErrorEvent, fmt.Sprintf("unable to load nvram: %v", err))
+	}
+}
+
+func (m *Mach) saveNVRAM() {
+	if m.NVRAM == nil || !m.nvramOK {
+		return
+	}
+	filename := filepath.Join(config.VarDir, m.NVRAM.Name)
+	if err := m.NVRAM.Save(filename); err != nil {
+		log.Printf("unable to save nvram: %v", err)
 	}
 }
 
 func (m *Mach) execute() {
-	for t := 0; t < perJiffy; t++ {
+	for t := 0; t < PerJiffy; t++ {
 		for name, cpu := range m.CPU {
-			m.Executing = name
-			m.At = cpu.PC() + cpu.Offset()
-			if m.tracing[name] && !m.stuck[name] {
-				m.event(TraceEvent, name, cpu.PC())
+			if m.suspended[name] {
+				continue
 			}
-			cpu.Next()
-			// if the program counter didn't change, it is either stuck
-			// in an infinite loop or not advancing due to a halt-like
-			// instruction
-			m.stuck[name] = m.At == cpu.PC()
-			// at a breakpoint? only honor it if the processor is not stuck.
-			// when at a halt-like instruction, this causes a break once
-			// instead of each time.
-			addr := cpu.PC() + cpu.Offset()
-			if _, yes := m.Breakpoints[name][addr]; yes && !m.stuck[name] {
-				m.setStatus(Break)
-				return
+			n := m.speed[name]
+			if n == 0 {
+				n = 1
+			}
+			for i := 0; i < n; i++ {
+				if m.step(name, cpu) {
+					m.setStatus(Break)
+					return
+				}
 			}
 		}
 		m.Executing = ""
@@ -241,6 +279,57 @@ func (m *Mach) execute() {
 	}
 }
 
+// step executes the next instruction on the CPU and returns true if a
+// breakpoint has been reached.
+func (m *Mach) step(name string, cpu CPU) bool {
+	m.Executing = name
+	m.At = cpu.PC() + cpu.Offset()
+	if m.tracing[name] && !m.stuck[name] {
+		m.event(TraceEvent, name, cpu.PC())
+	}
+	cpu.Next()
+	// if the program counter didn't change, it is either stuck
+	// in an infinite loop or not advancing due to a halt-like
+	// instruction
+	m.stuck[name] = m.At == cpu.PC()
+	// at a breakpoint? only honor it if the processor is not stuck.
+	// when at a halt-like instruction, this causes a break once
+	// instead of each time.
+	addr := cpu.PC() + cpu.Offset()
+	_, yes := m.Breakpoints[name][addr]
+	return yes && !m.stuck[name]
+}
+
+// Suspend stops the named CPU from executing until it is resumed. This
+// is used by systems where the processors share a bus and only one may
+// run at a time.
+func (m *Mach) Suspend(name string) {
+	if m.suspended == nil {
+		m.suspended = make(map[string]bool)
+	}
+	m.suspended[name] = true
+}
+
+// Resume allows a suspended CPU to execute again.
+func (m *Mach) Resume(name string) {
+	delete(m.suspended, name)
+}
+
+// Suspended returns true if the named CPU is not executing.
+func (m *Mach) Suspended(name string) bool {
+	return m.suspended[name]
+}
+
+// SetSpeed sets the number of instructions the named CPU executes each
+// time the machine steps through all of its processors. The default is
+// one. Used for processors that can switch to a faster clock.
+func (m *Mach) SetSpeed(name string, n int) {
+	if m.speed == nil {
+		m.speed = make(map[string]int)
+	}
+	m.speed[name] = n
+}
+
 func (m *Mach) render() error {
 	r := m.Ctx.Renderer
 	if err := m.Screen.Draw(r); err != nil {
@@ -0,0 +1,219 @@
+package rcs
+
+import (
+	"fmt"
+	"sort"
+)
+
+// RegionKind identifies what is mapped to a region of memory.
+type RegionKind int
+
+const (
+	RegionUnmapped RegionKind = iota
+	RegionRAM
+	RegionROM
+	RegionIO
+)
+
+func (k RegionKind) String() string {
+	switch k {
+	case RegionRAM:
+		return "ram"
+	case RegionROM:
+		return "rom"
+	case RegionIO:
+		return "io"
+	}
+	return "unmapped"
+}
+
+// MemoryRegion describes a contiguous range of addresses that are mapped
+// to the same source. The source is recorded when the region is mapped:
+// MapRAM and MapROM create RAM and ROM regions, MapRW, MapLoad and the
+// other single address methods create IO regions, and Map names regions
+// after the memory they were copied from.
+type MemoryRegion struct {
+	Addr   int        // first address in the region
+	Size   int        // number of addresses in the region
+	Kind   RegionKind // what is mapped to the region
+	Name   string     // name of the source, if known
+	Offset int        // offset into the source for the first address
+}
+
+// Source returns the kind, name, and offset of the region as text.
+func (r MemoryRegion) Source() string {
+	s := r.Kind.String()
+	if r.Name != "" {
+		s += " " + r.Name
+	}
+	if r.Kind == RegionRAM || r.Kind == RegionROM {
+		s += fmt.Sprintf(" $%04x", r.Offset)
+	}
+	return s
+}
+
+// continues returns true if r1 starts where r ends and is mapped to the
+// next part of the same source.
+func (r MemoryRegion) continues(r1 MemoryRegion) bool {
+	if r.Addr+r.Size != r1.Addr || r.Kind != r1.Kind || r.Name != r1.Name {
+		return false
+	}
+	return r.Kind == RegionIO || r.Offset+r.Size == r1.Offset
+}
+
+type memoryLabel struct {
+	name string
+	data []uint8
+}
+
+// Label gives a name to a slice of data, such as a ROM returned by
+// LoadROMs. When the slice, or a part of it, is mapped with MapRAM or
+// MapROM, the name and the offset into the slice are recorded for the
+// region.
+func (m *Memory) Label(name string, data []uint8) {
+	m.labels = append(m.labels, memoryLabel{name: name, data: data})
+}
+
+// source returns the label and offset for data if it is part of a slice
+// given to Label.
+func (m *Memory) source(data []uint8) (string, int) {
+	if len(data) == 0 {
+		return "", 0
+	}
+	for _, l := range m.labels {
+		offset := cap(l.data) - cap(data)
+		if offset >= 0 && offset < len(l.data) && &l.data[offset] == &data[0] {
+			return l.name, offset
+		}
+	}
+	return "", 0
+}
+
+// Regions returns the regions mapped for reading and for writing in the
+// given bank. The regions are in address order and cover the entire
+// address space. Addresses without a mapping are in regions with a kind
+// of RegionUnmapped.
+func (m *Memory) Regions(bank int) (reads []MemoryRegion, writes []MemoryRegion) {
+	var rmap, wmap []MemoryRegion
+	if b := m.banks[bank]; b != nil {
+		rmap, wmap = b.rmap, b.wmap
+	}
+	return fillRegions(rmap, m.MaxAddr+1), fillRegions(wmap, m.MaxAddr+1)
+}
+
+// fillRegions adds unmapped regions to the gaps in list.
+func fillRegions(list []MemoryRegion, size int) []MemoryRegion {
+	out := make([]MemoryRegion, 0, len(list)*2+1)
+	next := 0
+	for _, r := range list {
+		if r.Addr > next {
+			out = append(out, MemoryRegion{Addr: next, Size: r.Addr - next})
+		}
+		out = append(out, r)
+		next = r.Addr + r.Size
+	}
+	if next < size {
+		out = append(out, MemoryRegion{Addr: next, Size: size - next})
+	}
+	return out
+}
+
+// setRegion records r in list, replacing the parts of any regions that
+// overlap. Regions with a kind of RegionUnmapped are not stored. The list
+// is updated in place when possible since regions are often mapped one
+// address at a time.
+func setRegion(list []MemoryRegion, r MemoryRegion) []MemoryRegion {
+	end := r.Addr + r.Size
+	// regions from i to j overlap with r
+	i := sort.Search(len(list), func(k int) bool {
+		return list[k].Addr+list[k].Size > r.Addr
+	})
+	j := sort.Search(len(list), func(k int) bool {
+		return list[k].Addr >= end
+	})
+
+	var pieces [3]MemoryRegion
+	n := 0
+	if i < j && list[i].Addr < r.Addr {
+		left := list[i]
+		left.Size = r.Addr - left.Addr
+		pieces[n] = left
+		n++
+	}
+	if r.Kind != RegionUnmapped && r.Size > 0 {
+		pieces[n] = r
+		n++
+	}
+	if i < j && list[j-1].Addr+list[j-1].Size > end {
+		right := list[j-1]
+		if right.Kind != RegionIO {
+			right.Offset += end - right.Addr
+		}
+		right.Size = right.Addr + right.Size - end
+		right.Addr = end
+		pieces[n] = right
+		n++
+	}
+
+	// replace the overlapping regions with the pieces
+	size := len(list)
+	delta := n - (j - i)
+	if delta > 0 {
+		list = append(list, pieces[:delta]...)
+		copy(list[j+delta:], list[j:size])
+	} else if delta < 0 {
+		copy(list[j+delta:], list[j:])
+		list = list[:size+delta]
+	}
+	copy(list[i:], pieces[:n])
+
+	// join regions that continue each other
+	k := i - 1
+	if k < 0 {
+		k = 0
+	}
+	for k < i+n && k+1 < len(list) {
+		if list[k].continues(list[k+1]) {
+			list[k].Size += list[k+1].Size
+			list = append(list[:k+1], list[k+2:]...)
+			n--
+			continue
+		}
+		k++
+	}
+	return list
+}
+
+// copyRegions replaces the regions in list for the range of size
+// addresses at dst with the regions found at src in list1. Regions without
+// a name are given the name passed in.
+func copyRegions(list []MemoryRegion, list1 []MemoryRegion, dst int, src int, size int, name string) []MemoryRegion {
+	var copies []MemoryRegion
+	for _, r := range list1 {
+		start, end := r.Addr, r.Addr+r.Size
+		if start < src {
+			start = src
+		}
+		if end > src+size {
+			end = src + size
+		}
+		if start >= end {
+			continue
+		}
+		r1 := r
+		r1.Addr = start - src + dst
+		r1.Size = end - start
+		if r.Kind != RegionIO {
+			r1.Offset += start - r.Addr
+		}
+		if r1.Name == "" {
+			r1.Name = name
+		}
+		copies = append(copies, r1)
+	}
+	list = setRegion(list, MemoryRegion{Addr: dst, Size: size})
+	for _, r := range copies {
+		list = setRegion(list, r)
+	}
+	return list
+}
@@ -0,0 +1,97 @@
+package rcs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegions(t *testing.T) {
+	mem := NewMemory(1, 0x10000)
+	ram := make([]uint8, 0x10000, 0x10000)
+	rom := make([]uint8, 0x4000, 0x4000)
+	var port uint8
+	mem.Label("ram", ram)
+	mem.Label("kernal", rom)
+	mem.MapRAM(0x0000, ram)
+	mem.MapROM(0xe000, rom[0x2000:])
+	mem.MapRW(0xd000, &port)
+	mem.MapRW(0xd001, &port)
+	mem.Unmap(0xd002)
+
+	reads, writes := mem.Regions(0)
+	wantReads := []MemoryRegion{
+		{Addr: 0x0000, Size: 0xd000, Kind: RegionRAM, Name: "ram", Offset: 0x0000},
+		{Addr: 0xd000, Size: 0x0002, Kind: RegionIO},
+		{Addr: 0xd002, Size: 0x0001, Kind: RegionUnmapped},
+		{Addr: 0xd003, Size: 0x0ffd, Kind: RegionRAM, Name: "ram", Offset: 0xd003},
+		{Addr: 0xe000, Size: 0x2000, Kind: RegionROM, Name: "kernal", Offset: 0x2000},
+	}
+	if !reflect.DeepEqual(reads, wantReads) {
+		t.Errorf("\n have: %+v \n want: %+v", reads, wantReads)
+	}
+	wantWrites := []MemoryRegion{
+		{Addr: 0x0000, Size: 0xd000, Kind: RegionRAM, Name: "ram", Offset: 0x0000},
+		{Addr: 0xd000, Size: 0x0002, Kind: RegionIO},
+		{Addr: 0xd002, Size: 0x0001, Kind: RegionUnmapped},
+		{Addr: 0xd003, Size: 0x2ffd, Kind: RegionRAM, Name: "ram", Offset: 0xd003},
+	}
+	if !reflect.DeepEqual(writes, wantWrites) {
+		t.Errorf("\n have: %+v \n want: %+v", writes, wantWrites)
+	}
+}
+
+func TestRegionsMap(t *testing.T) {
+	io := NewMemory(1, 0x100)
+	io.Name = "io"
+	var port uint8
+	io.MapRW(0x00, &port)
+	io.MapRAM(0x80, make([]uint8, 0x80, 0x80))
+
+	mem := NewMemory(2, 0x1000)
+	mem.SetBank(1)
+	mem.Map(0x400, io)
+	mem.Mirror(0x400, 0x100, 0x800)
+
+	reads, _ := mem.Regions(1)
+	want := []MemoryRegion{
+		{Addr: 0x000, Size: 0x400, Kind: RegionUnmapped},
+		{Addr: 0x400, Size: 0x001, Kind: RegionIO, Name: "io"},
+		{Addr: 0x401, Size: 0x07f, Kind: RegionUnmapped},
+		{Addr: 0x480, Size: 0x080, Kind: RegionRAM, Name: "io", Offset: 0x00},
+		{Addr: 0x500, Size: 0x700, Kind: RegionUnmapped},
+		{Addr: 0xc00, Size: 0x001, Kind: RegionIO, Name: "io"},
+		{Addr: 0xc01, Size: 0x07f, Kind: RegionUnmapped},
+		{Addr: 0xc80, Size: 0x080, Kind: RegionRAM, Name: "io", Offset: 0x00},
+		{Addr: 0xd00, Size: 0x300, Kind: RegionUnmapped},
+	}
+	if !reflect.DeepEqual(reads, want) {
+		t.Errorf("\n have: %+v \n want: %+v", reads, want)
+	}
+
+	// bank 0 was never mapped
+	reads, _ = mem.Regions(0)
+	want = []MemoryRegion{{Addr: 0, Size: 0x1000, Kind: RegionUnmapped}}
+	if !reflect.DeepEqual(reads, want) {
+		t.Errorf("\n have: %+v \n want: %+v", reads, want)
+	}
+}
+
+func TestRegionSource(t *testing.T) {
+	var tests = []struct {
+		region MemoryRegion
+		want   string
+	}{
+		{MemoryRegion{Kind: RegionRAM, Offset: 0x1000}, "ram $1000"},
+		{MemoryRegion{Kind: RegionROM, Name: "basic"}, "rom basic $0000"},
+		{MemoryRegion{Kind: RegionIO, Name: "io", Offset: 0x10}, "io io"},
+		{MemoryRegion{Kind: RegionUnmapped}, "unmapped"},
+	}
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			have := test.region.Source()
+			if have != test.want {
+				t.Errorf("\n have: %v \n want: %v", have, test.want)
+			}
+		})
+	}
+}
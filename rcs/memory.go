@@ -46,11 +46,12 @@ this example from Pac-Man, the value of port IN0 is read through address
 	mem.MapRO(0x5000, &portIN0)
 	mem.MapWO(0x5000, &irqEnable)
 
-A range of addresses can map to the same value with:
+A range of addresses can map to the same value by mirroring the mapping.
+Mirror copies the bindings to each address that is ignored by the mask
+and MirrorN repeats the bindings a number of times:
 
-	for i := 0x50c0; i <= 0x50ff; i++ {
-		mem.MapWO(i, &watchdogReset)
-	}
+	mem.MapWO(0x50c0, &watchdogReset)
+	mem.Mirror(0x50c0, 1, 0x3f)
 
 Large blocks can be mapped by passing in a uint8 slice using MapRAM
 for read/write access and MapROM for read-only access. The following example
@@ -89,6 +90,20 @@ in bank 0 and full access to the RAM in bank 1:
 
 	mem.SetBank(1)
 	mem.MapRAM(0x0000, ram)
+
+The address space is divided into pages of 256 addresses. A page that is
+entirely mapped by MapRAM or MapROM is read and written directly through
+the slice. Once any other mapping is made in a page, every address in that
+page goes through a function call. Keep I/O registers out of RAM pages
+where possible. The mappings for a bank are not allocated until that bank
+is selected.
+
+Each map operation also records where the values come from. Give a name
+to a ROM or RAM slice with Label before mapping it and use Regions to
+list what is mapped in a bank:
+
+	mem.Label("kernal", roms["kernal"])
+	mem.MapROM(0xe000, roms["kernal"])
 */
 type Memory struct {
 	Name     string
@@ -96,20 +111,63 @@ type Memory struct {
 	Callback func(MemoryEvent) // function called on watch events
 	NBank    int               // number of banks
 
-	// read and write functions for each bank
-	reads  [][]Load8
-	writes [][]Store8
+	// mappings for each bank, allocated when the bank is first selected
+	banks  []*bank
+	npages int
+
+	// names given to slices of data with Label
+	labels []memoryLabel
 
 	// previous read and write functions are stored here during watches
-	preads  [][]Load8
-	pwrites [][]Store8
+	preads  map[watchKey]Load8
+	pwrites map[watchKey]Store8
 
 	// selected bank index
 	bank int
 
-	// read and write functions for the selected bank
-	read  []Load8
-	write []Store8
+	// mappings for the selected bank
+	rdata [][]uint8
+	wdata [][]uint8
+	rfunc [][]Load8
+	wfunc [][]Store8
+}
+
+const (
+	pageBits = 8
+	pageSize = 1 << pageBits
+	pageMask = pageSize - 1
+)
+
+// bank contains the mappings for each page of pageSize addresses. When an
+// entire page is mapped to a slice with MapRAM or MapROM, values are
+// accessed directly through rdata and wdata. Otherwise the data slice for
+// the page is nil and there is a function for each address in rfunc and
+// wfunc.
+type bank struct {
+	rdata [][]uint8
+	wdata [][]uint8
+	rfunc [][]Load8
+	wfunc [][]Store8
+
+	// sources recorded for reads and writes, see Regions
+	rmap []MemoryRegion
+	wmap []MemoryRegion
+
+	mirrors []MemoryMirror
+}
+
+func newBank(npages int) *bank {
+	return &bank{
+		rdata: make([][]uint8, npages, npages),
+		wdata: make([][]uint8, npages, npages),
+		rfunc: make([][]Load8, npages, npages),
+		wfunc: make([][]Store8, npages, npages),
+	}
+}
+
+type watchKey struct {
+	bank int
+	addr int
 }
 
 // NewMemory creates a memory space of uint8 values that are addressable
@@ -124,48 +182,61 @@ func NewMemory(banks int, size int) *Memory {
 		Name:    "mem",
 		MaxAddr: size - 1,
 		NBank:   banks,
-		reads:   make([][]Load8, banks, banks),
-		writes:  make([][]Store8, banks, banks),
-		preads:  make([][]Load8, banks, banks),
-		pwrites: make([][]Store8, banks, banks),
-	}
-	for b := 0; b < banks; b++ {
-		mem.reads[b] = make([]Load8, size, size)
-		mem.writes[b] = make([]Store8, size, size)
-		mem.preads[b] = make([]Load8, size, size)
-		mem.pwrites[b] = make([]Store8, size, size)
-	}
-	mem.read = mem.reads[0]
-	mem.write = mem.writes[0]
+		banks:   make([]*bank, banks, banks),
+		npages:  (size + pageMask) >> pageBits,
+		preads:  make(map[watchKey]Load8),
+		pwrites: make(map[watchKey]Store8),
+	}
+	mem.SetBank(0)
 	mem.Callback = func(MemoryEvent) {}
 	return mem
 }
 
 // Read returns the 8-bit value at the given address.
 func (m *Memory) Read(addr int) uint8 {
-	if m.read[addr] == nil {
-		log.Printf("(!) %v: unmapped read, bank %v, addr %v", m.Name,
-			X(m.bank), X(addr))
-		return 0
+	if data := m.rdata[addr>>pageBits]; data != nil {
+		return data[addr&pageMask]
+	}
+	return m.readFunc(addr)
+}
+
+// readFunc handles reads that are not mapped directly to a slice.
+func (m *Memory) readFunc(addr int) uint8 {
+	if funcs := m.rfunc[addr>>pageBits]; funcs != nil {
+		if load := funcs[addr&pageMask]; load != nil {
+			return load()
+		}
 	}
-	v := m.read[addr]()
-	return v
+	log.Printf("(!) %v: unmapped read, bank %v, addr %v", m.Name,
+		X(m.bank), X(addr))
+	return 0
 }
 
 // Write sets the 8-bit value at the given address.
 func (m *Memory) Write(addr int, val uint8) {
-	if m.write[addr] == nil {
-		log.Printf("(!) %v: unmapped write, bank %v, addr %v, val %v",
-			m.Name, X(m.bank), X(addr), X8(val))
-		return
+	if data := m.wdata[addr>>pageBits]; data != nil {
+		data[addr&pageMask] = val
+	} else {
+		m.writeFunc(addr, val)
 	}
-	m.write[addr](val)
+}
+
+// writeFunc handles writes that are not mapped directly to a slice.
+func (m *Memory) writeFunc(addr int, val uint8) {
+	if funcs := m.wfunc[addr>>pageBits]; funcs != nil {
+		if store := funcs[addr&pageMask]; store != nil {
+			store(val)
+			return
+		}
+	}
+	log.Printf("(!) %v: unmapped write, bank %v, addr %v, val %v",
+		m.Name, X(m.bank), X(addr), X8(val))
 }
 
 // WriteN sets multiple 8-bit values starting with the given address.
 func (m *Memory) WriteN(addr int, values ...uint8) {
 	for i, val := range values {
-		m.write[addr+i](val)
+		m.Write(addr+i, val)
 	}
 }
 
@@ -186,14 +257,113 @@ func (m *Memory) WriteLE(addr int, val int) {
 	m.Write(addr+1, hi)
 }
 
+// readFuncs switches reads in the page to use functions and returns them.
+func (m *Memory) readFuncs(page int) []Load8 {
+	if m.rfunc[page] == nil {
+		funcs := make([]Load8, pageSize, pageSize)
+		data := m.rdata[page]
+		for i := range data {
+			j := i
+			funcs[i] = func() uint8 { return data[j] }
+		}
+		m.rfunc[page] = funcs
+		m.rdata[page] = nil
+	}
+	return m.rfunc[page]
+}
+
+// writeFuncs switches writes in the page to use functions and returns them.
+func (m *Memory) writeFuncs(page int) []Store8 {
+	if m.wfunc[page] == nil {
+		funcs := make([]Store8, pageSize, pageSize)
+		data := m.wdata[page]
+		for i := range data {
+			j := i
+			funcs[i] = func(v uint8) { data[j] = v }
+		}
+		m.wfunc[page] = funcs
+		m.wdata[page] = nil
+	}
+	return m.wfunc[page]
+}
+
+// loadFunc returns the read mapping at the address as a function.
+func (m *Memory) loadFunc(addr int) Load8 {
+	if data := m.rdata[addr>>pageBits]; data != nil {
+		i := addr & pageMask
+		return func() uint8 { return data[i] }
+	}
+	if funcs := m.rfunc[addr>>pageBits]; funcs != nil {
+		return funcs[addr&pageMask]
+	}
+	return nil
+}
+
+// storeFunc returns the write mapping at the address as a function.
+func (m *Memory) storeFunc(addr int) Store8 {
+	if data := m.wdata[addr>>pageBits]; data != nil {
+		i := addr & pageMask
+		return func(v uint8) { data[i] = v }
+	}
+	if funcs := m.wfunc[addr>>pageBits]; funcs != nil {
+		return funcs[addr&pageMask]
+	}
+	return nil
+}
+
+// mapData maps reads, and writes if rw is set, to the values in data
+// starting at addr. Pages that are covered entirely are accessed directly
+// through the slice and the remaining addresses use functions.
+func (m *Memory) mapData(addr int, data []uint8, rw bool) {
+	name, offset := m.source(data)
+	r := MemoryRegion{Addr: addr, Size: len(data), Kind: RegionROM, Name: name, Offset: offset}
+	b := m.banks[m.bank]
+	if rw {
+		r.Kind = RegionRAM
+		b.wmap = setRegion(b.wmap, r)
+	}
+	b.rmap = setRegion(b.rmap, r)
+
+	end := addr + len(data)
+	for start := addr; start < end; {
+		page := start >> pageBits
+		pstart := page << pageBits
+		pend := pstart + pageSize
+		if pend > m.MaxAddr+1 {
+			pend = m.MaxAddr + 1
+		}
+		next := pend
+		if next > end {
+			next = end
+		}
+		chunk := data[start-addr : next-addr]
+		if start == pstart && next == pend {
+			m.rdata[page], m.rfunc[page] = chunk, nil
+			if rw {
+				m.wdata[page], m.wfunc[page] = chunk, nil
+			}
+		} else {
+			rfunc := m.readFuncs(page)
+			for i := range chunk {
+				j := i
+				rfunc[(start+i)&pageMask] = func() uint8 { return chunk[j] }
+			}
+			if rw {
+				wfunc := m.writeFuncs(page)
+				for i := range chunk {
+					j := i
+					wfunc[(start+i)&pageMask] = func(v uint8) { chunk[j] = v }
+				}
+			}
+		}
+		start = next
+	}
+}
+
 // MapRAM adds read/write maps to all of the 8-bit values in ram starting at
 // addr. Any existing read or write maps are replaced.
 func (m *Memory) MapRAM(addr int, ram []uint8) {
-	for i := 0; i < len(ram); i++ {
-		j := i
-		m.read[addr+i] = func() uint8 { return ram[j] }
-		m.write[addr+i] = func(v uint8) { ram[j] = v }
-	}
+	m.mapData(addr, ram, true)
 }
 
 // MapROM adds read maps to all of the 8-bit values in rom starting at
@@ -203,10 +373,7 @@ func (m *Memory) MapROM(addr int, rom []uint8) {
 	if rom == nil {
 		return
 	}
-	for i := 0; i < len(rom); i++ {
-		j := i
-		m.read[addr+i] = func() uint8 { return rom[j] }
-	}
+	m.mapData(addr, rom, false)
 }
 
 // MapRW adds a read and write to the given 8-bit value at addr. Any existing
@@ -219,13 +386,13 @@ func (m *Memory) MapRW(addr int, b *uint8) {
 // MapRO adds a read mapping to the given 8-bit value at addr. If there is
 // already a read mapping, it is replaced. Write mappings are not altered.
 func (m *Memory) MapRO(addr int, b *uint8) {
-	m.read[addr] = func() uint8 { return *b }
+	m.MapLoad(addr, func() uint8 { return *b })
 }
 
 // MapWO adds a write mapping to the given 8-bit value at addr. If there is
 // already a write mapping, it is replaced. Read mappings are not altered.
 func (m *Memory) MapWO(addr int, b *uint8) {
-	m.write[addr] = func(v uint8) { *b = v }
+	m.MapStore(addr, func(v uint8) { *b = v })
 }
 
 // MapLoad adds a read mapping to the given function. When this address is
@@ -233,7 +400,9 @@ func (m *Memory) MapWO(addr int, b *uint8) {
 // read mapping for this address, it is replaced. Write mappings are not
 // altered.
 func (m *Memory) MapLoad(addr int, load Load8) {
-	m.read[addr] = load
+	m.setLoad(addr, load)
+	b := m.banks[m.bank]
+	b.rmap = setRegion(b.rmap, MemoryRegion{Addr: addr, Size: 1, Kind: RegionIO})
 }
 
 // MapStore adds a write mapping to the given function. When this address is
@@ -241,7 +410,21 @@ func (m *Memory) MapLoad(addr int, load Load8) {
 // is already a write mapping for this address, it is replaced. Read mappings
 // are not altered.
 func (m *Memory) MapStore(addr int, store Store8) {
-	m.write[addr] = store
+	m.setStore(addr, store)
+	b := m.banks[m.bank]
+	b.wmap = setRegion(b.wmap, MemoryRegion{Addr: addr, Size: 1, Kind: RegionIO})
+}
+
+// setLoad replaces the read function at the address without recording
+// a source for the region.
+func (m *Memory) setLoad(addr int, load Load8) {
+	m.readFuncs(addr >> pageBits)[addr&pageMask] = load
+}
+
+// setStore replaces the write function at the address without recording
+// a source for the region.
+func (m *Memory) setStore(addr int, store Store8) {
+	m.writeFuncs(addr >> pageBits)[addr&pageMask] = store
 }
 
 // Map maps the contents of another memory to this memory at the starting
@@ -249,33 +432,174 @@ func (m *Memory) MapStore(addr int, store Store8) {
 // later updates to the map of the other memory will not be seen in this
 // memory.
 func (m *Memory) Map(startAddr int, m1 *Memory) {
-	endAddr := startAddr + len(m1.read)
-	for i, addr := 0, startAddr; addr < endAddr; i, addr = i+1, addr+1 {
-		m.read[addr] = m1.read[i]
-		m.write[addr] = m1.write[i]
+	m.copyMap(startAddr, m1, 0, m1.MaxAddr+1)
+}
+
+// copyMap copies the bindings for size addresses starting at src in m1
+// to this memory starting at dst. Pages that line up are copied whole.
+func (m *Memory) copyMap(dst int, m1 *Memory, src int, size int) {
+	for i := 0; i < size; {
+		addr, addr1 := dst+i, src+i
+		if addr&pageMask == 0 && addr1&pageMask == 0 && i+pageSize <= size {
+			page, page1 := addr>>pageBits, addr1>>pageBits
+			m.rdata[page] = m1.rdata[page1]
+			m.wdata[page] = m1.wdata[page1]
+			m.rfunc[page] = nil
+			m.wfunc[page] = nil
+			if funcs := m1.rfunc[page1]; funcs != nil {
+				m.rfunc[page] = append([]Load8(nil), funcs...)
+			}
+			if funcs := m1.wfunc[page1]; funcs != nil {
+				m.wfunc[page] = append([]Store8(nil), funcs...)
+			}
+			i += pageSize
+			continue
+		}
+		m.setLoad(addr, m1.loadFunc(addr1))
+		m.setStore(addr, m1.storeFunc(addr1))
+		i++
+	}
+
+	// Unnamed sources from another memory are named after that memory
+	name := ""
+	if m1 != m {
+		name = m1.Name
+	}
+	b, b1 := m.banks[m.bank], m1.banks[m1.bank]
+	b.rmap = copyRegions(b.rmap, b1.rmap, dst, src, size, name)
+	b.wmap = copyRegions(b.wmap, b1.wmap, dst, src, size, name)
+}
+
+// MemoryMirror describes a range of addresses that has been copied to
+// other locations with Mirror or MirrorN.
+type MemoryMirror struct {
+	Addr int // first address in the range
+	Size int // number of addresses in the range
+	Mask int // address lines that are not decoded, if created by Mirror
+	N    int // number of times the range appears
+}
+
+func (r MemoryMirror) String() string {
+	where := fmt.Sprintf("$%04x", r.Addr)
+	if r.Size > 1 {
+		where += fmt.Sprintf("-$%04x", r.Addr+r.Size-1)
 	}
+	if r.Mask != 0 {
+		return fmt.Sprintf("%v x%v, mask $%04x", where, r.N, r.Mask)
+	}
+	return fmt.Sprintf("%v x%v, step $%04x", where, r.N, r.Size)
+}
+
+/*
+Mirror copies the bindings for the range of size addresses starting at addr
+to every location that only differs by the bits set in mask. This is used
+when a device does not decode all of the address lines. The range should
+not include any addresses that differ by the bits in the mask. In this
+example from Pac-Man, address line A15 is not connected so video memory
+is also found at 0xc000:
+
+	mem.MapRAM(0x4000, video.TileMemory)
+	mem.MapRAM(0x4400, video.ColorMemory)
+	mem.Mirror(0x4000, 0x800, 0x8000)
+
+The bindings are copied at call time in the same way as Map. Any mappings
+that should not be mirrored need to be made afterwards.
+*/
+func (m *Memory) Mirror(addr int, size int, mask int) {
+	base := addr &^ mask
+	n := 0
+	for bits := mask; ; bits = (bits - 1) & mask {
+		n++
+		dst := base | bits
+		if dst != addr && dst+size-1 <= m.MaxAddr {
+			m.copyMap(dst, m, addr, size)
+		}
+		if bits == 0 {
+			break
+		}
+	}
+	m.addMirror(MemoryMirror{Addr: addr, Size: size, Mask: mask, N: n})
+}
+
+// MirrorN copies the bindings for the range of size addresses starting at
+// addr so that the range repeats n times. The copies start immediately
+// after the range.
+func (m *Memory) MirrorN(addr int, size int, n int) {
+	for i := 1; i < n; i++ {
+		m.copyMap(addr+i*size, m, addr, size)
+	}
+	m.addMirror(MemoryMirror{Addr: addr, Size: size, N: n})
+}
+
+func (m *Memory) addMirror(r MemoryMirror) {
+	b := m.banks[m.bank]
+	b.mirrors = append(b.mirrors, r)
+}
+
+// Mirrors returns the mirrors created in the given bank.
+func (m *Memory) Mirrors(bank int) []MemoryMirror {
+	if b := m.banks[bank]; b != nil {
+		return b.mirrors
+	}
+	return nil
+}
+
+// ParseAddrMask converts a pattern of address lines to an address and a
+// mask. The pattern is written with the most significant line first and
+// uses '1' and '0' for decoded lines, 'x' for lines that are not decoded,
+// and '-' for lines that are not used. Spaces are ignored. For example,
+// "0101 0000 00xx xxxx" is an address of 0x5000 with a mask of 0x3f which
+// covers the range from 0x5000 to 0x503f.
+func ParseAddrMask(pattern string) (addr int, mask int, err error) {
+	pattern = strings.Replace(pattern, " ", "", -1)
+	if pattern == "" {
+		return 0, 0, fmt.Errorf("empty pattern")
+	}
+	for _, ch := range pattern {
+		addr <<= 1
+		mask <<= 1
+		switch ch {
+		case '0', '-':
+		case '1':
+			addr |= 1
+		case 'x':
+			mask |= 1
+		default:
+			return 0, 0, fmt.Errorf("invalid address line: %q", ch)
+		}
+	}
+	return addr, mask, nil
 }
 
 // Unmap removes the read and write mappings at the address.
 func (m *Memory) Unmap(addr int) {
-	m.read[addr] = nil
-	m.write[addr] = nil
+	m.setLoad(addr, nil)
+	m.setStore(addr, nil)
+	b := m.banks[m.bank]
+	r := MemoryRegion{Addr: addr, Size: 1, Kind: RegionUnmapped}
+	b.rmap = setRegion(b.rmap, r)
+	b.wmap = setRegion(b.wmap, r)
 }
 
 // MapNil creates an empty read and write mapping at the address.
 func (m *Memory) MapNil(addr int) {
-	m.read[addr] = func() uint8 { return 0 }
-	m.write[addr] = func(uint8) {}
+	m.setLoad(addr, func() uint8 { return 0 })
+	m.setStore(addr, func(uint8) {})
+	b := m.banks[m.bank]
+	r := MemoryRegion{Addr: addr, Size: 1, Kind: RegionIO, Name: "nil"}
+	b.rmap = setRegion(b.rmap, r)
+	b.wmap = setRegion(b.wmap, r)
 }
 
 // WatchRO creates a read watch on the address. When a value is read to that
 // address, a MemoryEvent is sent to the Callback function.
 func (m *Memory) WatchRO(addr int) {
-	if m.preads[m.bank][addr] != nil {
+	key := watchKey{bank: m.bank, addr: addr}
+	if _, ok := m.preads[key]; ok {
 		return
 	}
-	prev := m.reads[m.bank][addr]
-	m.read[addr] = func() uint8 {
+	prev := m.loadFunc(addr)
+	m.setLoad(addr, func() uint8 {
 		value := prev()
 		m.Callback(MemoryEvent{
 			Read:  true,
@@ -284,18 +608,19 @@ func (m *Memory) WatchRO(addr int) {
 			Value: value,
 		})
 		return value
-	}
-	m.preads[m.bank][addr] = prev
+	})
+	m.preads[key] = prev
 }
 
 // WatchWO creates a write watch on the address. When a value is written to
 // that address, a MemoryEvent is sent to the Callback function.
 func (m *Memory) WatchWO(addr int) {
-	if m.pwrites[m.bank][addr] != nil {
+	key := watchKey{bank: m.bank, addr: addr}
+	if _, ok := m.pwrites[key]; ok {
 		return
 	}
-	prev := m.writes[m.bank][addr]
-	m.write[addr] = func(value uint8) {
+	prev := m.storeFunc(addr)
+	m.setStore(addr, func(value uint8) {
 		prev(value)
 		m.Callback(MemoryEvent{
 			Read:  false,
@@ -303,8 +628,8 @@ func (m *Memory) WatchWO(addr int) {
 			Addr:  addr,
 			Value: value,
 		})
-	}
-	m.pwrites[m.bank][addr] = prev
+	})
+	m.pwrites[key] = prev
 }
 
 // WatchRW creats a read and write watch on the address. When a value is
@@ -317,13 +642,14 @@ func (m *Memory) WatchRW(addr int) {
 
 // Unwatch removes read nad write watches on the address.
 func (m *Memory) Unwatch(addr int) {
-	if prev := m.pwrites[m.bank][addr]; prev != nil {
-		m.write[addr] = prev
-		m.pwrites[m.bank][addr] = nil
+	key := watchKey{bank: m.bank, addr: addr}
+	if prev, ok := m.pwrites[key]; ok {
+		m.setStore(addr, prev)
+		delete(m.pwrites, key)
 	}
-	if prev := m.preads[m.bank][addr]; prev != nil {
-		m.read[addr] = prev
-		m.preads[m.bank][addr] = nil
+	if prev, ok := m.preads[key]; ok {
+		m.setLoad(addr, prev)
+		delete(m.preads, key)
 	}
 }
 
@@ -334,10 +660,15 @@ func (m *Memory) Bank() int {
 }
 
 // SetBank changes the selected bank. Banks are numbered starting with zero.
-func (m *Memory) SetBank(bank int) {
-	m.bank = bank
-	m.read = m.reads[bank]
-	m.write = m.writes[bank]
+func (m *Memory) SetBank(n int) {
+	b := m.banks[n]
+	if b == nil {
+		b = newBank(m.npages)
+		m.banks[n] = b
+	}
+	m.bank = n
+	m.rdata, m.wdata = b.rdata, b.wdata
+	m.rfunc, m.wfunc = b.rfunc, b.wfunc
 }
 
 // Pointer points to a location in memory.
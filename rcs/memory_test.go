@@ -242,6 +242,86 @@ func TestMemoryBank(t *testing.T) {
 	}
 }
 
+func TestMemoryOverlay(t *testing.T) {
+	mem := NewMemory(1, 0x400)
+	ram := make([]uint8, 0x400, 0x400)
+	rom := make([]uint8, 0x180, 0x180)
+	for i := range rom {
+		rom[i] = 0xee
+	}
+	mem.MapRAM(0, ram)
+	mem.MapROM(0x100, rom)
+
+	mem.Write(0x100, 0x11) // full page of rom
+	mem.Write(0x210, 0x22) // partial page of rom
+	mem.Write(0x290, 0x33) // ram after the rom
+	have := []uint8{mem.Read(0x100), mem.Read(0x210), mem.Read(0x290)}
+	want := []uint8{0xee, 0xee, 0x33}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+	if ram[0x100] != 0x11 || ram[0x210] != 0x22 {
+		t.Errorf("expected writes to go to ram")
+	}
+}
+
+func TestMemoryMapInPage(t *testing.T) {
+	mem := NewMemory(1, 0x100)
+	ram := make([]uint8, 0x100, 0x100)
+	port := uint8(0x44)
+	mem.MapRAM(0, ram)
+	mem.MapRO(0x50, &port)
+
+	mem.Write(0x50, 0x11)
+	mem.Write(0x51, 0x22)
+	have := []uint8{mem.Read(0x50), mem.Read(0x51), ram[0x50]}
+	want := []uint8{0x44, 0x22, 0x11}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+}
+
+func TestMemoryMapCopy(t *testing.T) {
+	main := NewMemory(1, 0x400)
+	mem := NewMemory(1, 0x200)
+	mem.MapRAM(0, make([]uint8, 0x200, 0x200))
+	main.Map(0x100, mem)
+
+	// remapping the copy should not change the original
+	port := uint8(0x44)
+	main.MapRO(0x110, &port)
+	main.Write(0x210, 0x11)
+	if have := mem.Read(0x10); have != 0 {
+		t.Errorf("\n have: %02x \n want: 00", have)
+	}
+	if have := mem.Read(0x110); have != 0x11 {
+		t.Errorf("\n have: %02x \n want: 11", have)
+	}
+}
+
+func TestMemoryWatch(t *testing.T) {
+	var events []MemoryEvent
+	mem := NewMemory(2, 0x100)
+	mem.SetBank(1)
+	mem.MapRAM(0, make([]uint8, 0x100, 0x100))
+	mem.Callback = func(e MemoryEvent) { events = append(events, e) }
+
+	mem.WatchRW(0x10)
+	mem.Write(0x10, 0x11)
+	mem.Read(0x10)
+	mem.Unwatch(0x10)
+	mem.Write(0x10, 0x22)
+	mem.Read(0x10)
+
+	want := []MemoryEvent{
+		{Read: false, Bank: 1, Addr: 0x10, Value: 0x11},
+		{Read: true, Bank: 1, Addr: 0x10, Value: 0x11},
+	}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("\n have: %v \n want: %v", events, want)
+	}
+}
+
 func TestMemoryMirror(t *testing.T) {
 	mem := NewMemory(1, 20)
 	ram := make([]uint8, 10, 10)
@@ -255,6 +335,92 @@ func TestMemoryMirror(t *testing.T) {
 	}
 }
 
+func TestMemoryMirrorMask(t *testing.T) {
+	mem := NewMemory(1, 0x10000)
+	ram := make([]uint8, 0x800, 0x800)
+	in0 := uint8(0x12)
+	mem.MapRAM(0x4000, ram)
+	mem.Mirror(0x4000, 0x800, 0x8000)
+	mem.MapNil(0x5040)
+	mem.MapRO(0x5000, &in0)
+	mem.Mirror(0x5000, 1, 0x3f)
+
+	mem.Write(0xc123, 0x44)
+	have := []uint8{ram[0x123], mem.Read(0x4123), mem.Read(0x503f), mem.Read(0x5040)}
+	want := []uint8{0x44, 0x44, 0x12, 0x00}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+}
+
+func TestMemoryMirrorN(t *testing.T) {
+	mem := NewMemory(1, 0x10)
+	ram := make([]uint8, 4, 4)
+	mem.MapRAM(0, ram)
+	mem.MirrorN(0, 4, 3)
+
+	mem.Write(0x9, 0x11)
+	if ram[1] != 0x11 {
+		t.Errorf("\n have: %02x \n want: 11", ram[1])
+	}
+	have := mem.Mirrors(0)
+	want := []MemoryMirror{{Addr: 0, Size: 4, N: 3}}
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+}
+
+func TestMemoryMirrorString(t *testing.T) {
+	var tests = []struct {
+		mirror MemoryMirror
+		want   string
+	}{
+		{MemoryMirror{Addr: 0x4000, Size: 0x800, Mask: 0x8000, N: 2}, "$4000-$47ff x2, mask $8000"},
+		{MemoryMirror{Addr: 0x5000, Size: 1, Mask: 0x3f, N: 64}, "$5000 x64, mask $003f"},
+		{MemoryMirror{Addr: 0x0000, Size: 0x400, N: 4}, "$0000-$03ff x4, step $0400"},
+	}
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			have := test.mirror.String()
+			if have != test.want {
+				t.Errorf("\n have: %v \n want: %v", have, test.want)
+			}
+		})
+	}
+}
+
+func TestParseAddrMask(t *testing.T) {
+	var tests = []struct {
+		in   string
+		addr int
+		mask int
+		err  bool
+	}{
+		{"0101 0000 00xx xxxx", 0x5000, 0x003f, false},
+		{"x100 0xxx xxxx xxxx", 0x4000, 0x87ff, false},
+		{"--01", 0x0001, 0x0000, false},
+		{"0102", 0, 0, true},
+		{"", 0, 0, true},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			addr, mask, err := ParseAddrMask(test.in)
+			if test.err {
+				if err == nil {
+					t.Errorf("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if addr != test.addr || mask != test.mask {
+				t.Errorf("\n have: %04x %04x \n want: %04x %04x", addr, mask, test.addr, test.mask)
+			}
+		})
+	}
+}
+
 func benchmarkMemoryW(count int, b *testing.B) {
 	mem := NewMemory(1, count)
 	mem.MapRAM(0, make([]uint8, count, count))
@@ -286,6 +452,29 @@ func BenchmarkMemoryR(b *testing.B)      { benchmarkMemoryR(1, b) }
 func BenchmarkMemoryPageR(b *testing.B)  { benchmarkMemoryR(0x100, b) }
 func BenchmarkMemorySpaceR(b *testing.B) { benchmarkMemoryR(0x10000, b) }
 
+func benchmarkMemoryIO(count int, b *testing.B) {
+	mem := NewMemory(1, count)
+	var value uint8
+	for i := 0; i < count; i++ {
+		mem.MapRW(i, &value)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := 0; i < count; i++ {
+			mem.Write(i, mem.Read(i))
+		}
+	}
+}
+
+func BenchmarkMemoryPageIO(b *testing.B) { benchmarkMemoryIO(0x100, b) }
+
+func BenchmarkNewMemoryBanked(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		mem := NewMemory(256, 0x10000)
+		mem.MapRAM(0, make([]uint8, 0x10000, 0x10000))
+	}
+}
+
 func TestPointerFetch(t *testing.T) {
 	mem := NewMemory(1, 10)
 	mem.MapRAM(0, make([]uint8, 10, 10))
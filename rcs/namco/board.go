@@ -0,0 +1,203 @@
+package namco
+
+import (
+	"fmt"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/blackchip-org/retro-cs/rcs/z80"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// The screen has 264 scanlines and the NMI for the third CPU is raised
+// on lines 64 and 192.
+const (
+	scanLines = 264
+	nmiLine0  = 64
+	nmiLine1  = 192
+)
+
+// Board is the hardware shared by Galaga and Dig Dug. Each of the three
+// Z80 processors has its own code ROM at $0000 and the rest of the
+// memory is common to all of them:
+//
+//	$6800-$681f: WSG, write only
+//	$6820-$6822: interrupt enable for CPU1, CPU2, and CPU3
+//	$6823:       reset latch for CPU2 and CPU3
+//	$7000-$70ff: 06XX data
+//	$7100-$71ff: 06XX control
+//
+// The 51XX is device 0 on the 06XX. A game maps the rest of its devices
+// into the common memory before calling InitCPUs.
+type Board struct {
+	CPU    [3]*z80.CPU
+	Mem    [3]*rcs.Memory
+	Common *rcs.Memory
+	N06XX  *N06XX
+	N51XX  *N51XX
+	WSG    *WSG
+
+	InterruptEnable0 uint8 // low bit
+	InterruptEnable1 uint8 // low bit
+	InterruptEnable2 uint8 // low bit, clear to enable the NMI for CPU3
+
+	reset uint8   // low bit, clear to hold CPU2 and CPU3 in reset
+	irq   [2]bool // interrupt lines for CPU1 and CPU2 are asserted
+	steps int     // steps since the last vblank
+	mach  *rcs.Mach
+}
+
+// NewBoard maps the sound generator, the latches, and the 06XX into the
+// common memory.
+func NewBoard(mem *rcs.Memory, waveforms []uint8, spec sdl.AudioSpec) (*Board, error) {
+	b := &Board{Common: mem}
+
+	wsg, err := NewWSG(waveforms, spec)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < 0x20; i++ {
+		offset := i
+		mem.MapStore(0x6800+i, func(v uint8) { wsg.Write(offset, v) })
+	}
+	// There is no sound enable latch. The generator is always on.
+	wsg.Enable = 1
+	b.WSG = wsg
+
+	// Clearing an interrupt enable also clears a pending interrupt
+	mem.MapStore(0x6820, func(v uint8) {
+		b.InterruptEnable0 = v & 1
+		if b.InterruptEnable0 == 0 {
+			b.irq[0] = false
+			if b.CPU[0] != nil {
+				b.CPU[0].IRQ = false
+			}
+		}
+	})
+	mem.MapStore(0x6821, func(v uint8) {
+		b.InterruptEnable1 = v & 1
+		if b.InterruptEnable1 == 0 {
+			b.irq[1] = false
+			if b.CPU[1] != nil {
+				b.CPU[1].IRQ = false
+			}
+		}
+	})
+	mem.MapStore(0x6822, func(v uint8) {
+		b.InterruptEnable2 = v & 1
+	})
+	mem.MapStore(0x6823, func(v uint8) {
+		b.reset = v & 1
+		b.holdReset()
+	})
+
+	b.N51XX = NewN51XX()
+	b.N06XX = NewN06XX()
+	b.N06XX.DeviceW[0] = b.N51XX.Write
+	b.N06XX.DeviceR[0] = b.N51XX.Read
+	b.N06XX.NMI = func() {
+		b.CPU[0].NMI = true
+	}
+	for i, addr := 0, 0x7000; addr < 0x7100; addr, i = addr+1, i+1 {
+		j := i
+		mem.MapLoad(addr, b.N06XX.ReadData(j))
+		mem.MapStore(addr, b.N06XX.WriteData(j))
+	}
+	for i, addr := 0, 0x7100; addr < 0x7200; addr, i = addr+1, i+1 {
+		j := i
+		mem.MapLoad(addr, b.N06XX.ReadCtrl(j))
+		mem.MapStore(addr, b.N06XX.WriteCtrl(j))
+	}
+	return b, nil
+}
+
+// InitCPUs creates the memory for each CPU from the common memory and
+// the code ROM for that CPU.
+func (b *Board) InitCPUs(code [3][]uint8) {
+	for i := range b.CPU {
+		b.Mem[i] = rcs.NewMemory(1, 0x10000)
+		b.Mem[i].Name = fmt.Sprintf("mem%v", i+1)
+		b.Mem[i].Map(0, b.Common)
+		b.Mem[i].MapROM(0x0000, code[i])
+		b.CPU[i] = z80.New(b.Mem[i])
+		b.CPU[i].Name = fmt.Sprintf("cpu%v", i+1)
+	}
+}
+
+// Attach is called once the machine has been created so that CPU2 and
+// CPU3 can be held in reset.
+func (b *Board) Attach(mach *rcs.Mach) {
+	b.mach = mach
+	b.holdReset()
+}
+
+// VBlank asserts the interrupt lines for CPU1 and CPU2 if enabled.
+func (b *Board) VBlank() {
+	if b.InterruptEnable0 != 0 {
+		b.irq[0] = true
+	}
+	if b.InterruptEnable1 != 0 {
+		b.irq[1] = true
+	}
+	b.steps = 0
+}
+
+// Next is called each time the processors are stepped. The interrupt
+// lines for CPU1 and CPU2 stay asserted until cleared by the game and the
+// NMI for CPU3 is raised twice a frame.
+func (b *Board) Next() {
+	b.CPU[0].IRQ = b.irq[0]
+	b.CPU[1].IRQ = b.irq[1]
+	b.steps++
+	if b.steps == nmiLine0*rcs.PerJiffy/scanLines || b.steps == nmiLine1*rcs.PerJiffy/scanLines {
+		if b.InterruptEnable2 == 0 {
+			b.CPU[2].NMI = true
+		}
+	}
+}
+
+// holdReset keeps CPU2 and CPU3 from running while the reset latch is low.
+func (b *Board) holdReset() {
+	if b.mach == nil {
+		return
+	}
+	for i, name := range []string{"cpu2", "cpu3"} {
+		if b.reset == 0 {
+			b.CPU[i+1].Reset()
+			b.mach.Suspend(name)
+		} else {
+			b.mach.Resume(name)
+		}
+	}
+}
+
+// Save writes the processors, the sound generator, the 51XX, and the
+// latches.
+func (b *Board) Save(enc *rcs.Encoder) {
+	for _, cpu := range b.CPU {
+		cpu.Save(enc)
+	}
+	b.WSG.Save(enc)
+	b.N51XX.Save(enc)
+	enc.Encode(b.InterruptEnable0)
+	enc.Encode(b.InterruptEnable1)
+	enc.Encode(b.InterruptEnable2)
+	enc.Encode(b.reset)
+	enc.Encode(b.irq)
+	enc.Encode(b.steps)
+}
+
+// Load restores the state written by Save.
+func (b *Board) Load(dec *rcs.Decoder) {
+	for _, cpu := range b.CPU {
+		cpu.Load(dec)
+	}
+	b.WSG.Load(dec)
+	b.N51XX.Load(dec)
+	dec.Decode(&b.InterruptEnable0)
+	dec.Decode(&b.InterruptEnable1)
+	dec.Decode(&b.InterruptEnable2)
+	dec.Decode(&b.reset)
+	dec.Decode(&b.irq)
+	dec.Decode(&b.steps)
+	b.holdReset()
+}
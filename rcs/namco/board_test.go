@@ -0,0 +1,56 @@
+package namco
+
+import (
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func newTestBoard(t *testing.T) *Board {
+	mem := rcs.NewMemory(1, 0x10000)
+	b, err := NewBoard(mem, make([]uint8, 0x100), sdl.AudioSpec{Freq: WSGClock})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.InitCPUs([3][]uint8{{0}, {0}, {0}})
+	return b
+}
+
+func TestBoardIRQ(t *testing.T) {
+	b := newTestBoard(t)
+	b.Mem[0].Write(0x6820, 1)
+	b.VBlank()
+	b.Next()
+	if !b.CPU[0].IRQ {
+		t.Fatalf("expected IRQ on cpu1")
+	}
+	if b.CPU[1].IRQ {
+		t.Errorf("unexpected IRQ on cpu2")
+	}
+	// clearing the enable also clears the pending interrupt
+	b.Mem[0].Write(0x6820, 0)
+	if b.CPU[0].IRQ {
+		t.Errorf("expected IRQ to be cleared by the latch")
+	}
+	b.Next()
+	if b.CPU[0].IRQ {
+		t.Errorf("expected IRQ to stay cleared")
+	}
+}
+
+func TestBoardNMI(t *testing.T) {
+	b := newTestBoard(t)
+	b.VBlank()
+	n := 0
+	for i := 0; i < rcs.PerJiffy; i++ {
+		b.Next()
+		if b.CPU[2].NMI {
+			n++
+			b.CPU[2].NMI = false
+		}
+	}
+	if n != 2 {
+		t.Errorf("\n have: %v \n want: %v", n, 2)
+	}
+}
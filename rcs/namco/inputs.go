@@ -0,0 +1,123 @@
+package namco
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// Inputs maps the keyboard and game controllers to the inputs of a 51XX.
+// All inputs are active low. Only the joystick directions given to
+// NewInputs are mapped.
+//
+// The first controller used is player 1 and the second controller is
+// player 2. The player 2 joystick is found in the high nibble of IN1
+// and is only read by the game in a cocktail cabinet.
+type Inputs struct {
+	n       *N51XX
+	joy     uint8
+	players map[sdl.JoystickID]int
+}
+
+func NewInputs(n *N51XX, joy uint8) *Inputs {
+	return &Inputs{
+		n:       n,
+		joy:     joy,
+		players: make(map[sdl.JoystickID]int),
+	}
+}
+
+// Keyboard is the handler for keyboard events. Player 2 is only used in
+// a cocktail cabinet.
+func (in *Inputs) Keyboard(e *sdl.KeyboardEvent) error {
+	var port *uint8
+	var bit uint8
+	switch e.Keysym.Sym {
+	case sdl.K_1:
+		port, bit = &in.n.IN0, In0Start1
+	case sdl.K_2:
+		port, bit = &in.n.IN0, In0Start2
+	case sdl.K_c:
+		port, bit = &in.n.IN0, In0Coin1
+	case sdl.K_SPACE:
+		port, bit = &in.n.IN0, In0Button1
+	case sdl.K_UP:
+		port, bit = &in.n.IN1, in.joy&JoyUp
+	case sdl.K_LEFT:
+		port, bit = &in.n.IN1, in.joy&JoyLeft
+	case sdl.K_RIGHT:
+		port, bit = &in.n.IN1, in.joy&JoyRight
+	case sdl.K_DOWN:
+		port, bit = &in.n.IN1, in.joy&JoyDown
+	// player 2
+	case sdl.K_f:
+		port, bit = &in.n.IN0, In0Button2
+	case sdl.K_w:
+		port, bit = &in.n.IN1, (in.joy&JoyUp)<<4
+	case sdl.K_a:
+		port, bit = &in.n.IN1, (in.joy&JoyLeft)<<4
+	case sdl.K_d:
+		port, bit = &in.n.IN1, (in.joy&JoyRight)<<4
+	case sdl.K_s:
+		port, bit = &in.n.IN1, (in.joy&JoyDown)<<4
+	default:
+		return nil
+	}
+	if e.Type == sdl.KEYDOWN {
+		*port &^= bit
+	} else if e.Type == sdl.KEYUP {
+		*port |= bit
+	}
+	return nil
+}
+
+func (in *Inputs) player(id sdl.JoystickID) (int, bool) {
+	p, ok := in.players[id]
+	if ok {
+		return p, true
+	}
+	if len(in.players) >= 2 {
+		return 0, false
+	}
+	p = len(in.players)
+	in.players[id] = p
+	return p, true
+}
+
+// Button is the handler for game controller button events.
+func (in *Inputs) Button(e *sdl.ControllerButtonEvent) error {
+	p, ok := in.player(e.Which)
+	if !ok {
+		return nil
+	}
+	start := uint8(In0Start1)
+	fire := uint8(In0Button1)
+	shift := uint(0)
+	if p == 1 {
+		start = In0Start2
+		fire = In0Button2
+		shift = 4
+	}
+	var port *uint8
+	var bit uint8
+	switch e.Button {
+	case sdl.CONTROLLER_BUTTON_BACK:
+		port, bit = &in.n.IN0, In0Coin1
+	case sdl.CONTROLLER_BUTTON_START:
+		port, bit = &in.n.IN0, start
+	case sdl.CONTROLLER_BUTTON_A:
+		port, bit = &in.n.IN0, fire
+	case sdl.CONTROLLER_BUTTON_DPAD_UP:
+		port, bit = &in.n.IN1, (in.joy&JoyUp)<<shift
+	case sdl.CONTROLLER_BUTTON_DPAD_LEFT:
+		port, bit = &in.n.IN1, (in.joy&JoyLeft)<<shift
+	case sdl.CONTROLLER_BUTTON_DPAD_RIGHT:
+		port, bit = &in.n.IN1, (in.joy&JoyRight)<<shift
+	case sdl.CONTROLLER_BUTTON_DPAD_DOWN:
+		port, bit = &in.n.IN1, (in.joy&JoyDown)<<shift
+	default:
+		return nil
+	}
+	if e.Type == sdl.CONTROLLERBUTTONDOWN {
+		*port &^= bit
+	} else if e.Type == sdl.CONTROLLERBUTTONUP {
+		*port |= bit
+	}
+	return nil
+}
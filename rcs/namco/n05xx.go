@@ -0,0 +1,146 @@
+package namco
+
+import (
+	"image/color"
+	"log"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// The starfield is 256 pixels wide and advances a 16-bit linear feedback
+// shift register for every pixel. A star is found wherever the register
+// matches the hit pattern.
+const (
+	lfsrSeed     = 0x7fff
+	lfsrPeriod   = 0xffff
+	lfsrHitMask  = 0xfa14
+	lfsrHitValue = 0x7800
+
+	starfieldW     = 256
+	starfieldLines = 224
+)
+
+// Control registers for the starfield. Only the low bit of each is used.
+const (
+	StarSpeed0 = iota // scroll speed and direction, bits 0-2
+	StarSpeed1
+	StarSpeed2
+	StarSetA // selects set 0 or 1
+	StarSetB // selects set 2 or 3
+	StarEnable
+)
+
+// Pixels scrolled each frame as selected by the speed bits
+var starSpeeds = []int{-1, -2, -3, 0, 3, 2, 1, 0}
+
+type star struct {
+	pos   int // position in the LFSR sequence
+	set   uint8
+	color uint8
+}
+
+// N05XX is the starfield generator. There are four sets of stars and
+// two are displayed at any one time. The game blinks the stars by
+// alternating between the sets.
+type N05XX struct {
+	Control [6]uint8 // low bit, see StarSpeed0 through StarEnable
+	Scroll  int      // position of the starfield in the LFSR sequence
+	WatchW  bool
+
+	stars  []star
+	colors [64]color.RGBA
+}
+
+func NewN05XX() *N05XX {
+	n := &N05XX{}
+	lfsr := uint16(lfsrSeed)
+	for i := 0; i < lfsrPeriod; i++ {
+		if lfsr&lfsrHitMask == lfsrHitValue {
+			c := uint8(lfsr>>5)&0x07 | uint8(lfsr<<3)&0x18 | uint8(lfsr<<2)&0x20
+			n.stars = append(n.stars, star{
+				pos:   i,
+				set:   uint8(lfsr>>10)&1<<1 | uint8(lfsr>>8)&1,
+				color: ^c & 0x3f,
+			})
+		}
+		lfsr = nextLFSR(lfsr)
+	}
+	// Each color has two bits of red, green, and blue
+	levels := []uint8{0x00, 0x47, 0x97, 0xde}
+	for i := range n.colors {
+		n.colors[i] = color.RGBA{
+			R: levels[i&0x03],
+			G: levels[(i>>2)&0x03],
+			B: levels[(i>>4)&0x03],
+			A: 0xff,
+		}
+	}
+	return n
+}
+
+// nextLFSR returns the next state of the shift register which has taps
+// at 16, 14, 13, and 11.
+func nextLFSR(lfsr uint16) uint16 {
+	bit := (lfsr ^ lfsr>>2 ^ lfsr>>3 ^ lfsr>>5) & 1
+	return lfsr>>1 | bit<<15
+}
+
+// WriteControl returns a function that stores the low bit of a value to
+// the control register.
+func (n *N05XX) WriteControl(reg int) func(uint8) {
+	return func(v uint8) {
+		if n.WatchW {
+			log.Printf("n05xx control write(%v) => $%02x\n", reg, v)
+		}
+		n.Control[reg] = v & 1
+	}
+}
+
+// Speed returns the value of the speed bits.
+func (n *N05XX) Speed() uint8 {
+	return n.Control[StarSpeed0] | n.Control[StarSpeed1]<<1 | n.Control[StarSpeed2]<<2
+}
+
+// SetSpeed changes the speed bits.
+func (n *N05XX) SetSpeed(v uint8) {
+	n.Control[StarSpeed0] = v & 1
+	n.Control[StarSpeed1] = (v >> 1) & 1
+	n.Control[StarSpeed2] = (v >> 2) & 1
+}
+
+// VBlank scrolls the starfield for the next frame.
+func (n *N05XX) VBlank() {
+	n.Scroll += starSpeeds[n.Speed()]
+	n.Scroll %= lfsrPeriod
+	if n.Scroll < 0 {
+		n.Scroll += lfsrPeriod
+	}
+}
+
+// Draw renders the stars with the screen rotated into the cabinet. When
+// flip is true, the screen is rotated 180 degrees.
+func (n *N05XX) Draw(r *sdl.Renderer, flip bool) {
+	if n.Control[StarEnable] == 0 {
+		return
+	}
+	setA := n.Control[StarSetA]
+	setB := n.Control[StarSetB] | 2
+	for _, s := range n.stars {
+		if s.set != setA && s.set != setB {
+			continue
+		}
+		pos := (s.pos + n.Scroll) % lfsrPeriod
+		x := int32(pos%starfieldW) + 16
+		y := int32(pos / starfieldW)
+		if y >= starfieldLines {
+			continue
+		}
+		screenX, screenY := W-1-y, x
+		if flip {
+			screenX, screenY = W-1-screenX, H-1-screenY
+		}
+		c := n.colors[s.color]
+		r.SetDrawColor(c.R, c.G, c.B, c.A)
+		r.DrawPoint(screenX, screenY)
+	}
+}
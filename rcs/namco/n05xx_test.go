@@ -0,0 +1,41 @@
+package namco
+
+import "testing"
+
+func TestLFSRPeriod(t *testing.T) {
+	lfsr := nextLFSR(lfsrSeed)
+	n := 1
+	for ; lfsr != lfsrSeed; n++ {
+		lfsr = nextLFSR(lfsr)
+	}
+	if n != lfsrPeriod {
+		t.Errorf("\n have: %v \n want: %v", n, lfsrPeriod)
+	}
+}
+
+func TestStarSets(t *testing.T) {
+	n := NewN05XX()
+	var sets [4]int
+	for _, s := range n.stars {
+		sets[s.set]++
+	}
+	for i, count := range sets {
+		if count == 0 {
+			t.Errorf("no stars in set %v", i)
+		}
+	}
+}
+
+func TestStarScroll(t *testing.T) {
+	n := NewN05XX()
+	n.SetSpeed(0) // back one pixel
+	n.VBlank()
+	if n.Scroll != lfsrPeriod-1 {
+		t.Errorf("\n have: %v \n want: %v", n.Scroll, lfsrPeriod-1)
+	}
+	n.SetSpeed(4) // ahead three pixels
+	n.VBlank()
+	if n.Scroll != 2 {
+		t.Errorf("\n have: %v \n want: %v", n.Scroll, 2)
+	}
+}
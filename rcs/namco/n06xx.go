@@ -2,16 +2,28 @@ package namco
 
 import (
 	"log"
+	"time"
 
 	"github.com/blackchip-org/retro-cs/rcs"
 )
 
+// N06XXPeriod is the time between each NMI generated while a device is
+// selected.
+const N06XXPeriod = 200 * time.Microsecond
+
+// N06XX is the bus controller between the main CPU and the custom I/O
+// chips. The low four bits of the control register select the devices
+// and bit 4 is set for a read. While any device is selected, an NMI is
+// sent to the main CPU every N06XXPeriod so it can transfer the next
+// byte.
 type N06XX struct {
 	DeviceR [4]rcs.Load8
 	DeviceW [4]rcs.Store8
+	SelectR [4]func() // called when a device is selected for reading
 
 	ctrl    uint8
 	elapsed int
+	period  int
 	timing  bool
 	NMI     func()
 
@@ -23,7 +35,7 @@ type N06XX struct {
 }
 
 func NewN06XX() *N06XX {
-	n := &N06XX{}
+	n := &N06XX{period: rcs.Steps(N06XXPeriod)}
 	for i := 0; i < 4; i++ {
 		j := i
 		n.DeviceR[i] = func() uint8 {
@@ -45,36 +57,25 @@ func (n *N06XX) WriteData(addr int) rcs.Store8 {
 		if n.WatchDataW {
 			log.Printf("n06xx data write($%04x) => $%02x\n", addr, v)
 		}
-		dev := n.ctrl & 0x03
-		switch dev {
-		case 1 << 0:
-			n.DeviceW[0](v)
-		case 1 << 1:
-			n.DeviceW[1](v)
-		case 1 << 2:
-			n.DeviceW[2](v)
-		case 1 << 3:
-			n.DeviceW[3](v)
+		for i := uint(0); i < 4; i++ {
+			if n.ctrl&(1<<i) != 0 {
+				n.DeviceW[i](v)
+			}
 		}
 	}
 }
 
 func (n *N06XX) ReadData(addr int) rcs.Load8 {
 	return func() uint8 {
-		if n.ctrl&0x10 != 0 {
+		if n.ctrl&0x10 == 0 {
 			return 0
 		}
-		dev := n.ctrl & 0x03
+		// The outputs of all selected devices are wired together
 		v := uint8(0xff)
-		switch dev {
-		case 1 << 0:
-			v = n.DeviceR[0]()
-		case 1 << 1:
-			v = n.DeviceR[1]()
-		case 1 << 2:
-			v = n.DeviceR[2]()
-		case 1 << 3:
-			v = n.DeviceR[3]()
+		for i := uint(0); i < 4; i++ {
+			if n.ctrl&(1<<i) != 0 {
+				v &= n.DeviceR[i]()
+			}
 		}
 		if n.WatchDataR {
 			log.Printf("n06xx data $%02x <= read($%04x)\n", v, addr)
@@ -89,11 +90,14 @@ func (n *N06XX) WriteCtrl(addr int) rcs.Store8 {
 			log.Printf("n06xx ctrl write($%04x) => $%02x\n", addr, v)
 		}
 		n.ctrl = v
-		if v&0x0f == 0 {
-			//n.timing = false
-		} else {
-			n.elapsed = 0
-			n.timing = true
+		n.elapsed = 0
+		n.timing = v&0x0f != 0
+		if v&0x10 != 0 {
+			for i := uint(0); i < 4; i++ {
+				if v&(1<<i) != 0 && n.SelectR[i] != nil {
+					n.SelectR[i]()
+				}
+			}
 		}
 	}
 }
@@ -110,7 +114,7 @@ func (n *N06XX) ReadCtrl(addr int) rcs.Load8 {
 func (n *N06XX) Next() {
 	if n.timing {
 		n.elapsed++
-		if n.elapsed > 2000 {
+		if n.elapsed >= n.period {
 			if n.WatchNMI {
 				log.Println("n06xx NMI")
 			}
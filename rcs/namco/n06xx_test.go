@@ -0,0 +1,64 @@
+package namco
+
+import "testing"
+
+func TestN06XXSelect(t *testing.T) {
+	n := NewN06XX()
+	var have [4]uint8
+	for i := 0; i < 4; i++ {
+		j := i
+		n.DeviceW[i] = func(v uint8) { have[j] = v }
+	}
+	n.WriteCtrl(0)(0x09)
+	n.WriteData(0)(0x42)
+	want := [4]uint8{0x42, 0, 0, 0x42}
+	if have != want {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+}
+
+func TestN06XXReadMode(t *testing.T) {
+	n := NewN06XX()
+	n.DeviceR[3] = func() uint8 { return 0x5a }
+	n.WriteCtrl(0)(0x08)
+	if v := n.ReadData(0)(); v != 0 {
+		t.Errorf("\n have: %02x \n want: %02x", v, 0)
+	}
+	n.WriteCtrl(0)(0x18)
+	if v := n.ReadData(0)(); v != 0x5a {
+		t.Errorf("\n have: %02x \n want: %02x", v, 0x5a)
+	}
+}
+
+func TestN06XXNMI(t *testing.T) {
+	n := NewN06XX()
+	nmi := 0
+	n.NMI = func() { nmi++ }
+	n.WriteCtrl(0)(0x01)
+	for i := 0; i < n.period*3; i++ {
+		n.Next()
+	}
+	if nmi != 3 {
+		t.Errorf("\n have: %v \n want: %v", nmi, 3)
+	}
+	// no NMIs when no devices are selected
+	n.WriteCtrl(0)(0x00)
+	for i := 0; i < n.period*3; i++ {
+		n.Next()
+	}
+	if nmi != 3 {
+		t.Errorf("\n have: %v \n want: %v", nmi, 3)
+	}
+}
+
+func TestN06XXSelectR(t *testing.T) {
+	n := NewN06XX()
+	selected := 0
+	n.SelectR[1] = func() { selected++ }
+	n.WriteCtrl(0)(0x02)
+	n.WriteCtrl(0)(0x12)
+	n.WriteCtrl(0)(0x11)
+	if selected != 1 {
+		t.Errorf("\n have: %v \n want: %v", selected, 1)
+	}
+}
@@ -1,25 +1,247 @@
 package namco
 
-import "log"
+import (
+	"log"
 
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+// Input bits for the 51XX. All inputs are active low.
+const (
+	In0Button1 = 1 << iota // fire, player 1
+	In0Button2             // fire, player 2
+	In0Start1
+	In0Start2
+	In0Coin1
+	In0Coin2
+	In0Service
+	In0Test // clear for test mode
+)
+
+// Joystick bits for each player. Player 1 is in the low nibble of IN1
+// and player 2 is in the high nibble.
+const (
+	JoyUp = 1 << iota
+	JoyRight
+	JoyDown
+	JoyLeft
+)
+
+// Commands
+const (
+	n51xxNop = iota
+	n51xxCoinage
+	n51xxCredit
+	n51xxRemapOff
+	n51xxRemapOn
+	n51xxSwitch
+)
+
+// Joystick directions reported when remapping is enabled. The index is
+// the active low LDRU bits and the value is the direction, clockwise
+// starting with up as 0 and 8 when centered.
+var joyMap = []uint8{
+	0xf, 0xe, 0xd, 0x5, 0xc, 0x9, 0x7, 0x6,
+	0xb, 0x3, 0xa, 0x4, 0x1, 0x2, 0x0, 0x8,
+}
+
+// N51XX is the input and coin controller. In switch mode, the inputs are
+// reported as is. In credit mode, coins are counted and converted to
+// credits, start buttons use those credits, and the joysticks and fire
+// buttons are reported for each player.
 type N51XX struct {
+	IN0 uint8 // buttons, coins, service, test
+	IN1 uint8 // joysticks
+
+	Credits      int
+	CreditMode   bool
+	RemapJoy     bool
+	CoinsPerCred [2]int
+	CredsPerCoin [2]int
+
 	WatchR bool
 	WatchW bool
+
+	coins       [2]int
+	startOK     bool // start buttons enabled
+	coinage     int  // coinage values still to be received
+	readN       int
+	lastCoins   uint8
+	lastButtons uint8
 }
 
 func NewN51XX() *N51XX {
-	return &N51XX{}
+	return &N51XX{
+		IN0: 0xff,
+		IN1: 0xff,
+	}
 }
 
 func (n *N51XX) Write(v uint8) {
 	if n.WatchW {
 		log.Printf("write input controller: %02v", v)
 	}
+	v &= 0x07
+	if n.coinage > 0 {
+		switch n.coinage {
+		case 4:
+			n.CoinsPerCred[0] = int(v)
+		case 3:
+			n.CredsPerCoin[0] = int(v)
+		case 2:
+			n.CoinsPerCred[1] = int(v)
+		case 1:
+			n.CredsPerCoin[1] = int(v)
+		}
+		n.coinage--
+		return
+	}
+	switch v {
+	case n51xxNop:
+	case n51xxCoinage:
+		n.coinage = 4
+		n.Credits = 0
+	case n51xxCredit:
+		n.CreditMode = true
+		n.startOK = true
+		n.readN = 0
+	case n51xxRemapOff:
+		n.RemapJoy = false
+	case n51xxRemapOn:
+		n.RemapJoy = true
+	case n51xxSwitch:
+		n.CreditMode = false
+		n.readN = 0
+	default:
+		log.Printf("unknown input controller command: %v", v)
+	}
 }
 
 func (n *N51XX) Read() uint8 {
 	if n.WatchR {
 		log.Printf("read input controller")
 	}
-	return 0
+	i := n.readN % 3
+	n.readN++
+	if !n.CreditMode {
+		switch i {
+		case 0:
+			return n.IN0
+		case 1:
+			return n.IN1
+		}
+		return 0
+	}
+	switch i {
+	case 0:
+		return n.readCredits()
+	case 1:
+		return n.readPlayer(n.IN1&0x0f, In0Button1)
+	}
+	return n.readPlayer(n.IN1>>4, In0Button2)
+}
+
+// readCredits counts inserted coins and handles the start buttons. Returns
+// the number of credits in BCD.
+func (n *N51XX) readCredits() uint8 {
+	in := ^n.IN0
+	toggle := in ^ n.lastCoins
+	n.lastCoins = in
+	pressed := toggle & in
+
+	if n.CoinsPerCred[0] > 0 {
+		if n.Credits < 99 {
+			if pressed&In0Coin1 != 0 {
+				n.insertCoin(0)
+			}
+			if pressed&In0Coin2 != 0 {
+				n.insertCoin(1)
+			}
+			if pressed&In0Service != 0 {
+				n.Credits++
+			}
+		}
+	} else {
+		n.Credits = 100 // free play
+	}
+
+	// The start buttons are disabled once a game is started until the
+	// next credit mode command
+	if n.startOK {
+		if pressed&In0Start1 != 0 {
+			if n.Credits >= 1 {
+				n.Credits--
+				n.startOK = false
+			}
+		} else if pressed&In0Start2 != 0 {
+			if n.Credits >= 2 {
+				n.Credits -= 2
+				n.startOK = false
+			}
+		}
+	}
+
+	if in&In0Test != 0 {
+		return 0xbb
+	}
+	return uint8(n.Credits/10*16 + n.Credits%10)
+}
+
+// Save writes the credits, the mode, the coinage settings, and the
+// progress of any command or read sequence in progress.
+func (n *N51XX) Save(enc *rcs.Encoder) {
+	enc.Encode(n.Credits)
+	enc.Encode(n.CreditMode)
+	enc.Encode(n.RemapJoy)
+	enc.Encode(n.CoinsPerCred)
+	enc.Encode(n.CredsPerCoin)
+	enc.Encode(n.coins)
+	enc.Encode(n.startOK)
+	enc.Encode(n.coinage)
+	enc.Encode(n.readN)
+	enc.Encode(n.lastCoins)
+	enc.Encode(n.lastButtons)
+}
+
+// Load restores the state written by Save.
+func (n *N51XX) Load(dec *rcs.Decoder) {
+	dec.Decode(&n.Credits)
+	dec.Decode(&n.CreditMode)
+	dec.Decode(&n.RemapJoy)
+	dec.Decode(&n.CoinsPerCred)
+	dec.Decode(&n.CredsPerCoin)
+	dec.Decode(&n.coins)
+	dec.Decode(&n.startOK)
+	dec.Decode(&n.coinage)
+	dec.Decode(&n.readN)
+	dec.Decode(&n.lastCoins)
+	dec.Decode(&n.lastButtons)
+}
+
+func (n *N51XX) insertCoin(slot int) {
+	n.coins[slot]++
+	if n.coins[slot] >= n.CoinsPerCred[slot] {
+		n.Credits += n.CredsPerCoin[slot]
+		n.coins[slot] -= n.CoinsPerCred[slot]
+	}
+}
+
+// readPlayer returns the joystick in the low nibble. Bit 4 is clear
+// when the fire button was just pressed and bit 5 is clear while the
+// fire button is held down.
+func (n *N51XX) readPlayer(joy uint8, button uint8) uint8 {
+	in := ^n.IN0 & button
+	toggle := in ^ n.lastButtons&button
+	n.lastButtons = n.lastButtons&^button | in
+
+	if n.RemapJoy {
+		joy = joyMap[joy]
+	}
+	if toggle&in == 0 {
+		joy |= 1 << 4
+	}
+	if in == 0 {
+		joy |= 1 << 5
+	}
+	return joy
 }
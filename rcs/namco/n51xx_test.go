@@ -0,0 +1,134 @@
+package namco
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+func newTestN51XX() *N51XX {
+	n := NewN51XX()
+	// 1 coin, 1 credit for both slots
+	for _, v := range []uint8{n51xxCoinage, 1, 1, 1, 1, n51xxCredit} {
+		n.Write(v)
+	}
+	return n
+}
+
+func TestN51XXSwitchMode(t *testing.T) {
+	n := NewN51XX()
+	n.Write(n51xxSwitch)
+	n.IN0 = 0x12
+	n.IN1 = 0x34
+	have := []uint8{n.Read(), n.Read(), n.Read(), n.Read()}
+	want := []uint8{0x12, 0x34, 0x00, 0x12}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("\n have: % 02x \n want: % 02x", have, want)
+			break
+		}
+	}
+}
+
+func TestN51XXCoinage(t *testing.T) {
+	n := NewN51XX()
+	for _, v := range []uint8{n51xxCoinage, 2, 1, 1, 3} {
+		n.Write(v)
+	}
+	if n.CoinsPerCred != [2]int{2, 1} || n.CredsPerCoin != [2]int{1, 3} {
+		t.Errorf("unexpected coinage: %v %v", n.CoinsPerCred, n.CredsPerCoin)
+	}
+}
+
+func TestN51XXCoin(t *testing.T) {
+	n := newTestN51XX()
+	n.IN0 &^= In0Coin1
+	if have := n.Read(); have != 0x01 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x01)
+	}
+	n.Read()
+	n.Read()
+	// coin still held down, no additional credit
+	if have := n.Read(); have != 0x01 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x01)
+	}
+}
+
+func TestN51XXStart(t *testing.T) {
+	n := newTestN51XX()
+	n.Credits = 12
+	n.IN0 &^= In0Start1
+	if have := n.Read(); have != 0x11 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x11)
+	}
+}
+
+func TestN51XXFreePlay(t *testing.T) {
+	n := NewN51XX()
+	for _, v := range []uint8{n51xxCoinage, 0, 0, 0, 0, n51xxCredit} {
+		n.Write(v)
+	}
+	if have := n.Read(); have != 0xa0 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0xa0)
+	}
+}
+
+func TestN51XXJoystick(t *testing.T) {
+	n := newTestN51XX()
+	n.Write(n51xxRemapOn)
+	n.IN1 &^= JoyRight
+	n.IN0 &^= In0Button1
+	n.Read()
+	// right is direction 2, fire just pressed and held
+	if have := n.Read(); have != 0x02 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x02)
+	}
+	n.Read()
+	n.Read()
+	// fire still held
+	if have := n.Read(); have != 0x12 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x12)
+	}
+}
+
+func TestN51XXSaveLoad(t *testing.T) {
+	n := NewN51XX()
+	// 2 coins, 1 credit for both slots
+	for _, v := range []uint8{n51xxCoinage, 2, 1, 2, 1, n51xxCredit, n51xxRemapOn} {
+		n.Write(v)
+	}
+	n.Credits = 3
+	n.IN0 &^= In0Coin1
+	n.Read()
+	n.Read()
+
+	var buf bytes.Buffer
+	enc := rcs.NewEncoder(&buf)
+	n.Save(enc)
+	if enc.Err != nil {
+		t.Fatal(enc.Err)
+	}
+	n1 := NewN51XX()
+	dec := rcs.NewDecoder(&buf)
+	n1.Load(dec)
+	if dec.Err != nil {
+		t.Fatal(dec.Err)
+	}
+	if !n1.CreditMode || !n1.RemapJoy {
+		t.Errorf("mode not restored: credit %v remap %v", n1.CreditMode, n1.RemapJoy)
+	}
+	// the next read is player 2, centered with no fire
+	if have := n1.Read(); have != 0x38 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x38)
+	}
+	// the coin already in slot 1 counts towards the next credit
+	n1.IN0 = 0xff
+	n1.Read()
+	n1.Read()
+	n1.Read()
+	n1.IN0 &^= In0Coin1
+	if have := n1.Read(); have != 0x04 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x04)
+	}
+}
@@ -0,0 +1,31 @@
+package namco
+
+import "log"
+
+// N53XX is the DIP switch reader. Each read returns the next bank of
+// switches and the sequence starts over when the chip is selected for
+// reading.
+type N53XX struct {
+	Banks  []*uint8
+	WatchR bool
+
+	readN int
+}
+
+func NewN53XX(banks ...*uint8) *N53XX {
+	return &N53XX{Banks: banks}
+}
+
+func (n *N53XX) Read() uint8 {
+	v := *n.Banks[n.readN%len(n.Banks)]
+	n.readN++
+	if n.WatchR {
+		log.Printf("read dip switches: %02x\n", v)
+	}
+	return v
+}
+
+// Select restarts the sequence of banks.
+func (n *N53XX) Select() {
+	n.readN = 0
+}
@@ -0,0 +1,17 @@
+package namco
+
+import "testing"
+
+func TestN53XX(t *testing.T) {
+	dswA, dswB := uint8(0x12), uint8(0x34)
+	n := NewN53XX(&dswA, &dswB)
+	have := []uint8{n.Read(), n.Read(), n.Read()}
+	n.Select()
+	have = append(have, n.Read())
+	want := []uint8{0x12, 0x34, 0x12, 0x12}
+	for i := range want {
+		if have[i] != want[i] {
+			t.Errorf("read %v\n have: %02x \n want: %02x", i, have[i], want[i])
+		}
+	}
+}
@@ -1,20 +1,128 @@
 package namco
 
-import "log"
+import (
+	"log"
+	"math"
 
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// N54XXClock is the instruction rate of the 4-bit MCU in the 54XX. The
+// 1.536 MHz clock is divided by 6.
+const N54XXClock = 256000
+
+// Commands. The low nibble of a play command is ignored and the low nibble
+// of the volume command is the volume.
+const (
+	n54xxNop     = 0x0
+	n54xxPlayA   = 0x1
+	n54xxPlayB   = 0x2
+	n54xxParamsA = 0x3
+	n54xxParamsB = 0x4
+	n54xxPlayC   = 0x5
+	n54xxParamsC = 0x6
+	n54xxVolC    = 0x7
+)
+
+// Cutoff frequencies, in Hz, of the filters on each output. These were
+// chosen by ear and are not taken from the filter network on the board.
+var n54xxCutoff = [3]float64{400, 2000, 800}
+
+// N54XXChannel is one of the three noise outputs. Playing a sound starts
+// the amplitude at the first parameter, holds it for the duration of the
+// fourth parameter and then decays it by one step for each period given
+// in the second parameter. The third parameter divides the rate of the
+// noise. Durations are in units of 4 milliseconds. Type C has a fifth
+// parameter that is stored but not used since its effect is not known.
+//
+// Params holds the bytes as they were written. The MCU receives the full
+// byte but this model only uses the low nibble of each parameter.
+type N54XXChannel struct {
+	Params []uint8
+	Vol    uint8 // current amplitude, 4 bits
+
+	hold   int // samples until decay starts
+	decay  int // samples until the next decay step
+	frac   int
+	lfsr   uint16
+	filter float64
+	alpha  float64
+}
+
+// N54XX is the noise generator used for explosions. The original chip is
+// a Fujitsu MB8844 MCU that shapes noise into three outputs which are
+// filtered on the board. The commands below are the ones listed in the
+// namco54 device of MAME, which runs a dump of the MCU program instead of
+// describing what the parameters do.
+//
+// This is a high-level model and is not derived from that program. The
+// meaning of the parameters, the envelope, and the filters are
+// approximations.
+//
+//	$0x      nop
+//	$1x      play sound type A
+//	$2x      play sound type B
+//	$3x      set parameters for type A, followed by 4 values
+//	$4x      set parameters for type B, followed by 4 values
+//	$5x      play sound type C
+//	$6x      set parameters for type C, followed by 5 values
+//	$7x      set the volume of type C to x
+//	$8x-$fx  nop
+//
+// The volume of type C scales the output of that channel.
 type N54XX struct {
-	WatchR bool
-	WatchW bool
+	Channels [3]N54XXChannel
+	VolC     uint8
+	WatchR   bool
+	WatchW   bool
+
+	spec   sdl.AudioSpec
+	param  *N54XXChannel // channel receiving parameters
+	paramN int           // parameters still to be received
 }
 
-func NewN54XX() *N54XX {
-	return &N54XX{}
+func NewN54XX(spec sdl.AudioSpec) *N54XX {
+	n := &N54XX{spec: spec, VolC: 0x0f}
+	n.Channels[0].Params = []uint8{0xf, 0x4, 0x2, 0x0}
+	n.Channels[1].Params = []uint8{0xf, 0x2, 0x0, 0x0}
+	n.Channels[2].Params = []uint8{0xf, 0x8, 0x4, 0x0, 0x0}
+	for i := range n.Channels {
+		c := &n.Channels[i]
+		c.lfsr = 1
+		if spec.Freq > 0 {
+			c.alpha = 1 - math.Exp(-2*math.Pi*n54xxCutoff[i]/float64(spec.Freq))
+		}
+	}
+	return n
 }
 
 func (n *N54XX) Write(v uint8) {
 	if n.WatchW {
 		log.Printf("write noise generator: %02v\n", v)
 	}
+	if n.paramN > 0 {
+		c := n.param
+		c.Params[len(c.Params)-n.paramN] = v
+		n.paramN--
+		return
+	}
+	switch v >> 4 {
+	case n54xxNop:
+	case n54xxPlayA:
+		n.play(0)
+	case n54xxPlayB:
+		n.play(1)
+	case n54xxPlayC:
+		n.play(2)
+	case n54xxParamsA:
+		n.params(0)
+	case n54xxParamsB:
+		n.params(1)
+	case n54xxParamsC:
+		n.params(2)
+	case n54xxVolC:
+		n.VolC = v & 0x0f
+	}
 }
 
 func (n *N54XX) Read() uint8 {
@@ -23,3 +131,77 @@ func (n *N54XX) Read() uint8 {
 	}
 	return 0
 }
+
+func (n *N54XX) params(ch int) {
+	n.param = &n.Channels[ch]
+	n.paramN = len(n.param.Params)
+}
+
+// ms4 returns the number of output samples in v units of 4 milliseconds.
+func (n *N54XX) ms4(v uint8) int {
+	return int(n.spec.Freq) * 4 * int(v) / 1000
+}
+
+// param returns the part of parameter i that is used by the model.
+func (c *N54XXChannel) param(i int) uint8 {
+	return c.Params[i] & 0x0f
+}
+
+func (n *N54XX) play(ch int) {
+	c := &n.Channels[ch]
+	c.Vol = c.param(0)
+	c.hold = n.ms4(c.param(3))
+	c.decay = n.ms4(c.param(1) + 1)
+}
+
+// Mix adds the output of the noise generator to the samples in out.
+func (n *N54XX) Mix(out []float64) {
+	rate := int(n.spec.Freq)
+	if rate == 0 {
+		return
+	}
+	for i := range n.Channels {
+		c := &n.Channels[i]
+		if c.Vol == 0 && c.filter == 0 {
+			continue
+		}
+		noiseRate := N54XXClock / (int(c.param(2)) + 1) / 8
+		gain := 1.0
+		if i == 2 {
+			gain = float64(n.VolC) / 15
+		}
+		for j := range out {
+			c.frac += noiseRate
+			for c.frac >= rate {
+				c.frac -= rate
+				bit := (c.lfsr ^ c.lfsr>>1) & 1
+				c.lfsr = c.lfsr>>1 | bit<<14
+			}
+			sample := 0.0
+			if c.Vol > 0 {
+				sample = gain * float64(c.Vol) / 15
+				if c.lfsr&1 == 0 {
+					sample = -sample
+				}
+				n.envelope(c)
+			}
+			c.filter += c.alpha * (sample - c.filter)
+			if c.Vol == 0 && math.Abs(c.filter) < 1e-6 {
+				c.filter = 0
+			}
+			out[j] += c.filter / 3
+		}
+	}
+}
+
+func (n *N54XX) envelope(c *N54XXChannel) {
+	if c.hold > 0 {
+		c.hold--
+		return
+	}
+	c.decay--
+	if c.decay <= 0 {
+		c.Vol--
+		c.decay = n.ms4(c.param(1) + 1)
+	}
+}
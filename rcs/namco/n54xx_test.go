@@ -0,0 +1,75 @@
+package namco
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestN54XXParams(t *testing.T) {
+	n := NewN54XX(sdl.AudioSpec{Freq: 48000})
+	for _, v := range []uint8{0x30, 0xf8, 0x01, 0x02, 0x53} {
+		n.Write(v)
+	}
+	// parameters are kept as written
+	want := []uint8{0xf8, 0x1, 0x2, 0x53}
+	for i, v := range want {
+		if n.Channels[0].Params[i] != v {
+			t.Errorf("param %v\n have: %02x \n want: %02x", i, n.Channels[0].Params[i], v)
+		}
+	}
+	// parameters are done, this plays the sound
+	n.Write(0x10)
+	if n.Channels[0].Vol != 0x8 {
+		t.Errorf("\n have: %02x \n want: %02x", n.Channels[0].Vol, 0x8)
+	}
+}
+
+func TestN54XXVolC(t *testing.T) {
+	n := NewN54XX(sdl.AudioSpec{Freq: 48000})
+	for _, v := range []uint8{0x60, 0x0a, 0x01, 0x00, 0x02, 0x03} {
+		n.Write(v)
+	}
+	n.Write(0x75)
+	n.Write(0x50)
+	// the amplitude starts at the first parameter and the volume is kept
+	// separately
+	if n.Channels[2].Vol != 0xa {
+		t.Errorf("\n have: %02x \n want: %02x", n.Channels[2].Vol, 0xa)
+	}
+	if n.VolC != 0x5 {
+		t.Errorf("\n have: %02x \n want: %02x", n.VolC, 0x5)
+	}
+	if n.Channels[2].Params[4] != 0x3 {
+		t.Errorf("\n have: %02x \n want: %02x", n.Channels[2].Params[4], 0x3)
+	}
+
+	// silence when the volume is zero
+	n.Write(0x70)
+	out := make([]float64, 100)
+	n.Mix(out)
+	for i, v := range out {
+		if v != 0 {
+			t.Fatalf("sample %v: have %v, want 0", i, v)
+		}
+	}
+}
+
+func TestN54XXDecay(t *testing.T) {
+	n := NewN54XX(sdl.AudioSpec{Freq: 48000})
+	n.Write(0x20)
+	out := make([]float64, 48000)
+	n.Mix(out)
+	loud := false
+	for _, v := range out[:1000] {
+		if v != 0 {
+			loud = true
+		}
+	}
+	if !loud {
+		t.Errorf("expected noise")
+	}
+	if n.Channels[1].Vol != 0 {
+		t.Errorf("expected silence after one second but volume is %v", n.Channels[1].Vol)
+	}
+}
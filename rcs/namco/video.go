@@ -9,18 +9,33 @@ import (
 )
 
 type Data struct {
-	Palettes []uint8
-	Colors   []uint8
-	Tiles    []uint8
-	Sprites  []uint8
+	Palettes       []uint8
+	SpritePalettes []uint8 // if nil, sprites use Palettes
+	Colors         []uint8
+	Tiles          []uint8
+	Sprites        []uint8
 }
 
+// SpriteSystem is the layout of the sprite attributes in memory.
+type SpriteSystem int
+
+const (
+	// PacmanSprites are the 8 sprites found on the Pac-Man board
+	PacmanSprites SpriteSystem = iota
+	// GalagaSprites are the 64 sprites found on the Galaga board
+	GalagaSprites
+)
+
 type Config struct {
 	TileLayout     SheetLayout
 	SpriteLayout   SheetLayout
 	PaletteEntries int
 	PaletteColors  int
-	Hack           bool
+	Colors         int       // number of entries in the color PROM
+	ColorWeights   [][]uint8 // RGB weight of each bit, Pac-Man if nil
+	Transparent    []int     // colors that are not drawn
+	TileColorBase  int       // tile palettes refer to colors starting here
+	Sprites        SpriteSystem
 }
 
 const (
@@ -39,6 +54,14 @@ type Video struct {
 	SpritePalettes []uint8
 	TileMemory     []uint8
 	ColorMemory    []uint8
+	Flip           bool // rotate the screen 180 degrees
+
+	// Galaga sprite attributes. Each bank has two bytes for each of the
+	// 64 sprites.
+	SpriteRAM [3][]uint8
+
+	// Drawn behind the tiles and sprites if not nil
+	Starfield *N05XX
 
 	Texture  *sdl.Texture
 	config   Config
@@ -54,7 +77,11 @@ func NewVideo(r *sdl.Renderer, config Config, data Data) (*Video, error) {
 		return nil, err
 	}
 	colors := ColorTable(config, data.Colors)
-	palettes := PaletteTable(config, data.Palettes, colors)
+	palettes := PaletteTable(config, data.Palettes, colors[config.TileColorBase:])
+	spritePalettes := palettes
+	if data.SpritePalettes != nil {
+		spritePalettes = PaletteTable(config, data.SpritePalettes, colors)
+	}
 
 	var tiles, sprites [64]rcs.TileSheet
 	for pal := 0; pal < config.PaletteEntries; pal++ {
@@ -64,7 +91,7 @@ func NewVideo(r *sdl.Renderer, config Config, data Data) (*Video, error) {
 		}
 		tiles[pal] = t
 
-		s, err := NewTileSheet(r, data.Sprites, config.SpriteLayout, palettes[pal])
+		s, err := NewTileSheet(r, data.Sprites, config.SpriteLayout, spritePalettes[pal])
 		if err != nil {
 			return nil, err
 		}
@@ -91,8 +118,16 @@ func (v *Video) Draw(r *sdl.Renderer) error {
 	r.SetRenderTarget(v.Texture)
 	r.SetDrawColorArray(0, 0, 0, 0xff)
 	r.Clear()
+	if v.Starfield != nil {
+		v.Starfield.Draw(r, v.Flip)
+	}
 	v.drawTiles(r)
-	v.drawSprites(r)
+	switch v.config.Sprites {
+	case GalagaSprites:
+		DrawSprites(r, v.sprites[:], v.config.SpriteLayout, v.SpriteRAM, v.Flip, GalagaSprite)
+	default:
+		v.drawSprites(r)
+	}
 	r.SetRenderTarget(nil)
 	return nil
 }
@@ -134,17 +169,19 @@ func (v *Video) drawTiles(r *sdl.Renderer) error {
 
 			// Only 64 palettes, strip out the higher bits
 			pal := v.ColorMemory[addr] & 0x3f
-			r.Copy(v.tiles[pal].Texture, &src, &dest)
+			if v.Flip {
+				flipRect(&dest)
+				r.CopyEx(v.tiles[pal].Texture, &src, &dest, 0, nil,
+					sdl.FLIP_HORIZONTAL|sdl.FLIP_VERTICAL)
+			} else {
+				r.Copy(v.tiles[pal].Texture, &src, &dest)
+			}
 		}
 	}
 	return nil
 }
 
 func (v *Video) drawSprites(r *sdl.Renderer) error {
-	// FIXME: Galaga testing
-	if v.config.Hack {
-		return nil
-	}
 	layout := v.config.SpriteLayout
 	spriteW := layout.TileW
 	spriteH := layout.TileH
@@ -183,6 +220,10 @@ func (v *Video) drawSprites(r *sdl.Renderer) error {
 			W: spriteW,
 			H: spriteH,
 		}
+		if v.Flip {
+			flipRect(&dest)
+			flip ^= sdl.FLIP_HORIZONTAL | sdl.FLIP_VERTICAL
+		}
 		// Only 64 palettes, strip out the higher bits
 		pal := v.SpritePalettes[s] & 0x3f
 		r.CopyEx(v.sprites[pal].Texture, &src, &dest, 0, nil, flip)
@@ -190,6 +231,114 @@ func (v *Video) drawSprites(r *sdl.Renderer) error {
 	return nil
 }
 
+// Sprite contains the attributes of one of the 64 sprites on the Galaga
+// board. Positions are for the monitor before it is rotated into the
+// cabinet. A sprite can be double width or double height and uses the
+// next sprites in the sheet for the other parts.
+type Sprite struct {
+	N     int32 // sprite number of the first part
+	Pal   uint8
+	X     int32
+	Y     int32
+	FlipX bool
+	FlipY bool
+	SizeX int32 // 1 if double width
+	SizeY int32 // 1 if double height
+	WrapX bool  // parts past the right edge wrap around to the left
+}
+
+// SpriteDecoder returns the attributes for the sprite at offset offs in
+// each bank of sprite RAM. The layout of the attributes is different for
+// each game.
+type SpriteDecoder func(ram [3][]uint8, offs int) Sprite
+
+// GalagaSprite decodes the sprite attributes for Galaga:
+//
+//	bank 0: sprite number, palette
+//	bank 1: y position, x position
+//	bank 2: flip and size, bit 8 of the x position
+func GalagaSprite(ram [3][]uint8, offs int) Sprite {
+	bank0, bank1, bank2 := ram[0], ram[1], ram[2]
+	return Sprite{
+		N:     int32(bank0[offs] & 0x7f),
+		Pal:   bank0[offs+1] & 0x3f,
+		X:     int32(bank1[offs+1]) - 40 + 0x100*int32(bank2[offs+1]&0x03),
+		Y:     256 - int32(bank1[offs]) + 1,
+		FlipX: bank2[offs]&0x01 != 0,
+		FlipY: bank2[offs]&0x02 != 0,
+		SizeX: int32(bank2[offs]>>2) & 1,
+		SizeY: int32(bank2[offs]>>3) & 1,
+	}
+}
+
+// DrawSprites draws the 64 sprites found in the three banks of sprite
+// RAM. Each sprite is decoded with the given function. Set flipped when
+// the screen is rotated 180 degrees.
+func DrawSprites(r *sdl.Renderer, sheets []rcs.TileSheet, layout SheetLayout, ram [3][]uint8, flipped bool, decode SpriteDecoder) {
+	spriteW := layout.TileW
+	spriteH := layout.TileH
+	rowTiles := layout.TextureW / spriteW
+
+	for offs := 0; offs < 0x80; offs += 2 {
+		s := decode(ram, offs)
+		sy := s.Y - spriteH*s.SizeY
+		sy = (sy & 0xff) - 32
+
+		// The monitor is rotated so a flip in x is a vertical flip on
+		// the screen and a flip in y is horizontal.
+		flip := sdl.FLIP_NONE
+		if s.FlipX {
+			flip |= sdl.FLIP_VERTICAL
+		}
+		if s.FlipY {
+			flip |= sdl.FLIP_HORIZONTAL
+		}
+		if flipped {
+			flip ^= sdl.FLIP_HORIZONTAL | sdl.FLIP_VERTICAL
+		}
+
+		for y := int32(0); y <= s.SizeY; y++ {
+			for x := int32(0); x <= s.SizeX; x++ {
+				partX, partY := x, y
+				if s.FlipX {
+					partX ^= s.SizeX
+				}
+				if s.FlipY {
+					partY ^= s.SizeY
+				}
+				n := s.N + partY*2 + partX
+				src := sdl.Rect{
+					X: (n % rowTiles) * spriteW,
+					Y: (n / rowTiles) * spriteH,
+					W: spriteW,
+					H: spriteH,
+				}
+				destY := s.X + x*spriteW
+				if s.WrapX {
+					destY &= 0xff
+				}
+				dest := sdl.Rect{
+					X: W - (sy + y*spriteH) - spriteW,
+					Y: destY,
+					W: spriteW,
+					H: spriteH,
+				}
+				if flipped {
+					flipRect(&dest)
+				}
+				r.CopyEx(sheets[s.Pal].Texture, &src, &dest, 0, nil, flip)
+			}
+		}
+	}
+}
+
+// flipRect moves the destination to where it appears when the screen is
+// rotated 180 degrees.
+func flipRect(dest *sdl.Rect) {
+	dest.X = W - dest.X - dest.W
+	dest.Y = H - dest.Y - dest.H
+}
+
 func (v *Video) Save(enc *rcs.Encoder) {
 	enc.Encode(v.TileMemory)
 	enc.Encode(v.ColorMemory)
@@ -255,27 +404,25 @@ func NewTileSheet(r *sdl.Renderer, d []byte, l SheetLayout, pal []color.RGBA) (r
 }
 
 func ColorTable(config Config, data []uint8) []color.RGBA {
-	// FIXME: Galaga testing
-	if config.Hack {
-		return []color.RGBA{}
+	weights := config.ColorWeights
+	if weights == nil {
+		weights = colorWeights
 	}
-	colors := make([]color.RGBA, 16, 16)
-	for addr := 0; addr < 16; addr++ {
+	colors := make([]color.RGBA, config.Colors, config.Colors)
+	for addr := 0; addr < config.Colors; addr++ {
 		r, g, b := uint8(0), uint8(0), uint8(0)
 		c := data[addr]
 		for bit := uint8(0); bit < 8; bit++ {
 			if c&(1<<bit) != 0 {
-				r += colorWeights[bit][0]
-				g += colorWeights[bit][1]
-				b += colorWeights[bit][2]
+				r += weights[bit][0]
+				g += weights[bit][1]
+				b += weights[bit][2]
 			}
 		}
-		alpha := uint8(0xff)
-		// Color 0 is actually transparent
-		if addr == 0 {
-			alpha = 0x00
-		}
-		colors[addr] = color.RGBA{r, g, b, alpha}
+		colors[addr] = color.RGBA{r, g, b, 0xff}
+	}
+	for _, addr := range config.Transparent {
+		colors[addr].A = 0x00
 	}
 	return colors
 }
@@ -283,11 +430,6 @@ func ColorTable(config Config, data []uint8) []color.RGBA {
 func PaletteTable(config Config, data []uint8, colors []color.RGBA) [][]color.RGBA {
 	palettes := make([][]color.RGBA, config.PaletteEntries, config.PaletteEntries)
 	for pal := 0; pal < config.PaletteEntries; pal++ {
-		// FIXME: Galaga testing
-		if config.Hack {
-			palettes[pal] = ViewerPalette
-			continue
-		}
 		addr := pal * config.PaletteColors
 		entry := make([]color.RGBA, config.PaletteColors, config.PaletteColors)
 		for i := 0; i < config.PaletteColors; i++ {
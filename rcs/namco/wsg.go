@@ -0,0 +1,244 @@
+package namco
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// WSGClock is the rate of the waveform sound generator. The 3.072 MHz
+// clock is divided by 32.
+const WSGClock = 96000
+
+// The generator output is low-pass filtered with a windowed-sinc filter
+// before it is decimated to the output rate. The filter has wsgTaps taps
+// at the internal rate. The output instant falls between two ticks of
+// the generator and the distance from the last tick selects one of
+// wsgPhases sets of coefficients.
+const (
+	wsgTaps   = 64
+	wsgPhases = 32
+)
+
+// WSGVoice is one of the three voices in the waveform sound generator.
+// The accumulator and frequency are 20 bits wide and are written four
+// bits at a time. Voices 1 and 2 do not have registers for the lowest
+// four bits which are always zero.
+type WSGVoice struct {
+	Acc      uint32
+	Freq     uint32
+	Waveform uint8 // 3 bits
+	Vol      uint8 // 4 bits
+}
+
+// Mixer adds its output to the samples generated by another sound chip.
+type Mixer interface {
+	Mix(out []float64)
+}
+
+// WSG is the 3-voice waveform sound generator used on Pac-Man and other
+// Namco boards of that era. Each voice adds its frequency to its
+// accumulator at the rate of WSGClock and the top 5 bits of the
+// accumulator select one of the 32 samples in the waveform.
+type WSG struct {
+	Voices [3]WSGVoice
+	Enable uint8   // sound enable, low bit
+	Mixers []Mixer // other chips sharing the audio output
+
+	waves   [8][32]uint8
+	spec    sdl.AudioSpec
+	frac    int         // internal clock ticks pending for the next sample
+	hist    []float64   // last wsgTaps samples at the internal rate
+	pos     int         // index of the newest sample in hist
+	taps    [][]float64 // filter coefficients for each phase, nil if not decimating
+	samples []float64
+	data    []byte
+}
+
+// NewWSG creates a sound generator with the waveforms found in rom.
+// Samples are generated at the frequency in spec.
+func NewWSG(rom []uint8, spec sdl.AudioSpec) (*WSG, error) {
+	if len(rom) < 0x100 {
+		return nil, fmt.Errorf("expecting waveform ROM of at least 256 bytes but got %v", len(rom))
+	}
+	w := &WSG{spec: spec, hist: make([]float64, wsgTaps)}
+	if spec.Freq > 0 && spec.Freq < WSGClock {
+		w.taps = wsgFilter(int(spec.Freq))
+	}
+	for i := 0; i < 8; i++ {
+		for j := 0; j < 32; j++ {
+			w.waves[i][j] = rom[i*32+j] & 0x0f
+		}
+	}
+	return w, nil
+}
+
+// Write stores the value to the register at the offset. Only the lower
+// four bits are used.
+//
+//	$00-$04  voice 0 accumulator
+//	$05      voice 0 waveform
+//	$06-$09  voice 1 accumulator
+//	$0a      voice 1 waveform
+//	$0b-$0e  voice 2 accumulator
+//	$0f      voice 2 waveform
+//	$10-$14  voice 0 frequency
+//	$15      voice 0 volume
+//	$16-$19  voice 1 frequency
+//	$1a      voice 1 volume
+//	$1b-$1e  voice 2 frequency
+//	$1f      voice 2 volume
+func (w *WSG) Write(offset int, v uint8) {
+	v &= 0x0f
+	freq := offset&0x10 != 0
+	reg := offset & 0x0f
+
+	// nibble is -1 for the waveform and volume registers
+	var voice, nibble int
+	switch {
+	case reg <= 0x04:
+		voice, nibble = 0, reg
+	case reg == 0x05:
+		voice, nibble = 0, -1
+	case reg <= 0x09:
+		voice, nibble = 1, reg-0x06+1
+	case reg == 0x0a:
+		voice, nibble = 1, -1
+	case reg <= 0x0e:
+		voice, nibble = 2, reg-0x0b+1
+	default:
+		voice, nibble = 2, -1
+	}
+
+	vo := &w.Voices[voice]
+	shift := uint(nibble * 4)
+	switch {
+	case nibble < 0 && !freq:
+		vo.Waveform = v & 0x07
+	case nibble < 0:
+		vo.Vol = v
+	case !freq:
+		vo.Acc = vo.Acc&^(0xf<<shift) | uint32(v)<<shift
+	default:
+		vo.Freq = vo.Freq&^(0xf<<shift) | uint32(v)<<shift
+	}
+}
+
+// tick advances the accumulators by one clock and returns the mixed output
+// of all voices from -1 to 1.
+func (w *WSG) tick() float64 {
+	sum := 0
+	for i := range w.Voices {
+		v := &w.Voices[i]
+		v.Acc = (v.Acc + v.Freq) & 0xfffff
+		if v.Vol == 0 || v.Freq == 0 {
+			continue
+		}
+		sample := int(w.waves[v.Waveform][v.Acc>>15]) - 8
+		sum += sample * int(v.Vol)
+	}
+	if w.Enable&1 == 0 {
+		return 0
+	}
+	return float64(sum) / (3 * 8 * 15)
+}
+
+// Fill generates samples at the output rate. The generator runs at its
+// internal rate and is filtered to attenuate the frequencies above half
+// of the output rate before it is decimated. Some of those frequencies
+// still alias into the output near the cutoff.
+func (w *WSG) Fill(out []float64) {
+	rate := int(w.spec.Freq)
+	if rate == 0 {
+		return
+	}
+	for i := range out {
+		w.frac += WSGClock
+		for w.frac >= rate {
+			w.frac -= rate
+			w.pos = (w.pos + 1) % wsgTaps
+			w.hist[w.pos] = w.tick()
+		}
+		if w.taps == nil {
+			out[i] = w.hist[w.pos]
+			continue
+		}
+		taps := w.taps[w.frac*wsgPhases/rate]
+		sum := 0.0
+		for k, c := range taps {
+			sum += c * w.hist[(w.pos-k+wsgTaps)%wsgTaps]
+		}
+		out[i] = sum
+	}
+}
+
+// wsgFilter returns the coefficients of a low-pass filter with a cutoff
+// just below half of the output rate, windowed with a Blackman window.
+// Coefficient k of each phase is applied to the kth newest sample and
+// each phase is normalized for unity gain.
+func wsgFilter(rate int) [][]float64 {
+	fc := 0.45 * float64(rate) / WSGClock
+	taps := make([][]float64, wsgPhases)
+	for p := range taps {
+		taps[p] = make([]float64, wsgTaps)
+		sum := 0.0
+		for k := range taps[p] {
+			// distance from the output instant to the sample
+			x := float64(k) + float64(p)/wsgPhases
+			n := x / wsgTaps
+			win := 0.42 - 0.5*math.Cos(2*math.Pi*n) + 0.08*math.Cos(4*math.Pi*n)
+			c := 2 * fc
+			if t := x - wsgTaps/2; t != 0 {
+				c = math.Sin(2*math.Pi*fc*t) / (math.Pi * t)
+			}
+			taps[p][k] = c * win
+			sum += taps[p][k]
+		}
+		for k := range taps[p] {
+			taps[p][k] /= sum
+		}
+	}
+	return taps
+}
+
+// Queue fills the SDL audio queue with enough samples to keep it from
+// running dry.
+func (w *WSG) Queue() error {
+	max := int(w.spec.Samples) * 5
+	q := int(sdl.GetQueuedAudioSize(1) / 4)
+	n := max - q
+	if n <= 0 {
+		return nil
+	}
+	if len(w.samples) < max {
+		w.samples = make([]float64, max)
+		w.data = make([]byte, max*4)
+	}
+	w.Fill(w.samples[:n])
+	for _, m := range w.Mixers {
+		m.Mix(w.samples[:n])
+	}
+	for i, d := 0, 0; i < n; i, d = i+1, d+4 {
+		v := math.Max(-1, math.Min(1, w.samples[i]))
+		sample := int16(v * ((1 << 15) - 1))
+		w.data[d+0] = byte(sample)
+		w.data[d+1] = byte(sample >> 8)
+		w.data[d+2] = byte(sample)
+		w.data[d+3] = byte(sample >> 8)
+	}
+	return sdl.QueueAudio(1, w.data[:n*4])
+}
+
+// Save writes the voice registers and the sound enable.
+func (w *WSG) Save(enc *rcs.Encoder) {
+	enc.Encode(w.Voices)
+	enc.Encode(w.Enable)
+}
+
+// Load restores the voice registers and the sound enable.
+func (w *WSG) Load(dec *rcs.Decoder) {
+	dec.Decode(&w.Voices)
+	dec.Decode(&w.Enable)
+}
@@ -0,0 +1,120 @@
+package namco
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func newTestWSG(t *testing.T, rate int32) *WSG {
+	rom := make([]uint8, 0x100)
+	// waveform 0 is a square wave
+	for i := 0; i < 16; i++ {
+		rom[i] = 0x0f
+	}
+	w, err := NewWSG(rom, sdl.AudioSpec{Freq: rate})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestWSGWrite(t *testing.T) {
+	w := newTestWSG(t, WSGClock)
+	for i := 0; i < 5; i++ {
+		w.Write(0x10+i, uint8(i+1))
+	}
+	for i := 0; i < 4; i++ {
+		w.Write(0x16+i, uint8(i+1))
+		w.Write(0x0b+i, uint8(i+5))
+	}
+	w.Write(0x0a, 0xff)
+	w.Write(0x1f, 0x0c)
+
+	tests := []struct {
+		name string
+		have uint32
+		want uint32
+	}{
+		{"voice 0 freq", w.Voices[0].Freq, 0x54321},
+		{"voice 1 freq", w.Voices[1].Freq, 0x43210},
+		{"voice 2 acc", w.Voices[2].Acc, 0x87650},
+		{"voice 1 waveform", uint32(w.Voices[1].Waveform), 0x07},
+		{"voice 2 vol", uint32(w.Voices[2].Vol), 0x0c},
+	}
+	for _, test := range tests {
+		if test.have != test.want {
+			t.Errorf("%v\n have: %05x \n want: %05x", test.name, test.have, test.want)
+		}
+	}
+}
+
+func TestWSGPitch(t *testing.T) {
+	w := newTestWSG(t, WSGClock)
+	w.Enable = 1
+	w.Voices[0].Freq = 0x8000 // one sample per clock, 32 clocks per cycle
+	w.Voices[0].Vol = 0x0f
+	out := make([]float64, 64)
+	w.Fill(out)
+	for i, v := range out {
+		// the accumulator is advanced before the sample is taken
+		high := (i+1)%32 < 16
+		if high && v <= 0 || !high && v >= 0 {
+			t.Fatalf("unexpected sample %v at %v", v, i)
+		}
+	}
+}
+
+func TestWSGFilter(t *testing.T) {
+	w := newTestWSG(t, 22050)
+	w.Enable = 1
+	w.Voices[0].Freq = 0x40000 // 24 kHz, above the output Nyquist frequency
+	w.Voices[0].Vol = 0x0f
+	out := make([]float64, 256)
+	w.Fill(out)
+	// Only the DC offset of the square wave should remain
+	dc := (7.0 - 8.0) / 2 * 15 / (3 * 8 * 15)
+	for i, v := range out[wsgTaps:] {
+		if math.Abs(v-dc) > 0.001 {
+			t.Fatalf("unexpected sample %v at %v", v, i+wsgTaps)
+		}
+	}
+}
+
+func TestWSGDisabled(t *testing.T) {
+	w := newTestWSG(t, 22050)
+	w.Voices[0].Freq = 0x8000
+	w.Voices[0].Vol = 0x0f
+	out := make([]float64, 64)
+	w.Fill(out)
+	for i, v := range out {
+		if v != 0 {
+			t.Fatalf("unexpected sample %v at %v", v, i)
+		}
+	}
+}
+
+func TestWSGSaveLoad(t *testing.T) {
+	w := newTestWSG(t, WSGClock)
+	w.Voices[1] = WSGVoice{Acc: 0x12345, Freq: 0x6780, Waveform: 5, Vol: 0x0c}
+	w.Enable = 1
+
+	var buf bytes.Buffer
+	enc := rcs.NewEncoder(&buf)
+	w.Save(enc)
+	if enc.Err != nil {
+		t.Fatal(enc.Err)
+	}
+	w1 := newTestWSG(t, WSGClock)
+	dec := rcs.NewDecoder(&buf)
+	w1.Load(dec)
+	if dec.Err != nil {
+		t.Fatal(dec.Err)
+	}
+	if w1.Voices != w.Voices || w1.Enable != w.Enable {
+		t.Errorf("\n have: %+v %v \n want: %+v %v", w1.Voices, w1.Enable, w.Voices, w.Enable)
+	}
+}
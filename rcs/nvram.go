@@ -0,0 +1,92 @@
+package rcs
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// NVRAMRange is a block of memory that is kept between sessions. The
+// values of the first and last bytes are known after the game has
+// initialized the block and are used to check that the memory is
+// ready to be restored. These are the same as the entries found in
+// MAME's hiscore.dat.
+type NVRAMRange struct {
+	Addr  int
+	Len   int
+	Start uint8 // value of the first byte when initialized
+	End   uint8 // value of the last byte when initialized
+}
+
+// NVRAM describes the memory that is saved when the machine quits and
+// restored on the next run. Most arcade boards of this era did not have
+// battery backed memory so this is mostly used to keep high scores.
+//
+// Boards that do have non-volatile memory set Persistent. That memory is
+// restored before the machine starts and the start and end values of the
+// ranges are not checked.
+type NVRAM struct {
+	Name       string // name of the file in the variable directory
+	Mem        *Memory
+	Ranges     []NVRAMRange
+	Persistent bool
+}
+
+// Valid returns true when each range contains its start and end values.
+func (n *NVRAM) Valid() bool {
+	if n.Persistent {
+		return true
+	}
+	for _, r := range n.Ranges {
+		if n.Mem.Read(r.Addr) != r.Start {
+			return false
+		}
+		if n.Mem.Read(r.Addr+r.Len-1) != r.End {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *NVRAM) size() int {
+	size := 0
+	for _, r := range n.Ranges {
+		size += r.Len
+	}
+	return size
+}
+
+// Save writes the contents of all ranges to filename.
+func (n *NVRAM) Save(filename string) error {
+	data := make([]uint8, 0, n.size())
+	for _, r := range n.Ranges {
+		for i := 0; i < r.Len; i++ {
+			data = append(data, n.Mem.Read(r.Addr+i))
+		}
+	}
+	return ioutil.WriteFile(filename, data, 0644)
+}
+
+// Load restores the contents of all ranges from filename. It is not an
+// error if the file does not exist.
+func (n *NVRAM) Load(filename string) error {
+	data, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if len(data) != n.size() {
+		return fmt.Errorf("%v: expected %v bytes but found %v", filename,
+			n.size(), len(data))
+	}
+	i := 0
+	for _, r := range n.Ranges {
+		for j := 0; j < r.Len; j++ {
+			n.Mem.Write(r.Addr+j, data[i])
+			i++
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package rcs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestNVRAM() *NVRAM {
+	mem := NewMemory(1, 0x100)
+	mem.MapRAM(0, make([]uint8, 0x100, 0x100))
+	return &NVRAM{
+		Mem: mem,
+		Ranges: []NVRAMRange{
+			{Addr: 0x10, Len: 3, Start: 0x00, End: 0x00},
+			{Addr: 0x40, Len: 2, Start: 0x40, End: 0x40},
+		},
+	}
+}
+
+func TestNVRAMValid(t *testing.T) {
+	n := newTestNVRAM()
+	if n.Valid() {
+		t.Fatalf("expected invalid before initialization")
+	}
+	n.Mem.WriteN(0x40, 0x40, 0x40)
+	if !n.Valid() {
+		t.Fatalf("expected valid after initialization")
+	}
+}
+
+func TestNVRAMSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "hiscore")
+
+	n := newTestNVRAM()
+	n.Mem.WriteN(0x10, 0x01, 0x02, 0x03)
+	n.Mem.WriteN(0x40, 0x04, 0x05)
+	if err := n.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	n = newTestNVRAM()
+	if err := n.Load(filename); err != nil {
+		t.Fatal(err)
+	}
+	want := []uint8{0x01, 0x02, 0x03, 0x04, 0x05}
+	have := []uint8{
+		n.Mem.Read(0x10), n.Mem.Read(0x11), n.Mem.Read(0x12),
+		n.Mem.Read(0x40), n.Mem.Read(0x41),
+	}
+	for i := range want {
+		if want[i] != have[i] {
+			t.Errorf("\n have: % 02x \n want: % 02x", have, want)
+			break
+		}
+	}
+}
+
+func TestNVRAMLoadMissing(t *testing.T) {
+	n := newTestNVRAM()
+	if err := n.Load(filepath.Join(os.TempDir(), "rcs-does-not-exist")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNVRAMLoadWrongSize(t *testing.T) {
+	f, err := ioutil.TempFile("", "rcs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+	f.Write([]uint8{1, 2, 3})
+	f.Close()
+
+	n := newTestNVRAM()
+	if err := n.Load(f.Name()); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestNVRAMPersistent(t *testing.T) {
+	n := newTestNVRAM()
+	n.Persistent = true
+	if !n.Valid() {
+		t.Fatalf("expected persistent memory to always be valid")
+	}
+}
@@ -2,8 +2,17 @@ package z80
 
 func (c *CPU) storeNil(v uint8) {}
 
-func (c *CPU) storeIndImm(v uint8) { c.mem.Write(c.fetch2(), v) }
-func (c *CPU) store16IndImm(v int) { c.mem.WriteLE(c.fetch2(), v) }
+func (c *CPU) storeIndImm(v uint8) {
+	addr := c.fetch2()
+	c.mem.Write(addr, v)
+	c.WZ = uint16(c.A)<<8 | uint16(addr+1)&0xff
+}
+
+func (c *CPU) store16IndImm(v int) {
+	addr := c.fetch2()
+	c.mem.WriteLE(addr, v)
+	c.WZ = uint16(addr + 1)
+}
 
 func (c *CPU) storeA(v uint8)   { c.A = v }
 func (c *CPU) storeF(v uint8)   { c.F = v }
@@ -46,14 +55,30 @@ func (c *CPU) storeHL1(v int) { c.H1, c.L1 = uint8(v>>8), uint8(v) }
 
 func (c *CPU) storeIndHL(v uint8) { c.mem.Write(int(c.H)<<8|int(c.L), v) }
 
-func (c *CPU) storeIndBC(v uint8) { c.mem.Write(int(c.B)<<8|int(c.C), v) }
-func (c *CPU) storeIndDE(v uint8) { c.mem.Write(int(c.D)<<8|int(c.E), v) }
+func (c *CPU) storeIndBC(v uint8) { c.storeIndRP(c.B, c.C, v) }
+func (c *CPU) storeIndDE(v uint8) { c.storeIndRP(c.D, c.E, v) }
 
-func (c *CPU) loadZero() uint8   { return 0 }
-func (c *CPU) loadImm() uint8    { return c.fetch() }
-func (c *CPU) loadImm16() int    { return c.fetch2() }
-func (c *CPU) loadIndImm() uint8 { return c.mem.Read(c.fetch2()) }
-func (c *CPU) load16IndImm() int { return c.mem.ReadLE(c.fetch2()) }
+// Only used to store the accumulator
+func (c *CPU) storeIndRP(hi uint8, lo uint8, v uint8) {
+	c.mem.Write(int(hi)<<8|int(lo), v)
+	c.WZ = uint16(c.A)<<8 | uint16(lo+1)
+}
+
+func (c *CPU) loadZero() uint8 { return 0 }
+func (c *CPU) loadImm() uint8  { return c.fetch() }
+func (c *CPU) loadImm16() int  { return c.fetch2() }
+
+func (c *CPU) loadIndImm() uint8 {
+	addr := c.fetch2()
+	c.WZ = uint16(addr + 1)
+	return c.mem.Read(addr)
+}
+
+func (c *CPU) load16IndImm() int {
+	addr := c.fetch2()
+	c.WZ = uint16(addr + 1)
+	return c.mem.ReadLE(addr)
+}
 
 func (c *CPU) loadA() uint8    { return c.A }
 func (c *CPU) loadF() uint8    { return c.F }
@@ -69,7 +94,7 @@ func (c *CPU) loadIXL() uint8  { return c.IXL }
 func (c *CPU) loadIXH() uint8  { return c.IXH }
 func (c *CPU) loadIYL() uint8  { return c.IYL }
 func (c *CPU) loadIYH() uint8  { return c.IYH }
-func (c *CPU) loadIndC() uint8 { return c.Ports.Read(int(c.C)) }
+func (c *CPU) loadIndC() uint8 { return c.inIndC() }
 
 func (c *CPU) loadA1() uint8 { return c.A1 }
 func (c *CPU) loadF1() uint8 { return c.F1 }
@@ -80,14 +105,20 @@ func (c *CPU) loadE1() uint8 { return c.E1 }
 func (c *CPU) loadH1() uint8 { return c.H1 }
 func (c *CPU) loadL1() uint8 { return c.L1 }
 
-func (c *CPU) loadAF() int      { return int(c.A)<<8 | int(c.F) }
-func (c *CPU) loadBC() int      { return int(c.B)<<8 | int(c.C) }
-func (c *CPU) loadDE() int      { return int(c.D)<<8 | int(c.E) }
-func (c *CPU) loadHL() int      { return int(c.H)<<8 | int(c.L) }
-func (c *CPU) loadSP() int      { return int(c.SP) }
-func (c *CPU) loadIX() int      { return int(c.IXH)<<8 | int(c.IXL) }
-func (c *CPU) loadIY() int      { return int(c.IYH)<<8 | int(c.IYL) }
-func (c *CPU) load16IndSP() int { return c.mem.ReadLE(int(c.SP)) }
+func (c *CPU) loadAF() int { return int(c.A)<<8 | int(c.F) }
+func (c *CPU) loadBC() int { return int(c.B)<<8 | int(c.C) }
+func (c *CPU) loadDE() int { return int(c.D)<<8 | int(c.E) }
+func (c *CPU) loadHL() int { return int(c.H)<<8 | int(c.L) }
+func (c *CPU) loadSP() int { return int(c.SP) }
+func (c *CPU) loadIX() int { return int(c.IXH)<<8 | int(c.IXL) }
+func (c *CPU) loadIY() int { return int(c.IYH)<<8 | int(c.IYL) }
+
+func (c *CPU) load16IndSP() int {
+	// Only used by ex (sp),hl which leaves the loaded value in MEMPTR
+	v := c.mem.ReadLE(int(c.SP))
+	c.WZ = uint16(v)
+	return v
+}
 
 func (c *CPU) loadAF1() int { return int(c.A1)<<8 | int(c.F1) }
 func (c *CPU) loadBC1() int { return int(c.B1)<<8 | int(c.C1) }
@@ -96,30 +127,40 @@ func (c *CPU) loadHL1() int { return int(c.H1)<<8 | int(c.L1) }
 
 func (c *CPU) loadIndHL() uint8 { return c.mem.Read(int(c.H)<<8 | int(c.L)) }
 
-func (c *CPU) loadIndBC() uint8 { return c.mem.Read(int(c.B)<<8 | int(c.C)) }
-func (c *CPU) loadIndDE() uint8 { return c.mem.Read(int(c.D)<<8 | int(c.E)) }
+func (c *CPU) loadIndBC() uint8 { return c.loadIndRP(c.B, c.C) }
+func (c *CPU) loadIndDE() uint8 { return c.loadIndRP(c.D, c.E) }
+
+func (c *CPU) loadIndRP(hi uint8, lo uint8) uint8 {
+	addr := int(hi)<<8 | int(lo)
+	c.WZ = uint16(addr + 1)
+	return c.mem.Read(addr)
+}
 
 func (c *CPU) loadIndIX() uint8 {
 	ix := int(c.IXH)<<8 | int(c.IXL)
 	c.iaddr = ix + int(int8(c.delta))
+	c.WZ = uint16(c.iaddr)
 	return c.mem.Read(c.iaddr)
 }
 
 func (c *CPU) loadIndIY() uint8 {
 	iy := int(c.IYH)<<8 | int(c.IYL)
 	c.iaddr = iy + int(int8(c.delta))
+	c.WZ = uint16(c.iaddr)
 	return c.mem.Read(c.iaddr)
 }
 
 func (c *CPU) storeIndIX(v uint8) {
 	ix := int(c.IXH)<<8 | int(c.IXL)
 	addr := ix + int(int8(c.delta))
+	c.WZ = uint16(addr)
 	c.mem.Write(addr, v)
 }
 
 func (c *CPU) storeIndIY(v uint8) {
 	iy := int(c.IYH)<<8 | int(c.IYL)
 	addr := iy + int(int8(c.delta))
+	c.WZ = uint16(addr)
 	c.mem.Write(addr, v)
 }
 
@@ -128,19 +169,30 @@ func (c *CPU) storeLastInd(v uint8) {
 }
 
 func (c *CPU) outIndImm(v uint8) {
-	addr := int(c.fetch())
-	c.Ports.Write(addr, v)
+	n := c.fetch()
+	c.Ports.Write(c.port(c.A, n), v)
+	c.WZ = uint16(c.A)<<8 | uint16(n+1)
 }
 
 func (c *CPU) inIndImm() uint8 {
-	addr := int(c.fetch())
-	return c.Ports.Read(addr)
+	n := c.fetch()
+	c.WZ = (uint16(c.A)<<8 | uint16(n)) + 1
+	return c.Ports.Read(c.port(c.A, n))
 }
 
 func (c *CPU) outIndC(v uint8) {
-	c.Ports.Write(int(c.C), v)
+	c.Ports.Write(c.port(c.B, c.C), v)
+	c.WZ = uint16(c.loadBC() + 1)
 }
 
 func (c *CPU) inIndC() uint8 {
-	return c.Ports.Read(int(c.C))
+	c.WZ = uint16(c.loadBC() + 1)
+	return c.Ports.Read(c.port(c.B, c.C))
+}
+
+// The full 16-bit address is placed on the bus for port I/O. Most systems
+// only decode the lower 8 bits so the address is folded into the size
+// of the port space.
+func (c *CPU) port(hi uint8, lo uint8) int {
+	return (int(hi)<<8 | int(lo)) & c.Ports.MaxAddr
 }
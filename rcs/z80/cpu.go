@@ -7,9 +7,10 @@ import (
 	"github.com/blackchip-org/retro-cs/rcs"
 )
 
-// CPU is the Zilog Z80 processor.
+// CPU is the Zilog Z80 processor. It can also run as an Intel 8080.
 type CPU struct {
-	Name string
+	Name    string
+	Variant Variant
 
 	pc uint16 // Program counter
 	A  uint8  // Accumulator
@@ -37,29 +38,44 @@ type CPU struct {
 	IYH uint8
 	IYL uint8
 	SP  uint16 // Stack pointer
+	WZ  uint16 // Internal register, also known as MEMPTR
 
 	IFF1 bool // Interrupt flip flops
 	IFF2 bool
 	IM   uint8 // Interrupt mode
 	Halt bool  // Halted by instruction
 
-	Ports   *rcs.Memory
+	Ports *rcs.Memory // I/O address space, mirrored if less than 64K
+
+	// IRQ is level-sensitive and stays set until the interrupt is
+	// accepted by the CPU. Devices on the daisy chain also request
+	// interrupts through Daisy.
 	IRQ     bool
-	IRQData uint8
+	IRQData uint8         // value on the data bus when an interrupt is accepted
+	IRQBus  func() uint8  // if set, supplies each byte on the data bus instead
+	Daisy   []DaisyDevice // interrupt daisy chain, highest priority first
 	NMI     bool
 	RESET   bool
 
 	WatchIRQ bool
 
-	opcodes     map[uint8]func(*CPU)
-	opcodesCB   map[uint8]func(*CPU)
-	opcodesED   map[uint8]func(*CPU)
-	opcodesDD   map[uint8]func(*CPU)
-	opcodesFD   map[uint8]func(*CPU)
-	opcodesDDCB map[uint8]func(*CPU)
-	opcodesFDCB map[uint8]func(*CPU)
+	// Clock cycles used by executed instructions. Only counted for the
+	// 8080.
+	Cycles uint64
+
+	opcodes     *[256]func(*CPU)
+	opcodesCB   *[256]func(*CPU)
+	opcodesED   *[256]func(*CPU)
+	opcodesDD   *[256]func(*CPU)
+	opcodesFD   *[256]func(*CPU)
+	opcodesDDCB *[256]func(*CPU)
+	opcodesFDCB *[256]func(*CPU)
 
 	mem   *rcs.Memory
+	bus   func() uint8 // data bus while in an interrupt acknowledge cycle
+	ei    bool         // interrupts are not accepted until after the next instruction
+	q     uint8        // flags set by the last instruction, zero if unchanged
+	fset  bool         // flags were set by the current instruction
 	delta uint8
 	// address used to load on the last (IX+d) or (IY+d) instruction
 	iaddr int
@@ -94,35 +110,48 @@ const (
 	FlagS = uint8(1 << 7)
 )
 
+// New creates a new Z80 with a view of the given memory.
 func New(mem *rcs.Memory) *CPU {
+	return NewVariant(mem, Z80)
+}
+
+// NewVariant creates a new CPU of the given variant with a view of the
+// given memory. The 8080 does not use the prefixed instruction tables.
+func NewVariant(mem *rcs.Memory, v Variant) *CPU {
 	c := &CPU{
+		Variant:     v,
 		mem:         mem,
 		Ports:       rcs.NewMemory(1, 0x100),
-		opcodes:     opcodes,
-		opcodesCB:   opcodesCB,
-		opcodesED:   opcodesED,
-		opcodesDD:   opcodesDD,
-		opcodesFD:   opcodesFD,
-		opcodesDDCB: opcodesDDCB,
-		opcodesFDCB: opcodesFDCB,
+		opcodes:     &opcodes,
+		opcodesCB:   &opcodesCB,
+		opcodesED:   &opcodesED,
+		opcodesDD:   &opcodesDD,
+		opcodesFD:   &opcodesFD,
+		opcodesDDCB: &opcodesDDCB,
+		opcodesFDCB: &opcodesFDCB,
+	}
+	if v == I8080 {
+		c.opcodes = &opcodes8080
+		c.F = FlagN
 	}
 	c.Ports.MapRAM(0, make([]uint8, 0x100, 0x100))
 	return c
 }
 
 func (c *CPU) Next() {
+	c.ei = false
 	if !c.Halt {
 		c.execute()
 	}
-	if c.IRQ {
-		c.IRQ = false
-		if c.IFF1 {
-			c.irqAck()
-		}
-	}
 	if c.NMI {
 		c.NMI = false
 		c.nmiAck()
+	} else if c.IFF1 && !c.ei {
+		if c.IRQ {
+			c.irqAck(nil)
+		} else if dev := c.daisyRequest(); dev != nil {
+			c.irqAck(dev)
+		}
 	}
 	if c.RESET {
 		c.RESET = false
@@ -131,12 +160,16 @@ func (c *CPU) Next() {
 }
 
 func (c *CPU) execute() {
+	if c.Variant == I8080 {
+		c.execute8080()
+		return
+	}
 	here := c.PC()
 	opcode := c.fetch()
 	c.refreshR()
 
 	prefix := ""
-	var table map[uint8]func(*CPU)
+	var table *[256]func(*CPU)
 	switch opcode {
 	case 0xcb:
 		table = c.opcodesCB
@@ -174,44 +207,96 @@ func (c *CPU) execute() {
 		table = c.opcodes
 	}
 
-	opFunc, ok := table[opcode]
-	if !ok {
+	opFunc := table[opcode]
+	if opFunc == nil {
 		log.Printf("%04x: illegal instruction: %v%02x", here, prefix, opcode)
 		return
 	}
+	c.fset = false
 	opFunc(c)
+	if c.fset {
+		c.q = c.F
+	} else {
+		c.q = 0
+	}
 }
 
-func (c *CPU) irqAck() {
-	if c.IM == 0 {
-		log.Printf("(!) %v: unsupported interrupt mode 0", c.Name)
-		return
+// irqAck accepts an interrupt from the IRQ line, or from the given device
+// on the daisy chain if not nil.
+func (c *CPU) irqAck(dev DaisyDevice) {
+	var bus func() uint8
+	switch {
+	case dev != nil:
+		bus = dataBus(dev.IRQAck())
+	case c.IRQBus != nil:
+		c.IRQ = false
+		bus = c.IRQBus
+	default:
+		c.IRQ = false
+		bus = dataBus(c.IRQData)
 	}
+
 	retAddr := c.PC()
 	c.Halt = false
 	c.IFF1 = false
 	c.IFF2 = false
-	c.SP -= 2
-	c.mem.WriteLE(int(c.SP), retAddr)
-	if c.IM == 2 {
-		vector := int(c.I)<<8 | int(c.IRQData)
+	switch c.IM {
+	case 0:
+		// Execute the instruction found on the data bus. The program
+		// counter is not advanced while the instruction is fetched.
+		c.bus = bus
+		if c.WatchIRQ {
+			log.Printf("%v: irq(0), return %v", c.Name, rcs.X(retAddr))
+		}
+		c.execute()
+		c.bus = nil
+	case 2:
+		data := bus()
+		vector := int(c.I)<<8 | int(data)
 		if c.WatchIRQ {
 			log.Printf("%v: irq(2:%v), vector %v, return %v", c.Name,
-				rcs.X8(c.IRQData), rcs.X(vector), rcs.X(retAddr))
+				rcs.X8(data), rcs.X(vector), rcs.X(retAddr))
 		}
+		c.SP -= 2
+		c.mem.WriteLE(int(c.SP), retAddr)
 		c.SetPC(c.mem.ReadLE(vector))
-	} else {
+		c.WZ = c.pc
+	default:
 		if c.WatchIRQ {
 			log.Printf("%v: irq(1), return %v", c.Name, rcs.X(retAddr))
 		}
+		c.SP -= 2
+		c.mem.WriteLE(int(c.SP), retAddr)
 		c.pc = 0x0038
+		c.WZ = c.pc
+	}
+}
+
+// dataBus returns a function that provides the value placed on the data
+// bus. The bus floats high on any read after that.
+func dataBus(v uint8) func() uint8 {
+	done := false
+	return func() uint8 {
+		if done {
+			return 0xff
+		}
+		done = true
+		return v
 	}
 }
 
 func (c *CPU) nmiAck() {
+	c.Halt = false
+	c.IFF1 = false
 	c.SP -= 2
 	c.mem.WriteLE(int(c.SP), c.PC())
 	c.pc = 0x0066
+	c.WZ = c.pc
+}
+
+// Reset puts the CPU in the state found after the reset line is released.
+func (c *CPU) Reset() {
+	c.resetAck()
 }
 
 func (c *CPU) resetAck() {
@@ -221,6 +306,7 @@ func (c *CPU) resetAck() {
 	c.I = 0
 	c.R = 0
 	c.IM = 0
+	c.Halt = false
 }
 
 // PC returns the value of the program counter.
@@ -245,14 +331,17 @@ func (c *CPU) Memory() *rcs.Memory {
 	return c.mem
 }
 
-// NewDisassembler creates a disassembler that can handle Z80 machine
-// code.
+// NewDisassembler creates a disassembler that can handle the machine code
+// of the variant.
 func (c *CPU) NewDisassembler() *rcs.Disassembler {
-	dasm := rcs.NewDisassembler(c.mem, Reader, Formatter())
+	dasm := rcs.NewDisassembler(c.mem, ReaderFor(c.Variant), Formatter())
 	return dasm
 }
 
 func (c *CPU) fetch() uint8 {
+	if c.bus != nil {
+		return c.bus()
+	}
 	c.pc++
 	return c.mem.Read(int(c.pc - 1))
 }
@@ -349,11 +438,15 @@ func (c *CPU) Save(enc *rcs.Encoder) {
 	enc.Encode(c.IYL)
 	enc.Encode(c.SP)
 	enc.Encode(c.pc)
+	enc.Encode(c.WZ)
+	enc.Encode(c.q)
 
 	enc.Encode(c.IFF1)
 	enc.Encode(c.IFF2)
 	enc.Encode(c.IM)
 	enc.Encode(c.Halt)
+	enc.Encode(c.IRQ)
+	enc.Encode(c.IRQData)
 }
 
 func (c *CPU) Load(dec *rcs.Decoder) {
@@ -381,11 +474,15 @@ func (c *CPU) Load(dec *rcs.Decoder) {
 	dec.Decode(&c.IYL)
 	dec.Decode(&c.SP)
 	dec.Decode(&c.pc)
+	dec.Decode(&c.WZ)
+	dec.Decode(&c.q)
 
 	dec.Decode(&c.IFF1)
 	dec.Decode(&c.IFF2)
 	dec.Decode(&c.IM)
 	dec.Decode(&c.Halt)
+	dec.Decode(&c.IRQ)
+	dec.Decode(&c.IRQData)
 }
 
 func (c *CPU) prefix() string {
@@ -2,6 +2,9 @@ package z80
 
 import (
 	"testing"
+
+	"github.com/blackchip-org/retro-cs/mock"
+	"github.com/blackchip-org/retro-cs/rcs"
 )
 
 func TestString(t *testing.T) {
@@ -43,3 +46,135 @@ func TestString(t *testing.T) {
 		t.Errorf("\n have: \n%v \n want: \n%v", have, want)
 	}
 }
+
+func TestPortAddress16(t *testing.T) {
+	mock.ResetMemory()
+	cpu := New(mock.TestMemory)
+	cpu.Ports = rcs.NewMemory(1, 0x10000)
+	ports := make([]uint8, 0x10000, 0x10000)
+	cpu.Ports.MapRAM(0, ports)
+	cpu.A = 0x42
+	cpu.B = 0xd5
+	cpu.C = 0x05
+	mock.TestMemory.WriteN(0, 0xed, 0x79) // out (c),a
+	cpu.Next()
+	if ports[0xd505] != 0x42 {
+		t.Errorf("\n have: %02x \n want: %02x", ports[0xd505], 0x42)
+	}
+}
+
+func TestPortAddressMirror(t *testing.T) {
+	mock.ResetMemory()
+	cpu := New(mock.TestMemory)
+	cpu.A = 0x42
+	cpu.B = 0xd5
+	cpu.C = 0x05
+	mock.TestMemory.WriteN(0, 0xed, 0x79) // out (c),a
+	cpu.Next()
+	if v := cpu.Ports.Read(0x05); v != 0x42 {
+		t.Errorf("\n have: %02x \n want: %02x", v, 0x42)
+	}
+}
+
+func newInterruptCPU(im uint8) *CPU {
+	mock.ResetMemory()
+	cpu := New(mock.TestMemory)
+	cpu.SP = 0x8000
+	cpu.IFF1 = true
+	cpu.IFF2 = true
+	cpu.IM = im
+	return cpu
+}
+
+func TestIRQMode0(t *testing.T) {
+	cpu := newInterruptCPU(0)
+	cpu.IRQ = true
+	cpu.IRQData = 0xd7 // rst $10
+	cpu.Next()         // nop
+	if cpu.PC() != 0x10 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.PC(), 0x10)
+	}
+	if ret := cpu.mem.ReadLE(int(cpu.SP)); ret != 0x0001 {
+		t.Errorf("\n have: %04x \n want: %04x", ret, 0x0001)
+	}
+	if cpu.IRQ || cpu.IFF1 {
+		t.Errorf("expected interrupt to be accepted")
+	}
+}
+
+func TestIRQMode0Call(t *testing.T) {
+	cpu := newInterruptCPU(0)
+	bus := []uint8{0xcd, 0x34, 0x12} // call $1234
+	cpu.IRQ = true
+	cpu.IRQBus = func() uint8 {
+		v := bus[0]
+		bus = bus[1:]
+		return v
+	}
+	cpu.Next() // nop
+	if cpu.PC() != 0x1234 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.PC(), 0x1234)
+	}
+	if ret := cpu.mem.ReadLE(int(cpu.SP)); ret != 0x0001 {
+		t.Errorf("\n have: %04x \n want: %04x", ret, 0x0001)
+	}
+}
+
+func TestIRQMode2(t *testing.T) {
+	cpu := newInterruptCPU(2)
+	cpu.I = 0x12
+	cpu.mem.WriteLE(0x1234, 0x5678)
+	cpu.IRQ = true
+	cpu.IRQData = 0x34
+	cpu.Next() // nop
+	if cpu.PC() != 0x5678 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.PC(), 0x5678)
+	}
+}
+
+func TestIRQHeld(t *testing.T) {
+	cpu := newInterruptCPU(1)
+	cpu.IFF1 = false
+	cpu.IFF2 = false
+	cpu.mem.WriteN(0x0000, 0xfb, 0x00) // ei, nop
+	cpu.IRQ = true
+	cpu.Next() // ei
+	if !cpu.IRQ {
+		t.Fatalf("expected request to be held while interrupts are disabled")
+	}
+	cpu.Next() // nop
+	if cpu.PC() != 0x38 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.PC(), 0x38)
+	}
+}
+
+func TestNMIBeforeIRQ(t *testing.T) {
+	cpu := newInterruptCPU(1)
+	cpu.IRQ = true
+	cpu.NMI = true
+	cpu.Next() // nop
+	if cpu.PC() != 0x66 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.PC(), 0x66)
+	}
+	if !cpu.IRQ || cpu.IFF1 || !cpu.IFF2 {
+		t.Errorf("expected irq to be held and iff1 to be cleared")
+	}
+}
+
+func BenchmarkNext(b *testing.B) {
+	mock.ResetMemory()
+	c := New(mock.TestMemory)
+	c.mem.WriteN(0x0000,
+		0x3e, 0x00, // ld a,$00
+		0xc6, 0x01, // add a,$01
+		0x32, 0x00, 0x40, // ld ($4000),a
+		0xcb, 0x47, // bit 0,a
+		0xdd, 0x23, // inc ix
+		0x10, 0xf5, // djnz $0002
+		0xc3, 0x00, 0x00, // jp $0000
+	)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c.Next()
+	}
+}
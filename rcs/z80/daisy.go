@@ -0,0 +1,59 @@
+package z80
+
+// DaisyState is the state of a device on the interrupt daisy chain.
+type DaisyState uint8
+
+const (
+	// DaisyINT is set when the device is requesting an interrupt.
+	DaisyINT DaisyState = 1 << 0
+
+	// DaisyIEO is set when the interrupt for the device is being serviced.
+	// Devices further down the chain are blocked until a reti instruction
+	// is executed.
+	DaisyIEO DaisyState = 1 << 1
+)
+
+// DaisyDevice is a peripheral, such as a CTC, PIO, or SIO, on the interrupt
+// daisy chain. Devices are listed in CPU.Daisy in order of priority with
+// the highest priority first.
+type DaisyDevice interface {
+	// IRQState returns the current state of the device.
+	IRQState() DaisyState
+
+	// IRQAck is called when the CPU accepts the interrupt from the device.
+	// The returned value is placed on the data bus. The device should
+	// stop requesting an interrupt and start blocking lower priority
+	// devices.
+	IRQAck() uint8
+
+	// IRQReti is called on the device being serviced when the CPU executes
+	// a reti instruction.
+	IRQReti()
+}
+
+// daisyRequest returns the device that has the highest priority interrupt
+// request. Nil is returned if there is no request or if requests are
+// blocked by an interrupt being serviced.
+func (c *CPU) daisyRequest() DaisyDevice {
+	for _, dev := range c.Daisy {
+		state := dev.IRQState()
+		if state&DaisyINT != 0 {
+			return dev
+		}
+		if state&DaisyIEO != 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// daisyReti signals the device being serviced that the interrupt routine
+// has finished.
+func (c *CPU) daisyReti() {
+	for _, dev := range c.Daisy {
+		if dev.IRQState()&DaisyIEO != 0 {
+			dev.IRQReti()
+			return
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package z80
+
+import "testing"
+
+type testDaisyDevice struct {
+	vector  uint8
+	request bool
+	service bool
+	retis   int
+}
+
+func (d *testDaisyDevice) IRQState() DaisyState {
+	var state DaisyState
+	if d.request {
+		state |= DaisyINT
+	}
+	if d.service {
+		state |= DaisyIEO
+	}
+	return state
+}
+
+func (d *testDaisyDevice) IRQAck() uint8 {
+	d.request = false
+	d.service = true
+	return d.vector
+}
+
+func (d *testDaisyDevice) IRQReti() {
+	d.service = false
+	d.retis++
+}
+
+func TestDaisyChain(t *testing.T) {
+	cpu := newInterruptCPU(2)
+	hi := &testDaisyDevice{vector: 0x10}
+	lo := &testDaisyDevice{vector: 0x20}
+	cpu.Daisy = []DaisyDevice{hi, lo}
+	cpu.mem.WriteLE(0x0010, 0x1000)
+	cpu.mem.WriteLE(0x0020, 0x2000)
+	cpu.mem.WriteN(0x1000, 0xfb, 0x00, 0xed, 0x4d) // ei, nop, reti
+	cpu.mem.WriteN(0x2000, 0xfb, 0x00, 0xed, 0x4d) // ei, nop, reti
+
+	lo.request = true
+	cpu.Next() // nop
+	if cpu.PC() != 0x2000 {
+		t.Fatalf("\n have: %04x \n want: %04x", cpu.PC(), 0x2000)
+	}
+
+	// higher priority device interrupts the service routine
+	hi.request = true
+	cpu.Next() // ei
+	cpu.Next() // nop
+	if cpu.PC() != 0x1000 {
+		t.Fatalf("\n have: %04x \n want: %04x", cpu.PC(), 0x1000)
+	}
+
+	// reti goes to the highest priority device being serviced
+	cpu.Next() // ei
+	cpu.Next() // nop
+	cpu.Next() // reti
+	if hi.retis != 1 || lo.retis != 0 {
+		t.Fatalf("unexpected reti: hi %v, lo %v", hi.retis, lo.retis)
+	}
+	if cpu.PC() != 0x2002 {
+		t.Fatalf("\n have: %04x \n want: %04x", cpu.PC(), 0x2002)
+	}
+	cpu.Next() // reti
+	if lo.retis != 1 {
+		t.Errorf("expected reti for lo")
+	}
+}
+
+func TestDaisyChainBlocked(t *testing.T) {
+	cpu := newInterruptCPU(2)
+	hi := &testDaisyDevice{service: true}
+	lo := &testDaisyDevice{request: true}
+	cpu.Daisy = []DaisyDevice{hi, lo}
+	cpu.Next() // nop
+	if cpu.PC() != 0x0001 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.PC(), 0x0001)
+	}
+}
@@ -0,0 +1,354 @@
+package z80
+
+import (
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+// Variant selects the instruction set and behavior of the processor.
+type Variant int
+
+const (
+	Z80   Variant = iota // Zilog Z80
+	I8080                // Intel 8080
+)
+
+func (v Variant) String() string {
+	switch v {
+	case Z80:
+		return "z80"
+	case I8080:
+		return "8080"
+	}
+	return "?"
+}
+
+// Instruction table for the 8080. It starts with the unprefixed
+// instructions of the Z80 and replaces those that are different. The
+// prefixes and the Z80 only instructions found in the unprefixed table
+// are undocumented aliases on the 8080.
+var opcodes8080 [256]func(c *CPU)
+
+func init() {
+	opcodes8080 = opcodes
+	for k, v := range opcodes8080Diff {
+		if v != nil {
+			opcodes8080[k] = v
+		}
+	}
+}
+
+var opcodes8080Diff = [256]func(c *CPU){
+	0x04: func(c *CPU) { inr8080(c, c.storeB, c.loadB) },
+	0x05: func(c *CPU) { dcr8080(c, c.storeB, c.loadB) },
+	0x07: func(c *CPU) { keepAC(c, rlca) },
+	0x08: func(c *CPU) { nop() },
+	0x09: func(c *CPU) { dad8080(c, c.loadBC) },
+	0x0c: func(c *CPU) { inr8080(c, c.storeC, c.loadC) },
+	0x0d: func(c *CPU) { dcr8080(c, c.storeC, c.loadC) },
+	0x0f: func(c *CPU) { keepAC(c, rrca) },
+	0x10: func(c *CPU) { nop() },
+	0x14: func(c *CPU) { inr8080(c, c.storeD, c.loadD) },
+	0x15: func(c *CPU) { dcr8080(c, c.storeD, c.loadD) },
+	0x17: func(c *CPU) { keepAC(c, rla) },
+	0x18: func(c *CPU) { nop() },
+	0x19: func(c *CPU) { dad8080(c, c.loadDE) },
+	0x1c: func(c *CPU) { inr8080(c, c.storeE, c.loadE) },
+	0x1d: func(c *CPU) { dcr8080(c, c.storeE, c.loadE) },
+	0x1f: func(c *CPU) { keepAC(c, rra) },
+	0x20: func(c *CPU) { nop() },
+	0x24: func(c *CPU) { inr8080(c, c.storeH, c.loadH) },
+	0x25: func(c *CPU) { dcr8080(c, c.storeH, c.loadH) },
+	0x27: func(c *CPU) { daa8080(c) },
+	0x28: func(c *CPU) { nop() },
+	0x29: func(c *CPU) { dad8080(c, c.loadHL) },
+	0x2c: func(c *CPU) { inr8080(c, c.storeL, c.loadL) },
+	0x2d: func(c *CPU) { dcr8080(c, c.storeL, c.loadL) },
+	0x2f: func(c *CPU) { keepAC(c, cpl) },
+	0x30: func(c *CPU) { nop() },
+	0x34: func(c *CPU) { inr8080(c, c.storeIndHL, c.loadIndHL) },
+	0x35: func(c *CPU) { dcr8080(c, c.storeIndHL, c.loadIndHL) },
+	0x37: func(c *CPU) { keepAC(c, scf) },
+	0x38: func(c *CPU) { nop() },
+	0x39: func(c *CPU) { dad8080(c, c.loadSP) },
+	0x3c: func(c *CPU) { inr8080(c, c.storeA, c.loadA) },
+	0x3d: func(c *CPU) { dcr8080(c, c.storeA, c.loadA) },
+	0x3f: func(c *CPU) { keepAC(c, ccf) },
+	0x80: func(c *CPU) { add8080(c, c.loadB, false) },
+	0x81: func(c *CPU) { add8080(c, c.loadC, false) },
+	0x82: func(c *CPU) { add8080(c, c.loadD, false) },
+	0x83: func(c *CPU) { add8080(c, c.loadE, false) },
+	0x84: func(c *CPU) { add8080(c, c.loadH, false) },
+	0x85: func(c *CPU) { add8080(c, c.loadL, false) },
+	0x86: func(c *CPU) { add8080(c, c.loadIndHL, false) },
+	0x87: func(c *CPU) { add8080(c, c.loadA, false) },
+	0x88: func(c *CPU) { add8080(c, c.loadB, true) },
+	0x89: func(c *CPU) { add8080(c, c.loadC, true) },
+	0x8a: func(c *CPU) { add8080(c, c.loadD, true) },
+	0x8b: func(c *CPU) { add8080(c, c.loadE, true) },
+	0x8c: func(c *CPU) { add8080(c, c.loadH, true) },
+	0x8d: func(c *CPU) { add8080(c, c.loadL, true) },
+	0x8e: func(c *CPU) { add8080(c, c.loadIndHL, true) },
+	0x8f: func(c *CPU) { add8080(c, c.loadA, true) },
+	0x90: func(c *CPU) { c.A = sub8080(c, c.loadB, false) },
+	0x91: func(c *CPU) { c.A = sub8080(c, c.loadC, false) },
+	0x92: func(c *CPU) { c.A = sub8080(c, c.loadD, false) },
+	0x93: func(c *CPU) { c.A = sub8080(c, c.loadE, false) },
+	0x94: func(c *CPU) { c.A = sub8080(c, c.loadH, false) },
+	0x95: func(c *CPU) { c.A = sub8080(c, c.loadL, false) },
+	0x96: func(c *CPU) { c.A = sub8080(c, c.loadIndHL, false) },
+	0x97: func(c *CPU) { c.A = sub8080(c, c.loadA, false) },
+	0x98: func(c *CPU) { c.A = sub8080(c, c.loadB, true) },
+	0x99: func(c *CPU) { c.A = sub8080(c, c.loadC, true) },
+	0x9a: func(c *CPU) { c.A = sub8080(c, c.loadD, true) },
+	0x9b: func(c *CPU) { c.A = sub8080(c, c.loadE, true) },
+	0x9c: func(c *CPU) { c.A = sub8080(c, c.loadH, true) },
+	0x9d: func(c *CPU) { c.A = sub8080(c, c.loadL, true) },
+	0x9e: func(c *CPU) { c.A = sub8080(c, c.loadIndHL, true) },
+	0x9f: func(c *CPU) { c.A = sub8080(c, c.loadA, true) },
+	0xa0: func(c *CPU) { ana8080(c, c.loadB) },
+	0xa1: func(c *CPU) { ana8080(c, c.loadC) },
+	0xa2: func(c *CPU) { ana8080(c, c.loadD) },
+	0xa3: func(c *CPU) { ana8080(c, c.loadE) },
+	0xa4: func(c *CPU) { ana8080(c, c.loadH) },
+	0xa5: func(c *CPU) { ana8080(c, c.loadL) },
+	0xa6: func(c *CPU) { ana8080(c, c.loadIndHL) },
+	0xa7: func(c *CPU) { ana8080(c, c.loadA) },
+	0xa8: func(c *CPU) { xra8080(c, c.loadB) },
+	0xa9: func(c *CPU) { xra8080(c, c.loadC) },
+	0xaa: func(c *CPU) { xra8080(c, c.loadD) },
+	0xab: func(c *CPU) { xra8080(c, c.loadE) },
+	0xac: func(c *CPU) { xra8080(c, c.loadH) },
+	0xad: func(c *CPU) { xra8080(c, c.loadL) },
+	0xae: func(c *CPU) { xra8080(c, c.loadIndHL) },
+	0xaf: func(c *CPU) { xra8080(c, c.loadA) },
+	0xb0: func(c *CPU) { ora8080(c, c.loadB) },
+	0xb1: func(c *CPU) { ora8080(c, c.loadC) },
+	0xb2: func(c *CPU) { ora8080(c, c.loadD) },
+	0xb3: func(c *CPU) { ora8080(c, c.loadE) },
+	0xb4: func(c *CPU) { ora8080(c, c.loadH) },
+	0xb5: func(c *CPU) { ora8080(c, c.loadL) },
+	0xb6: func(c *CPU) { ora8080(c, c.loadIndHL) },
+	0xb7: func(c *CPU) { ora8080(c, c.loadA) },
+	0xb8: func(c *CPU) { sub8080(c, c.loadB, false) },
+	0xb9: func(c *CPU) { sub8080(c, c.loadC, false) },
+	0xba: func(c *CPU) { sub8080(c, c.loadD, false) },
+	0xbb: func(c *CPU) { sub8080(c, c.loadE, false) },
+	0xbc: func(c *CPU) { sub8080(c, c.loadH, false) },
+	0xbd: func(c *CPU) { sub8080(c, c.loadL, false) },
+	0xbe: func(c *CPU) { sub8080(c, c.loadIndHL, false) },
+	0xbf: func(c *CPU) { sub8080(c, c.loadA, false) },
+	0xc0: func(c *CPU) { ret8080(c, FlagZ, false) },
+	0xc4: func(c *CPU) { call8080(c, FlagZ, false) },
+	0xc6: func(c *CPU) { add8080(c, c.loadImm, false) },
+	0xc8: func(c *CPU) { ret8080(c, FlagZ, true) },
+	0xcb: func(c *CPU) { jpa(c, c.loadImm16) },
+	0xcc: func(c *CPU) { call8080(c, FlagZ, true) },
+	0xce: func(c *CPU) { add8080(c, c.loadImm, true) },
+	0xd0: func(c *CPU) { ret8080(c, FlagC, false) },
+	0xd4: func(c *CPU) { call8080(c, FlagC, false) },
+	0xd6: func(c *CPU) { c.A = sub8080(c, c.loadImm, false) },
+	0xd8: func(c *CPU) { ret8080(c, FlagC, true) },
+	0xd9: func(c *CPU) { reta(c) },
+	0xdc: func(c *CPU) { call8080(c, FlagC, true) },
+	0xdd: func(c *CPU) { calla(c, c.loadImm16) },
+	0xde: func(c *CPU) { c.A = sub8080(c, c.loadImm, true) },
+	0xe0: func(c *CPU) { ret8080(c, FlagP, false) },
+	0xe4: func(c *CPU) { call8080(c, FlagP, false) },
+	0xe6: func(c *CPU) { ana8080(c, c.loadImm) },
+	0xe8: func(c *CPU) { ret8080(c, FlagP, true) },
+	0xec: func(c *CPU) { call8080(c, FlagP, true) },
+	0xed: func(c *CPU) { calla(c, c.loadImm16) },
+	0xee: func(c *CPU) { xra8080(c, c.loadImm) },
+	0xf0: func(c *CPU) { ret8080(c, FlagS, false) },
+	0xf4: func(c *CPU) { call8080(c, FlagS, false) },
+	0xf6: func(c *CPU) { ora8080(c, c.loadImm) },
+	0xf8: func(c *CPU) { ret8080(c, FlagS, true) },
+	0xfc: func(c *CPU) { call8080(c, FlagS, true) },
+	0xfd: func(c *CPU) { calla(c, c.loadImm16) },
+	0xfe: func(c *CPU) { sub8080(c, c.loadImm, false) },
+}
+
+// Number of clock cycles for each instruction. Conditional calls and
+// returns take an additional 6 cycles when taken.
+var cycles8080 = [256]uint8{
+	4, 10, 7, 5, 5, 5, 7, 4, 4, 10, 7, 5, 5, 5, 7, 4, // 0x
+	4, 10, 7, 5, 5, 5, 7, 4, 4, 10, 7, 5, 5, 5, 7, 4, // 1x
+	4, 10, 16, 5, 5, 5, 7, 4, 4, 10, 16, 5, 5, 5, 7, 4, // 2x
+	4, 10, 13, 5, 10, 10, 10, 4, 4, 10, 13, 5, 5, 5, 7, 4, // 3x
+	5, 5, 5, 5, 5, 5, 7, 5, 5, 5, 5, 5, 5, 5, 7, 5, // 4x
+	5, 5, 5, 5, 5, 5, 7, 5, 5, 5, 5, 5, 5, 5, 7, 5, // 5x
+	5, 5, 5, 5, 5, 5, 7, 5, 5, 5, 5, 5, 5, 5, 7, 5, // 6x
+	7, 7, 7, 7, 7, 7, 7, 7, 5, 5, 5, 5, 5, 5, 7, 5, // 7x
+	4, 4, 4, 4, 4, 4, 7, 4, 4, 4, 4, 4, 4, 4, 7, 4, // 8x
+	4, 4, 4, 4, 4, 4, 7, 4, 4, 4, 4, 4, 4, 4, 7, 4, // 9x
+	4, 4, 4, 4, 4, 4, 7, 4, 4, 4, 4, 4, 4, 4, 7, 4, // ax
+	4, 4, 4, 4, 4, 4, 7, 4, 4, 4, 4, 4, 4, 4, 7, 4, // bx
+	5, 10, 10, 10, 11, 11, 7, 11, 5, 10, 10, 10, 11, 17, 7, 11, // cx
+	5, 10, 10, 10, 11, 11, 7, 11, 5, 10, 10, 10, 11, 17, 7, 11, // dx
+	5, 10, 10, 18, 11, 11, 7, 11, 5, 5, 10, 4, 11, 17, 7, 11, // ex
+	5, 10, 10, 4, 11, 11, 7, 11, 5, 5, 10, 4, 11, 17, 7, 11, // fx
+}
+
+func (c *CPU) execute8080() {
+	// Every entry in the 8080 table is filled in from the Z80 table so
+	// there are no illegal instructions.
+	opcode := c.fetch()
+	c.opcodes[opcode](c)
+	c.Cycles += uint64(cycles8080[opcode])
+	// Bit 1 of the flags is always set and bits 3 and 5 are always clear
+	c.F = c.F&^(Flag5|Flag3) | FlagN
+}
+
+// Sets the sign, zero, and parity flags for the result
+func flagsSZP(cpu *CPU, out uint8) {
+	cpu.F &^= FlagS | FlagZ | FlagP
+	if out&(1<<7) != 0 {
+		cpu.F |= FlagS
+	}
+	if out == 0 {
+		cpu.F |= FlagZ
+	}
+	if rcs.Parity(out) {
+		cpu.F |= FlagP
+	}
+}
+
+// The 8080 has no N flag and the instructions that set or clear the half
+// carry on the Z80 leave the auxiliary carry unchanged.
+func keepAC(cpu *CPU, fn func(*CPU)) {
+	ac := cpu.F & FlagH
+	fn(cpu)
+	cpu.F = cpu.F&^FlagH | ac
+}
+
+// 16-bit addition to HL, only the carry flag is changed
+func dad8080(cpu *CPU, load rcs.Load) {
+	keepAC(cpu, func(cpu *CPU) {
+		add16(cpu, cpu.storeHL, cpu.loadHL, load)
+	})
+}
+
+// add, and add with carry
+func add8080(cpu *CPU, load rcs.Load8, withCarry bool) {
+	carry := withCarry && cpu.F&FlagC != 0
+	out, fc, fh, _ := rcs.Add(cpu.A, load(), carry)
+
+	cpu.F = 0
+	if fh {
+		cpu.F |= FlagH
+	}
+	if fc {
+		cpu.F |= FlagC
+	}
+	flagsSZP(cpu, out)
+	cpu.A = out
+}
+
+// subtract, and subtract with borrow. The result is returned instead of
+// stored so it can also be used for a compare.
+func sub8080(cpu *CPU, load rcs.Load8, withBorrow bool) uint8 {
+	borrow := withBorrow && cpu.F&FlagC != 0
+	out, fc, fh, _ := rcs.Sub(cpu.A, load(), borrow)
+
+	// The subtraction is done by adding the complement and the auxiliary
+	// carry is the carry out of bit 3 of that addition.
+	cpu.F = 0
+	if !fh {
+		cpu.F |= FlagH
+	}
+	if fc {
+		cpu.F |= FlagC
+	}
+	flagsSZP(cpu, out)
+	return out
+}
+
+// bitwise logical and. The auxiliary carry is the logical or of bit 3 of
+// the values.
+func ana8080(cpu *CPU, load rcs.Load8) {
+	in := load()
+	out := cpu.A & in
+
+	cpu.F = 0
+	if (cpu.A|in)&(1<<3) != 0 {
+		cpu.F |= FlagH
+	}
+	flagsSZP(cpu, out)
+	cpu.A = out
+}
+
+// bitwise logical or
+func ora8080(cpu *CPU, load rcs.Load8) {
+	out := cpu.A | load()
+	cpu.F = 0
+	flagsSZP(cpu, out)
+	cpu.A = out
+}
+
+// bitwise exclusive or
+func xra8080(cpu *CPU, load rcs.Load8) {
+	out := cpu.A ^ load()
+	cpu.F = 0
+	flagsSZP(cpu, out)
+	cpu.A = out
+}
+
+// increment, carry unchanged
+func inr8080(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	out := load() + 1
+	cpu.F &= FlagC
+	if out&0x0f == 0 {
+		cpu.F |= FlagH
+	}
+	flagsSZP(cpu, out)
+	store(out)
+}
+
+// decrement, carry unchanged
+func dcr8080(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	out := load() - 1
+	cpu.F &= FlagC
+	if out&0x0f != 0x0f {
+		cpu.F |= FlagH
+	}
+	flagsSZP(cpu, out)
+	store(out)
+}
+
+// Decimal adjust accumulator. There is no N flag on the 8080 and the
+// adjustment is always for an addition. The carry flag is only ever set,
+// never cleared.
+func daa8080(cpu *CPU) {
+	carry := cpu.F&FlagC != 0
+	lo := cpu.A & 0x0f
+	hi := cpu.A >> 4
+	adjust := uint8(0)
+	if cpu.F&FlagH != 0 || lo > 9 {
+		adjust += 0x06
+	}
+	if carry || hi > 9 || (hi >= 9 && lo > 9) {
+		adjust += 0x60
+		carry = true
+	}
+	add8080(cpu, func() uint8 { return adjust }, false)
+	cpu.F &^= FlagC
+	if carry {
+		cpu.F |= FlagC
+	}
+}
+
+// call, conditional
+func call8080(cpu *CPU, flag uint8, condition bool) {
+	if (cpu.F&flag != 0) == condition {
+		cpu.Cycles += 6
+	}
+	call(cpu, flag, condition, cpu.loadImm16)
+}
+
+// return, conditional
+func ret8080(cpu *CPU, flag uint8, condition bool) {
+	if (cpu.F&flag != 0) == condition {
+		cpu.Cycles += 6
+	}
+	ret(cpu, flag, condition)
+}
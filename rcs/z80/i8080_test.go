@@ -0,0 +1,204 @@
+package z80
+
+import (
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/mock"
+)
+
+func new8080(code ...uint8) *CPU {
+	mock.ResetMemory()
+	cpu := NewVariant(mock.TestMemory, I8080)
+	cpu.SP = 0x8000
+	cpu.mem.WriteN(0, code...)
+	return cpu
+}
+
+func TestFlags8080Fixed(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   int
+		want uint8
+	}{
+		{"set", 0xffff, FlagS | FlagZ | FlagH | FlagP | FlagN | FlagC},
+		{"clear", 0xff00, FlagN},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := new8080(
+				0xf1, // pop psw
+				0xf5, // push psw
+			)
+			cpu.mem.WriteLE(0x8000, test.in)
+			cpu.Next()
+			cpu.Next()
+			have := cpu.mem.Read(0x8000)
+			if have != test.want {
+				t.Errorf("\n have: %02x \n want: %02x", have, test.want)
+			}
+		})
+	}
+}
+
+func TestALU8080(t *testing.T) {
+	var tests = []struct {
+		name  string
+		code  []uint8
+		a     uint8
+		f     uint8
+		wantA uint8
+		wantF uint8
+	}{
+		{"adi parity", []uint8{0xc6, 0x01}, 0x7f, 0, 0x80, FlagS | FlagH | FlagN},
+		{"sui borrow", []uint8{0xd6, 0x01}, 0x10, 0, 0x0f, FlagP | FlagN},
+		{"sui no borrow", []uint8{0xd6, 0x01}, 0x12, 0, 0x11, FlagH | FlagP | FlagN},
+		{"ani ac", []uint8{0xe6, 0x01}, 0x08, 0, 0x00, FlagZ | FlagH | FlagP | FlagN},
+		{"ani no ac", []uint8{0xe6, 0x70}, 0xf0, 0, 0x70, FlagN},
+		{"ori", []uint8{0xf6, 0x02}, 0x01, FlagH | FlagC, 0x03, FlagP | FlagN},
+		{"inr ac", []uint8{0x3c}, 0x0f, FlagC, 0x10, FlagH | FlagN | FlagC},
+		{"dcr no ac", []uint8{0x3d}, 0x10, 0, 0x0f, FlagP | FlagN},
+		{"dcr ac", []uint8{0x3d}, 0x11, 0, 0x10, FlagH | FlagN},
+		{"daa", []uint8{0x27}, 0x9b, 0, 0x01, FlagH | FlagN | FlagC},
+		{"daa half", []uint8{0x27}, 0x0a, FlagN, 0x10, FlagH | FlagN},
+		{"rlc", []uint8{0x07}, 0x80, FlagH, 0x01, FlagH | FlagN | FlagC},
+		{"cma", []uint8{0x2f}, 0x0f, 0, 0xf0, FlagN},
+		{"stc", []uint8{0x37}, 0, FlagH, 0, FlagH | FlagN | FlagC},
+		{"cmc", []uint8{0x3f}, 0, FlagC, 0, FlagN},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := new8080(test.code...)
+			cpu.A = test.a
+			cpu.F = test.f
+			cpu.Next()
+			if cpu.A != test.wantA {
+				t.Errorf("\n have a: %02x \n want a: %02x", cpu.A, test.wantA)
+			}
+			if cpu.F != test.wantF {
+				t.Errorf("\n have f: %02x \n want f: %02x", cpu.F, test.wantF)
+			}
+		})
+	}
+}
+
+func TestDad8080(t *testing.T) {
+	cpu := new8080(0x09) // dad b
+	cpu.H, cpu.L = 0x0f, 0x00
+	cpu.B, cpu.C = 0x01, 0x00
+	cpu.Next()
+	if cpu.loadHL() != 0x1000 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.loadHL(), 0x1000)
+	}
+	if cpu.F != FlagN {
+		t.Errorf("\n have: %02x \n want: %02x", cpu.F, FlagN)
+	}
+}
+
+func TestAliases8080(t *testing.T) {
+	var tests = []struct {
+		name   string
+		code   []uint8
+		wantPC int
+		wantSP uint16
+	}{
+		{"08", []uint8{0x08}, 0x0001, 0x8000},
+		{"10", []uint8{0x10, 0x10}, 0x0001, 0x8000},
+		{"18", []uint8{0x18, 0x10}, 0x0001, 0x8000},
+		{"38", []uint8{0x38, 0x10}, 0x0001, 0x8000},
+		{"cb", []uint8{0xcb, 0x34, 0x12}, 0x1234, 0x8000},
+		{"d9", []uint8{0xd9}, 0x5678, 0x8002},
+		{"dd", []uint8{0xdd, 0x34, 0x12}, 0x1234, 0x7ffe},
+		{"ed", []uint8{0xed, 0x34, 0x12}, 0x1234, 0x7ffe},
+		{"fd", []uint8{0xfd, 0x34, 0x12}, 0x1234, 0x7ffe},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := new8080(test.code...)
+			cpu.mem.WriteLE(0x8000, 0x5678)
+			cpu.Next()
+			if cpu.PC() != test.wantPC {
+				t.Errorf("\n have pc: %04x \n want pc: %04x", cpu.PC(), test.wantPC)
+			}
+			if cpu.SP != test.wantSP {
+				t.Errorf("\n have sp: %04x \n want sp: %04x", cpu.SP, test.wantSP)
+			}
+		})
+	}
+}
+
+func TestCycles8080(t *testing.T) {
+	cpu := new8080(
+		0x00,       // nop: 4
+		0x3e, 0x01, // mvi a,$01: 7
+		0xb7,             // ora a: 4
+		0xc4, 0x10, 0x00, // cnz $0010: 17
+	)
+	cpu.mem.WriteN(0x10,
+		0xc8, // rz: 5
+		0xc9, // ret: 10
+	)
+	want := []uint64{4, 11, 15, 32, 37, 47}
+	for i, w := range want {
+		cpu.Next()
+		if cpu.Cycles != w {
+			t.Fatalf("%v: \n have: %v \n want: %v", i, cpu.Cycles, w)
+		}
+	}
+}
+
+func TestIRQ8080(t *testing.T) {
+	cpu := new8080(
+		0xfb, // ei
+		0x00, // nop
+	)
+	cpu.IRQ = true
+	cpu.IRQData = 0xcf // rst 1
+	cpu.Next()
+	if cpu.PC() != 0x0001 {
+		t.Fatalf("\n have: %04x \n want: %04x", cpu.PC(), 0x0001)
+	}
+	cpu.Next()
+	if cpu.PC() != 0x0008 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.PC(), 0x0008)
+	}
+	if cpu.mem.ReadLE(int(cpu.SP)) != 0x0002 {
+		t.Errorf("\n have: %04x \n want: %04x", cpu.mem.ReadLE(int(cpu.SP)), 0x0002)
+	}
+}
+
+func TestDasm8080(t *testing.T) {
+	var tests = []struct {
+		bytes []uint8
+		want  string
+	}{
+		{[]uint8{0x00}, "nop"},
+		{[]uint8{0x7e}, "mov  a,m"},
+		{[]uint8{0x70}, "mov  m,b"},
+		{[]uint8{0x21, 0x34, 0x12}, "lxi  h,$1234"},
+		{[]uint8{0x3e, 0x12}, "mvi  a,$12"},
+		{[]uint8{0x32, 0x34, 0x12}, "sta  $1234"},
+		{[]uint8{0xf5}, "push psw"},
+		{[]uint8{0xc2, 0x34, 0x12}, "jnz  $1234"},
+		{[]uint8{0xec, 0x34, 0x12}, "cpe  $1234"},
+		{[]uint8{0xfe, 0x01}, "cpi  $01"},
+		{[]uint8{0xbe}, "cmp  m"},
+		{[]uint8{0xd3, 0x10}, "out  $10"},
+		{[]uint8{0xeb}, "xchg"},
+		{[]uint8{0xff}, "rst  7"},
+		{[]uint8{0xed, 0x34, 0x12}, "call $1234"},
+		{[]uint8{0x76}, "hlt"},
+	}
+	for _, test := range tests {
+		t.Run(test.want, func(t *testing.T) {
+			cpu := new8080(test.bytes...)
+			dasm := cpu.NewDisassembler()
+			s := dasm.NextStmt()
+			if s.Op != test.want {
+				t.Errorf("\n have: %v \n want: %v", s.Op, test.want)
+			}
+			if len(s.Bytes) != len(test.bytes) {
+				t.Errorf("\n have: %v bytes \n want: %v bytes", len(s.Bytes), len(test.bytes))
+			}
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// +build ext
+
+package z80
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/mock"
+)
+
+// The 8080 version of the exerciser runs all of its tests in one program
+// and then exits to CP/M by jumping to address zero. This can take more
+// than 10 minutes.
+func TestI8080EXM(t *testing.T) {
+	file := filepath.Join(root, "ext", "i8080", "8080exm.com")
+	code, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("unable to read %v: %v", file, err)
+	}
+
+	mock.ResetMemory()
+	for i, b := range code {
+		mock.TestMemory.Write(0x100+i, b)
+	}
+	// The stack starts at the address of the BDOS which is found in the
+	// jump instruction at the system call entry point.
+	mock.TestMemory.WriteLE(0x0006, 0xf000)
+
+	c := NewVariant(mock.TestMemory, I8080)
+	c.SetPC(0x100)
+	z := &zexRunner{mem: mock.TestMemory, cpu: c}
+	for {
+		z.Next()
+		z.Syscall()
+		if c.PC() == 0 {
+			break
+		}
+	}
+	if !z.Passed() {
+		t.Fail()
+	}
+}
@@ -6,6 +6,7 @@ import (
 
 // add
 func add(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
+	cpu.fset = true
 	out, fc, fh, fv := rcs.Add(load0(), load1(), false)
 
 	cpu.F = 0
@@ -35,6 +36,7 @@ func add(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
 
 // add with carry
 func adc(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
+	cpu.fset = true
 	out, fc, fh, fv := rcs.Add(load0(), load1(), cpu.F&FlagC != 0)
 
 	cpu.F = 0
@@ -64,8 +66,10 @@ func adc(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
 
 // 16-bit addition, without carry
 func add16(cpu *CPU, store rcs.Store, load0 rcs.Load, load1 rcs.Load) {
+	cpu.fset = true
 	in0 := load0()
 	in1 := load1()
+	cpu.WZ = uint16(in0 + 1)
 
 	lo, fc, _, _ := rcs.Add(uint8(in0), uint8(in1), false)
 	hi, fc, fh, _ := rcs.Add(uint8(in0>>8), uint8(in1>>8), fc)
@@ -89,8 +93,10 @@ func add16(cpu *CPU, store rcs.Store, load0 rcs.Load, load1 rcs.Load) {
 
 // 16-bit addition, with carry
 func adc16(cpu *CPU, store rcs.Store, load0 rcs.Load, load1 rcs.Load) {
+	cpu.fset = true
 	in0 := load0()
 	in1 := load1()
+	cpu.WZ = uint16(in0 + 1)
 
 	lo, fc, _, _ := rcs.Add(uint8(in0), uint8(in1), cpu.F&FlagC != 0)
 	hi, fc, fh, fv := rcs.Add(uint8(in0>>8), uint8(in1>>8), fc)
@@ -123,6 +129,7 @@ func adc16(cpu *CPU, store rcs.Store, load0 rcs.Load, load1 rcs.Load) {
 
 // bitwise logical and
 func and(cpu *CPU, load rcs.Load8) {
+	cpu.fset = true
 	out := cpu.A & load()
 
 	cpu.F = 0
@@ -147,6 +154,7 @@ func and(cpu *CPU, load rcs.Load8) {
 
 // test bit
 func bit(cpu *CPU, n int, load rcs.Load8) {
+	cpu.fset = true
 	bit := uint(n)
 	out := load()
 	cpu.F &^= FlagS | FlagZ | FlagV | FlagN | Flag5 | Flag3
@@ -168,25 +176,69 @@ func bit(cpu *CPU, n int, load rcs.Load8) {
 	}
 }
 
-// bit n,(ix+d)
+// bit n,(hl) and bit n,(ix+d)
 func biti(cpu *CPU, n int, load rcs.Load8) {
 	bit(cpu, n, load)
 
 	// http://www.z80.info/zip/z80-documented.pdf
 	// "This is where things start to get strange"
+	// Bits 3 and 5 are copied from MEMPTR which is set to the
+	// effective address for (ix+d)
 	cpu.F &^= Flag5 | Flag3
-	iaddrh := cpu.iaddr >> 8
-	if iaddrh&(1<<5) != 0 {
+	wzh := cpu.WZ >> 8
+	if wzh&(1<<5) != 0 {
 		cpu.F |= Flag5
 	}
-	if iaddrh&(1<<3) != 0 {
+	if wzh&(1<<3) != 0 {
 		cpu.F |= Flag3
 	}
 }
 
+// Repeat a block instruction by moving the program counter back to the
+// start of the instruction. Interrupts can be accepted between each
+// repetition. When interrupted, bits 3 and 5 of the flags are from the
+// high byte of the program counter.
+//
+// https://github.com/hoglet67/Z80Decoder/wiki/Undocumented-Flags
+func blockRepeat(cpu *CPU) {
+	cpu.SetPC(cpu.PC() - 2)
+	cpu.WZ = cpu.pc + 1
+	cpu.F &^= Flag5 | Flag3
+	cpu.F |= uint8(cpu.pc>>8) & (Flag5 | Flag3)
+}
+
+// The half carry and parity flags are also changed when repeating the
+// input and output block instructions. The value is the one that was
+// transferred.
+func blockRepeatIO(cpu *CPU, v uint8) {
+	if cpu.F&FlagC == 0 {
+		if !rcs.Parity(cpu.B & 0x07) {
+			cpu.F ^= FlagP
+		}
+		return
+	}
+	cpu.F &^= FlagH
+	if v&(1<<7) != 0 {
+		if !rcs.Parity((cpu.B - 1) & 0x07) {
+			cpu.F ^= FlagP
+		}
+		if cpu.B&0x0f == 0x00 {
+			cpu.F |= FlagH
+		}
+	} else {
+		if !rcs.Parity((cpu.B + 1) & 0x07) {
+			cpu.F ^= FlagP
+		}
+		if cpu.B&0x0f == 0x0f {
+			cpu.F |= FlagH
+		}
+	}
+}
+
 // call, conditional
 func call(cpu *CPU, flag uint8, condition bool, load rcs.Load) {
 	addr := load()
+	cpu.WZ = uint16(addr)
 	if (cpu.F&flag != 0) == condition {
 		cpu.SP -= 2
 		cpu.mem.WriteLE(int(cpu.SP), cpu.PC())
@@ -197,6 +249,7 @@ func call(cpu *CPU, flag uint8, condition bool, load rcs.Load) {
 // call, always
 func calla(cpu *CPU, load rcs.Load) {
 	addr := load()
+	cpu.WZ = uint16(addr)
 	cpu.SP -= 2
 	cpu.mem.WriteLE(int(cpu.SP), cpu.PC())
 	cpu.SetPC(addr)
@@ -204,8 +257,10 @@ func calla(cpu *CPU, load rcs.Load) {
 
 // invert carry flag
 func ccf(cpu *CPU) {
+	cpu.fset = true
 	// The H flag was tricky. Correct definition in the Z80 User Manual
 	carryIn := cpu.F&FlagC != 0
+	xy := cpu.xyQ()
 	cpu.F &^= FlagH | FlagN | FlagC | Flag5 | Flag3
 	if carryIn {
 		cpu.F |= FlagH
@@ -213,12 +268,7 @@ func ccf(cpu *CPU) {
 	if !carryIn {
 		cpu.F |= FlagC
 	}
-	if cpu.A&(1<<5) != 0 {
-		cpu.F |= Flag5
-	}
-	if cpu.A&(1<<3) != 0 {
-		cpu.F |= Flag3
-	}
+	cpu.F |= xy
 }
 
 // CP is a subtraction from A that doesn't update A, only the flags it would
@@ -226,6 +276,7 @@ func ccf(cpu *CPU) {
 //
 // F5 and F3 are copied from the operand, not the result
 func cp(cpu *CPU, load rcs.Load8) {
+	cpu.fset = true
 	a := cpu.A
 	in := load()
 	sub(cpu, cpu.loadA, func() uint8 { return in })
@@ -241,6 +292,7 @@ func cp(cpu *CPU, load rcs.Load8) {
 
 // invert accumulator, one's complement
 func cpl(cpu *CPU) {
+	cpu.fset = true
 	out := ^cpu.A
 	cpu.F &^= Flag5 | Flag3
 	cpu.F |= FlagH | FlagN
@@ -254,11 +306,13 @@ func cpl(cpu *CPU) {
 }
 
 func cpx(cpu *CPU, increment int) {
+	cpu.fset = true
 	carry := cpu.F&FlagC != 0
 	out, _, fh, _ := rcs.Sub(cpu.A, cpu.loadIndHL(), false)
 
 	cpu.storeHL(cpu.loadHL() + int(increment))
 	cpu.storeBC(cpu.loadBC() - int(1))
+	cpu.WZ += uint16(increment)
 
 	dresult := out
 	if fh {
@@ -297,7 +351,7 @@ func cpxr(cpu *CPU, increment int) {
 	if cpu.F&FlagZ != 0 {
 		return
 	}
-	cpu.SetPC(cpu.PC() - 2)
+	blockRepeat(cpu)
 }
 
 // decimal adjust in a
@@ -308,6 +362,7 @@ func cpxr(cpu *CPU, increment int) {
 //
 // Eventually ported directly from the MAME source code.
 func daa(cpu *CPU) {
+	cpu.fset = true
 	out := cpu.A
 	carry := false
 	half := false
@@ -367,6 +422,7 @@ func daa(cpu *CPU) {
 
 // decrement
 func dec(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	out, _, fh, fv := rcs.Sub(in, 1, false)
 
@@ -414,6 +470,7 @@ func djnz(cpu *CPU, load rcs.Load8) {
 	cpu.B--
 	if cpu.B != 0 {
 		cpu.SetPC(cpu.PC() + int(int8(delta)))
+		cpu.WZ = cpu.pc
 	}
 }
 
@@ -421,6 +478,7 @@ func djnz(cpu *CPU, load rcs.Load8) {
 func ei(cpu *CPU) {
 	cpu.IFF1 = true
 	cpu.IFF2 = true
+	cpu.ei = true
 }
 
 // exchange
@@ -450,6 +508,7 @@ func im(cpu *CPU, mode int) {
 
 // port in
 func in(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	out := load()
 
 	cpu.F &^= FlagS | FlagZ | FlagH | FlagV | FlagN | Flag5 | Flag3
@@ -473,6 +532,7 @@ func in(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // increment
 func inc(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	out, _, fh, fv := rcs.Add(in, 1, false)
 
@@ -508,8 +568,10 @@ func inc16(cpu *CPU, store rcs.Store, load rcs.Load) {
 }
 
 // port in, blocked
-func inx(cpu *CPU, increment int) {
+func inx(cpu *CPU, increment int) uint8 {
+	cpu.fset = true
 	in := cpu.inIndC()
+	cpu.WZ = uint16(cpu.loadBC() + increment)
 	cpu.B--
 
 	// https://github.com/mamedev/mame/blob/master/src/devices/device/proc/z80/z80.cpp
@@ -547,21 +609,23 @@ func inx(cpu *CPU, increment int) {
 	cpu.storeIndHL(in)
 	ihl := (int(cpu.H)<<8 | int(cpu.L)) + increment
 	cpu.H, cpu.L = uint8(ihl>>8), uint8(ihl)
+	return in
 }
 
 // port in, blocked, repeat
 func inxr(cpu *CPU, increment int) {
-	inx(cpu, increment)
-	for cpu.B != 0 {
-		cpu.refreshR()
-		cpu.refreshR()
-		inx(cpu, increment)
+	v := inx(cpu, increment)
+	if cpu.B == 0 {
+		return
 	}
+	blockRepeat(cpu)
+	blockRepeatIO(cpu, v)
 }
 
 // jump absolute, conditional
 func jp(cpu *CPU, flag uint8, condition bool, load rcs.Load) {
 	addr := load()
+	cpu.WZ = uint16(addr)
 	if (cpu.F&flag != 0) == condition {
 		cpu.SetPC(addr)
 	}
@@ -570,6 +634,12 @@ func jp(cpu *CPU, flag uint8, condition bool, load rcs.Load) {
 // jump absolute, always
 func jpa(cpu *CPU, load rcs.Load) {
 	cpu.SetPC(load())
+	cpu.WZ = cpu.pc
+}
+
+// jump to the address in a register, MEMPTR is not changed
+func jpr(cpu *CPU, load rcs.Load) {
+	cpu.SetPC(load())
 }
 
 // jump relative, conditional
@@ -578,6 +648,7 @@ func jr(cpu *CPU, flag uint8, condition bool, load rcs.Load8) {
 	flagSet := cpu.F&flag != 0
 	if flagSet == condition {
 		cpu.SetPC(cpu.PC() + delta)
+		cpu.WZ = cpu.pc
 	}
 }
 
@@ -585,6 +656,7 @@ func jr(cpu *CPU, flag uint8, condition bool, load rcs.Load8) {
 func jra(cpu *CPU, load rcs.Load8) {
 	delta := load()
 	cpu.SetPC(cpu.PC() + int(int8(delta)))
+	cpu.WZ = cpu.pc
 }
 
 // load
@@ -594,6 +666,7 @@ func ld(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // ld a, i or ld a, r
 func ldair(cpu *CPU, load rcs.Load8) {
+	cpu.fset = true
 	out := load()
 
 	cpu.F &^= FlagS | FlagZ | FlagH | FlagV | FlagN | Flag5 | Flag3
@@ -621,6 +694,7 @@ func ld16(cpu *CPU, store rcs.Store, load rcs.Load) {
 }
 
 func ldx(cpu *CPU, increment int) {
+	cpu.fset = true
 	source := int(cpu.H)<<8 | int(cpu.L)
 	target := int(cpu.D)<<8 | int(cpu.E)
 	v := cpu.mem.Read(source)
@@ -650,11 +724,10 @@ func ldx(cpu *CPU, increment int) {
 
 func ldxr(cpu *CPU, increment int) {
 	ldx(cpu, increment)
-	for cpu.B != 0 || cpu.C != 0 {
-		cpu.refreshR()
-		cpu.refreshR()
-		ldx(cpu, increment)
+	if cpu.B == 0 && cpu.C == 0 {
+		return
 	}
+	blockRepeat(cpu)
 }
 
 // no operation
@@ -667,6 +740,7 @@ func neg(cpu *CPU) {
 
 // bitwise logical or
 func or(cpu *CPU, load rcs.Load8) {
+	cpu.fset = true
 	out := cpu.A | load()
 
 	cpu.F = 0
@@ -688,9 +762,11 @@ func or(cpu *CPU, load rcs.Load8) {
 	cpu.A = out
 }
 
-func outx(cpu *CPU, increment int) {
+func outx(cpu *CPU, increment int) uint8 {
+	cpu.fset = true
 	in := cpu.mem.Read(int(cpu.H)<<8 | int(cpu.L))
 	cpu.B--
+	cpu.WZ = uint16(cpu.loadBC() + increment)
 	ihl := (int(cpu.H)<<8 | int(cpu.L)) + increment
 	cpu.H, cpu.L = uint8(ihl>>8), uint8(ihl)
 
@@ -726,17 +802,18 @@ func outx(cpu *CPU, increment int) {
 	if cpu.B&(1<<3) != 0 {
 		cpu.F |= Flag3
 	}
-	cpu.Ports.Write(int(cpu.C), in)
+	cpu.Ports.Write(cpu.port(cpu.B, cpu.C), in)
+	return in
 }
 
 // port out, blocked, repeat
 func outxr(cpu *CPU, increment int) {
-	outx(cpu, increment)
-	for cpu.B != 0 {
-		cpu.refreshR()
-		cpu.refreshR()
-		outx(cpu, increment)
+	v := outx(cpu, increment)
+	if cpu.B == 0 {
+		return
 	}
+	blockRepeat(cpu)
+	blockRepeatIO(cpu, v)
 }
 
 // Copies the two bytes from (SP) into the operand, then increases SP by 2.
@@ -768,23 +845,24 @@ func ret(cpu *CPU, flag uint8, value bool) {
 func reta(cpu *CPU) {
 	cpu.SetPC(cpu.mem.ReadLE(int(cpu.SP)))
 	cpu.SP += 2
+	cpu.WZ = cpu.pc
 }
 
 // return from interrupt
 func reti(cpu *CPU) {
-	cpu.SetPC(cpu.mem.ReadLE(int(cpu.SP)))
-	cpu.SP += 2
+	reta(cpu)
+	cpu.daisyReti()
 }
 
 // return from non-maskable interrupt
 func retn(cpu *CPU) {
 	cpu.IFF1 = cpu.IFF2
-	cpu.SetPC(cpu.mem.ReadLE(int(cpu.SP)))
-	cpu.SP += 2
+	reta(cpu)
 }
 
 // rotate left
 func rl(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&(1<<7) != 0
 	out := in << 1
@@ -816,6 +894,7 @@ func rl(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // rotate accumulator left
 func rla(cpu *CPU) {
+	cpu.fset = true
 	carryOut := cpu.A&(1<<7) != 0
 	out := cpu.A << 1
 	if cpu.F&FlagC != 0 {
@@ -837,6 +916,7 @@ func rla(cpu *CPU) {
 
 // rotate accumulator left with carry
 func rlca(cpu *CPU) {
+	cpu.fset = true
 	carryOut := cpu.A&(1<<7) != 0
 	out := cpu.A << 1
 	if carryOut {
@@ -858,6 +938,7 @@ func rlca(cpu *CPU) {
 
 // rotate left with carry
 func rlc(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&(1<<7) != 0
 	out := in << 1
@@ -888,7 +969,9 @@ func rlc(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 }
 
 func rld(cpu *CPU) {
+	cpu.fset = true
 	addr := int(cpu.H)<<8 | int(cpu.L)
+	cpu.WZ = uint16(addr + 1)
 	ahi, alo := cpu.A>>4, cpu.A&0x0f
 	readv := cpu.mem.Read(addr)
 	memhi, memlo := readv>>4, readv&0x0f
@@ -918,6 +1001,7 @@ func rld(cpu *CPU) {
 
 // rotate right
 func rr(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&1 != 0
 	out := in >> 1
@@ -949,6 +1033,7 @@ func rr(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // rotate accumulator right
 func rra(cpu *CPU) {
+	cpu.fset = true
 	carryOut := cpu.A&1 != 0
 	out := cpu.A >> 1
 	if cpu.F&FlagC != 0 {
@@ -970,6 +1055,7 @@ func rra(cpu *CPU) {
 
 // rotate right
 func rrc(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&1 != 0
 	out := in >> 1
@@ -1002,6 +1088,7 @@ func rrc(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // rotate accumulator right with carry
 func rrca(cpu *CPU) {
+	cpu.fset = true
 	carryOut := cpu.A&1 != 0
 	out := cpu.A >> 1
 	if carryOut {
@@ -1022,7 +1109,9 @@ func rrca(cpu *CPU) {
 }
 
 func rrd(cpu *CPU) {
+	cpu.fset = true
 	addr := int(cpu.H)<<8 | int(cpu.L)
+	cpu.WZ = uint16(addr + 1)
 	ahi, alo := cpu.A>>4, cpu.A&0x0f
 	readv := cpu.mem.Read(addr)
 	memhi, memlo := readv>>4, readv&0x0f
@@ -1055,18 +1144,24 @@ func rst(cpu *CPU, y int) {
 	cpu.SP -= 2
 	cpu.mem.WriteLE(int(cpu.SP), cpu.PC())
 	cpu.SetPC(y * 8)
+	cpu.WZ = cpu.pc
 }
 
 // set carry flag
 func scf(cpu *CPU) {
+	cpu.fset = true
+	xy := cpu.xyQ()
 	cpu.F &^= FlagH | FlagN | Flag5 | Flag3
-	cpu.F |= FlagC
-	if cpu.A&(1<<5) != 0 {
-		cpu.F |= Flag5
-	}
-	if cpu.A&(1<<3) != 0 {
-		cpu.F |= Flag3
-	}
+	cpu.F |= FlagC | xy
+}
+
+// Bits 3 and 5 of the flags for scf and ccf. These come from the
+// accumulator if the previous instruction set the flags. Otherwise, the
+// bits already set in the flags are kept.
+//
+// https://github.com/hoglet67/Z80Decoder/wiki/Undocumented-Flags
+func (cpu *CPU) xyQ() uint8 {
+	return ((cpu.q ^ cpu.F) | cpu.A) & (Flag5 | Flag3)
 }
 
 // set bit
@@ -1077,6 +1172,7 @@ func set(cpu *CPU, n int, store rcs.Store8, load rcs.Load8) {
 
 // shift left, arithemtic
 func sla(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&(1<<7) != 0
 	out := in << 1
@@ -1105,6 +1201,7 @@ func sla(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // shift right, arithemtic
 func sra(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&1 != 0
 	bit7 := in&(1<<7) != 0
@@ -1137,6 +1234,7 @@ func sra(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // undocumented: shift left, logical
 func sll(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&(1<<7) != 0
 	out := in<<1 + 1
@@ -1165,6 +1263,7 @@ func sll(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // shift right, logical
 func srl(cpu *CPU, store rcs.Store8, load rcs.Load8) {
+	cpu.fset = true
 	in := load()
 	carryOut := in&1 != 0
 	out := in >> 1
@@ -1193,6 +1292,7 @@ func srl(cpu *CPU, store rcs.Store8, load rcs.Load8) {
 
 // subtract
 func sub(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
+	cpu.fset = true
 	out, fc, fh, fv := rcs.Sub(load0(), load1(), false)
 
 	cpu.F = 0
@@ -1223,6 +1323,7 @@ func sub(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
 
 // subtract with carry
 func sbc(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
+	cpu.fset = true
 	out, fc, fh, fv := rcs.Sub(load0(), load1(), cpu.F&FlagC != 0)
 
 	cpu.F = 0
@@ -1253,8 +1354,10 @@ func sbc(cpu *CPU, load0 rcs.Load8, load1 rcs.Load8) {
 
 // subtract 16-bit with carry
 func sbc16(cpu *CPU, store rcs.Store, load0 rcs.Load, load1 rcs.Load) {
+	cpu.fset = true
 	in0 := load0()
 	in1 := load1()
+	cpu.WZ = uint16(in0 + 1)
 
 	lo, fc, _, _ := rcs.Sub(uint8(in0), uint8(in1), cpu.F&FlagC != 0)
 	hi, fc, fh, fv := rcs.Sub(uint8(in0>>8), uint8(in1>>8), fc)
@@ -1287,6 +1390,7 @@ func sbc16(cpu *CPU, store rcs.Store, load0 rcs.Load, load1 rcs.Load) {
 
 // bitwise logical exclusive or
 func xor(cpu *CPU, load rcs.Load8) {
+	cpu.fset = true
 	out := cpu.A ^ load()
 
 	cpu.F = 0
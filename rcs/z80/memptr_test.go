@@ -0,0 +1,204 @@
+package z80
+
+import (
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/mock"
+)
+
+func newMemptrCPU(code ...uint8) *CPU {
+	mock.ResetMemory()
+	cpu := New(mock.TestMemory)
+	cpu.SP = 0x8000
+	cpu.mem.WriteN(0, code...)
+	return cpu
+}
+
+func TestMemptr(t *testing.T) {
+	var tests = []struct {
+		name  string
+		code  []uint8
+		setup func(*CPU)
+		want  uint16
+	}{
+		{"ld a,(nn)", []uint8{0x3a, 0x34, 0x12}, nil, 0x1235},
+		{"ld (nn),a", []uint8{0x32, 0xff, 0x12}, func(c *CPU) {
+			c.A = 0x56
+		}, 0x5600},
+		{"ld a,(bc)", []uint8{0x0a}, func(c *CPU) {
+			c.B, c.C = 0x12, 0x34
+		}, 0x1235},
+		{"ld (de),a", []uint8{0x12}, func(c *CPU) {
+			c.D, c.E, c.A = 0x12, 0xff, 0x56
+		}, 0x5600},
+		{"ld hl,(nn)", []uint8{0x2a, 0x34, 0x12}, nil, 0x1235},
+		{"ld (nn),bc", []uint8{0xed, 0x43, 0x34, 0x12}, nil, 0x1235},
+		{"ex (sp),hl", []uint8{0xe3}, func(c *CPU) {
+			c.mem.WriteLE(0x8000, 0x5678)
+		}, 0x5678},
+		{"add hl,bc", []uint8{0x09}, func(c *CPU) {
+			c.H, c.L = 0x12, 0x34
+		}, 0x1235},
+		{"sbc hl,de", []uint8{0xed, 0x52}, func(c *CPU) {
+			c.H, c.L = 0x12, 0x34
+		}, 0x1235},
+		{"rld", []uint8{0xed, 0x6f}, func(c *CPU) {
+			c.H, c.L = 0x12, 0x34
+		}, 0x1235},
+		{"jp nn", []uint8{0xc3, 0x34, 0x12}, nil, 0x1234},
+		{"jp nz,nn not taken", []uint8{0xc2, 0x34, 0x12}, func(c *CPU) {
+			c.F = FlagZ
+		}, 0x1234},
+		{"call nz,nn not taken", []uint8{0xc4, 0x34, 0x12}, func(c *CPU) {
+			c.F = FlagZ
+		}, 0x1234},
+		{"jp (hl)", []uint8{0xe9}, func(c *CPU) {
+			c.H, c.L = 0x12, 0x34
+			c.WZ = 0xabcd
+		}, 0xabcd},
+		{"jr", []uint8{0x18, 0x10}, nil, 0x0012},
+		{"ret", []uint8{0xc9}, func(c *CPU) {
+			c.mem.WriteLE(0x8000, 0x1234)
+		}, 0x1234},
+		{"rst $38", []uint8{0xff}, nil, 0x0038},
+		{"in a,(n)", []uint8{0xdb, 0x34}, func(c *CPU) {
+			c.A = 0x12
+		}, 0x1235},
+		{"out (n),a", []uint8{0xd3, 0xff}, func(c *CPU) {
+			c.A = 0x12
+		}, 0x1200},
+		{"in b,(c)", []uint8{0xed, 0x40}, func(c *CPU) {
+			c.B, c.C = 0x12, 0x34
+		}, 0x1235},
+		{"ld a,(ix+d)", []uint8{0xdd, 0x7e, 0xf0}, func(c *CPU) {
+			c.IXH, c.IXL = 0x12, 0x34
+		}, 0x1224},
+		{"cpi", []uint8{0xed, 0xa1}, func(c *CPU) {
+			c.WZ = 0x1000
+		}, 0x1001},
+		{"cpd", []uint8{0xed, 0xa9}, func(c *CPU) {
+			c.WZ = 0x1000
+		}, 0x0fff},
+		{"ini", []uint8{0xed, 0xa2}, func(c *CPU) {
+			c.B, c.C = 0x02, 0x34
+			c.H, c.L = 0x40, 0x00
+		}, 0x0235},
+		{"outd", []uint8{0xed, 0xab}, func(c *CPU) {
+			c.B, c.C = 0x02, 0x34
+			c.H, c.L = 0x40, 0x00
+		}, 0x0133},
+		{"ldir repeat", []uint8{0xed, 0xb0}, func(c *CPU) {
+			c.B, c.C = 0x00, 0x02
+			c.H, c.L = 0x40, 0x00
+			c.D, c.E = 0x50, 0x00
+		}, 0x0001},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := newMemptrCPU(test.code...)
+			if test.setup != nil {
+				test.setup(cpu)
+			}
+			cpu.Next()
+			if cpu.WZ != test.want {
+				t.Errorf("\n have: %04x \n want: %04x", cpu.WZ, test.want)
+			}
+		})
+	}
+}
+
+func TestBitIndHLMemptr(t *testing.T) {
+	cpu := newMemptrCPU(
+		0x3a, 0x34, 0x28, // ld a,($2834)
+		0xcb, 0x46, // bit 0,(hl)
+	)
+	cpu.H, cpu.L = 0x40, 0x00
+	cpu.Next()
+	cpu.Next()
+	want := FlagZ | FlagH | FlagV | Flag5 | Flag3
+	if cpu.F != want {
+		t.Errorf("\n have: %02x \n want: %02x", cpu.F, want)
+	}
+}
+
+func TestScfQ(t *testing.T) {
+	// flags set by the previous instruction, bits 3 and 5 from A
+	cpu := newMemptrCPU(
+		0x97, // sub a
+		0x37, // scf
+	)
+	cpu.Next()
+	cpu.Next()
+	want := FlagZ | FlagC
+	if cpu.F != want {
+		t.Errorf("\n have: %02x \n want: %02x", cpu.F, want)
+	}
+
+	// flags unchanged by the previous instruction, bits 3 and 5 kept
+	cpu = newMemptrCPU(
+		0x00, // nop
+		0x37, // scf
+	)
+	cpu.F = Flag5 | Flag3
+	cpu.Next()
+	cpu.Next()
+	want = Flag5 | Flag3 | FlagC
+	if cpu.F != want {
+		t.Errorf("\n have: %02x \n want: %02x", cpu.F, want)
+	}
+}
+
+func TestLdirInterrupted(t *testing.T) {
+	cpu := newMemptrCPU()
+	cpu.mem.WriteN(0x2800, 0xed, 0xb0) // ldir
+	cpu.SetPC(0x2800)
+	cpu.B, cpu.C = 0x00, 0x02
+	cpu.H, cpu.L = 0x40, 0x00
+	cpu.D, cpu.E = 0x50, 0x00
+
+	cpu.Next()
+	if cpu.PC() != 0x2800 {
+		t.Fatalf("\n have: %04x \n want: %04x", cpu.PC(), 0x2800)
+	}
+	want := FlagV | Flag5 | Flag3
+	if cpu.F != want {
+		t.Errorf("\n have: %02x \n want: %02x", cpu.F, want)
+	}
+
+	cpu.Next()
+	if cpu.PC() != 0x2802 {
+		t.Fatalf("\n have: %04x \n want: %04x", cpu.PC(), 0x2802)
+	}
+	want = 0
+	if cpu.F != want {
+		t.Errorf("\n have: %02x \n want: %02x", cpu.F, want)
+	}
+}
+
+func TestInirInterrupted(t *testing.T) {
+	var tests = []struct {
+		name string
+		in   uint8
+		want uint8
+	}{
+		{"no carry", 0x01, Flag5 | Flag3},
+		{"carry", 0xff, Flag5 | Flag3 | FlagN | FlagC},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			cpu := newMemptrCPU()
+			cpu.mem.WriteN(0x2800, 0xed, 0xb2) // inir
+			cpu.SetPC(0x2800)
+			cpu.Ports.Write(0x10, test.in)
+			cpu.B, cpu.C = 0x02, 0x10
+			cpu.H, cpu.L = 0x40, 0x00
+			cpu.Next()
+			if cpu.PC() != 0x2800 {
+				t.Fatalf("\n have: %04x \n want: %04x", cpu.PC(), 0x2800)
+			}
+			if cpu.F != test.want {
+				t.Errorf("\n have: %02x \n want: %02x", cpu.F, test.want)
+			}
+		})
+	}
+}
@@ -2,7 +2,10 @@
 
 package z80
 
-var opcodes = map[uint8]func(c *CPU){
+// Instructions are indexed by opcode. Entries for opcodes that are not
+// implemented are nil.
+
+var opcodes = [256]func(c *CPU){
 	0x00: func(c *CPU) { nop() },
 	0x01: func(c *CPU) { ld16(c, c.storeBC, c.loadImm16) },
 	0x02: func(c *CPU) { ld(c, c.storeIndBC, c.loadA) },
@@ -234,7 +237,7 @@ var opcodes = map[uint8]func(c *CPU){
 	0xe6: func(c *CPU) { and(c, c.loadImm) },
 	0xe7: func(c *CPU) { rst(c, 4) },
 	0xe8: func(c *CPU) { ret(c, FlagV, true) },
-	0xe9: func(c *CPU) { jpa(c, c.loadHL) },
+	0xe9: func(c *CPU) { jpr(c, c.loadHL) },
 	0xea: func(c *CPU) { jp(c, FlagV, true, c.loadImm16) },
 	0xeb: func(c *CPU) { ex(c, c.loadDE, c.storeDE, c.loadHL, c.storeHL) },
 	0xec: func(c *CPU) { call(c, FlagV, true, c.loadImm16) },
@@ -256,7 +259,7 @@ var opcodes = map[uint8]func(c *CPU){
 	0xfe: func(c *CPU) { cp(c, c.loadImm) },
 	0xff: func(c *CPU) { rst(c, 7) },
 }
-var opcodesCB = map[uint8]func(c *CPU){
+var opcodesCB = [256]func(c *CPU){
 	0x00: func(c *CPU) { rlc(c, c.storeB, c.loadB) },
 	0x01: func(c *CPU) { rlc(c, c.storeC, c.loadC) },
 	0x02: func(c *CPU) { rlc(c, c.storeD, c.loadD) },
@@ -327,7 +330,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x43: func(c *CPU) { bit(c, 0, c.loadE) },
 	0x44: func(c *CPU) { bit(c, 0, c.loadH) },
 	0x45: func(c *CPU) { bit(c, 0, c.loadL) },
-	0x46: func(c *CPU) { bit(c, 0, c.loadIndHL) },
+	0x46: func(c *CPU) { biti(c, 0, c.loadIndHL) },
 	0x47: func(c *CPU) { bit(c, 0, c.loadA) },
 	0x48: func(c *CPU) { bit(c, 1, c.loadB) },
 	0x49: func(c *CPU) { bit(c, 1, c.loadC) },
@@ -335,7 +338,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x4b: func(c *CPU) { bit(c, 1, c.loadE) },
 	0x4c: func(c *CPU) { bit(c, 1, c.loadH) },
 	0x4d: func(c *CPU) { bit(c, 1, c.loadL) },
-	0x4e: func(c *CPU) { bit(c, 1, c.loadIndHL) },
+	0x4e: func(c *CPU) { biti(c, 1, c.loadIndHL) },
 	0x4f: func(c *CPU) { bit(c, 1, c.loadA) },
 	0x50: func(c *CPU) { bit(c, 2, c.loadB) },
 	0x51: func(c *CPU) { bit(c, 2, c.loadC) },
@@ -343,7 +346,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x53: func(c *CPU) { bit(c, 2, c.loadE) },
 	0x54: func(c *CPU) { bit(c, 2, c.loadH) },
 	0x55: func(c *CPU) { bit(c, 2, c.loadL) },
-	0x56: func(c *CPU) { bit(c, 2, c.loadIndHL) },
+	0x56: func(c *CPU) { biti(c, 2, c.loadIndHL) },
 	0x57: func(c *CPU) { bit(c, 2, c.loadA) },
 	0x58: func(c *CPU) { bit(c, 3, c.loadB) },
 	0x59: func(c *CPU) { bit(c, 3, c.loadC) },
@@ -351,7 +354,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x5b: func(c *CPU) { bit(c, 3, c.loadE) },
 	0x5c: func(c *CPU) { bit(c, 3, c.loadH) },
 	0x5d: func(c *CPU) { bit(c, 3, c.loadL) },
-	0x5e: func(c *CPU) { bit(c, 3, c.loadIndHL) },
+	0x5e: func(c *CPU) { biti(c, 3, c.loadIndHL) },
 	0x5f: func(c *CPU) { bit(c, 3, c.loadA) },
 	0x60: func(c *CPU) { bit(c, 4, c.loadB) },
 	0x61: func(c *CPU) { bit(c, 4, c.loadC) },
@@ -359,7 +362,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x63: func(c *CPU) { bit(c, 4, c.loadE) },
 	0x64: func(c *CPU) { bit(c, 4, c.loadH) },
 	0x65: func(c *CPU) { bit(c, 4, c.loadL) },
-	0x66: func(c *CPU) { bit(c, 4, c.loadIndHL) },
+	0x66: func(c *CPU) { biti(c, 4, c.loadIndHL) },
 	0x67: func(c *CPU) { bit(c, 4, c.loadA) },
 	0x68: func(c *CPU) { bit(c, 5, c.loadB) },
 	0x69: func(c *CPU) { bit(c, 5, c.loadC) },
@@ -367,7 +370,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x6b: func(c *CPU) { bit(c, 5, c.loadE) },
 	0x6c: func(c *CPU) { bit(c, 5, c.loadH) },
 	0x6d: func(c *CPU) { bit(c, 5, c.loadL) },
-	0x6e: func(c *CPU) { bit(c, 5, c.loadIndHL) },
+	0x6e: func(c *CPU) { biti(c, 5, c.loadIndHL) },
 	0x6f: func(c *CPU) { bit(c, 5, c.loadA) },
 	0x70: func(c *CPU) { bit(c, 6, c.loadB) },
 	0x71: func(c *CPU) { bit(c, 6, c.loadC) },
@@ -375,7 +378,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x73: func(c *CPU) { bit(c, 6, c.loadE) },
 	0x74: func(c *CPU) { bit(c, 6, c.loadH) },
 	0x75: func(c *CPU) { bit(c, 6, c.loadL) },
-	0x76: func(c *CPU) { bit(c, 6, c.loadIndHL) },
+	0x76: func(c *CPU) { biti(c, 6, c.loadIndHL) },
 	0x77: func(c *CPU) { bit(c, 6, c.loadA) },
 	0x78: func(c *CPU) { bit(c, 7, c.loadB) },
 	0x79: func(c *CPU) { bit(c, 7, c.loadC) },
@@ -383,7 +386,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0x7b: func(c *CPU) { bit(c, 7, c.loadE) },
 	0x7c: func(c *CPU) { bit(c, 7, c.loadH) },
 	0x7d: func(c *CPU) { bit(c, 7, c.loadL) },
-	0x7e: func(c *CPU) { bit(c, 7, c.loadIndHL) },
+	0x7e: func(c *CPU) { biti(c, 7, c.loadIndHL) },
 	0x7f: func(c *CPU) { bit(c, 7, c.loadA) },
 	0x80: func(c *CPU) { res(c, 0, c.storeB, c.loadB) },
 	0x81: func(c *CPU) { res(c, 0, c.storeC, c.loadC) },
@@ -514,7 +517,7 @@ var opcodesCB = map[uint8]func(c *CPU){
 	0xfe: func(c *CPU) { set(c, 7, c.storeIndHL, c.loadIndHL) },
 	0xff: func(c *CPU) { set(c, 7, c.storeA, c.loadA) },
 }
-var opcodesED = map[uint8]func(c *CPU){
+var opcodesED = [256]func(c *CPU){
 	0x40: func(c *CPU) { in(c, c.storeB, c.loadIndC) },
 	0x41: func(c *CPU) { ld(c, c.outIndC, c.loadB) },
 	0x42: func(c *CPU) { sbc16(c, c.storeHL, c.loadHL, c.loadBC) },
@@ -594,7 +597,7 @@ var opcodesED = map[uint8]func(c *CPU){
 	0xba: func(c *CPU) { inxr(c, -1) },
 	0xbb: func(c *CPU) { outxr(c, -1) },
 }
-var opcodesDD = map[uint8]func(c *CPU){
+var opcodesDD = [256]func(c *CPU){
 	0x00: func(c *CPU) { nop() },
 	0x01: func(c *CPU) { ld16(c, c.storeBC, c.loadImm16) },
 	0x02: func(c *CPU) { ld(c, c.storeIndBC, c.loadA) },
@@ -826,7 +829,7 @@ var opcodesDD = map[uint8]func(c *CPU){
 	0xe6: func(c *CPU) { and(c, c.loadImm) },
 	0xe7: func(c *CPU) { rst(c, 4) },
 	0xe8: func(c *CPU) { ret(c, FlagV, true) },
-	0xe9: func(c *CPU) { jpa(c, c.loadIX) },
+	0xe9: func(c *CPU) { jpr(c, c.loadIX) },
 	0xea: func(c *CPU) { jp(c, FlagV, true, c.loadImm16) },
 	0xeb: func(c *CPU) { ex(c, c.loadDE, c.storeDE, c.loadHL, c.storeHL) },
 	0xec: func(c *CPU) { call(c, FlagV, true, c.loadImm16) },
@@ -848,7 +851,7 @@ var opcodesDD = map[uint8]func(c *CPU){
 	0xfe: func(c *CPU) { cp(c, c.loadImm) },
 	0xff: func(c *CPU) { rst(c, 7) },
 }
-var opcodesFD = map[uint8]func(c *CPU){
+var opcodesFD = [256]func(c *CPU){
 	0x00: func(c *CPU) { nop() },
 	0x01: func(c *CPU) { ld16(c, c.storeBC, c.loadImm16) },
 	0x02: func(c *CPU) { ld(c, c.storeIndBC, c.loadA) },
@@ -1080,7 +1083,7 @@ var opcodesFD = map[uint8]func(c *CPU){
 	0xe6: func(c *CPU) { and(c, c.loadImm) },
 	0xe7: func(c *CPU) { rst(c, 4) },
 	0xe8: func(c *CPU) { ret(c, FlagV, true) },
-	0xe9: func(c *CPU) { jpa(c, c.loadIY) },
+	0xe9: func(c *CPU) { jpr(c, c.loadIY) },
 	0xea: func(c *CPU) { jp(c, FlagV, true, c.loadImm16) },
 	0xeb: func(c *CPU) { ex(c, c.loadDE, c.storeDE, c.loadHL, c.storeHL) },
 	0xec: func(c *CPU) { call(c, FlagV, true, c.loadImm16) },
@@ -1102,7 +1105,7 @@ var opcodesFD = map[uint8]func(c *CPU){
 	0xfe: func(c *CPU) { cp(c, c.loadImm) },
 	0xff: func(c *CPU) { rst(c, 7) },
 }
-var opcodesDDCB = map[uint8]func(c *CPU){
+var opcodesDDCB = [256]func(c *CPU){
 	0x00: func(c *CPU) { rlc(c, c.storeB, c.loadIndIX); ld(c, c.storeLastInd, c.loadB) },
 	0x01: func(c *CPU) { rlc(c, c.storeC, c.loadIndIX); ld(c, c.storeLastInd, c.loadC) },
 	0x02: func(c *CPU) { rlc(c, c.storeD, c.loadIndIX); ld(c, c.storeLastInd, c.loadD) },
@@ -1360,7 +1363,7 @@ var opcodesDDCB = map[uint8]func(c *CPU){
 	0xfe: func(c *CPU) { set(c, 7, c.storeLastInd, c.loadIndIX) },
 	0xff: func(c *CPU) { set(c, 7, c.storeA, c.loadIndIX); ld(c, c.storeLastInd, c.loadA) },
 }
-var opcodesFDCB = map[uint8]func(c *CPU){
+var opcodesFDCB = [256]func(c *CPU){
 	0x00: func(c *CPU) { rlc(c, c.storeB, c.loadIndIY); ld(c, c.storeLastInd, c.loadB) },
 	0x01: func(c *CPU) { rlc(c, c.storeC, c.loadIndIY); ld(c, c.storeLastInd, c.loadC) },
 	0x02: func(c *CPU) { rlc(c, c.storeD, c.loadIndIY); ld(c, c.storeLastInd, c.loadD) },
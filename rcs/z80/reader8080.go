@@ -0,0 +1,292 @@
+package z80
+
+import (
+	"fmt"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+// Reader8080 disassembles the instructions of the 8080 using the Intel
+// mnemonics.
+func Reader8080(e rcs.StmtEval) {
+	e.Stmt.Addr = e.Ptr.Addr()
+	opcode := e.Ptr.Fetch()
+	e.Stmt.Bytes = []uint8{opcode}
+	dasmTable8080[opcode](e)
+}
+
+// ReaderFor returns a reader that disassembles the instructions of the
+// variant.
+func ReaderFor(v Variant) rcs.CodeReader {
+	if v == I8080 {
+		return Reader8080
+	}
+	return Reader
+}
+
+// Restart statements have the argument encoded in the opcode and Intel
+// uses the number of the restart instead of the address.
+func opRst8080(e rcs.StmtEval, n int) {
+	e.Stmt.Op = fmt.Sprintf("%-4s %d", "rst", n)
+}
+
+// The Z80 only instructions in the unprefixed table, and the prefixes,
+// are shown as the 8080 instruction they execute.
+var dasmTable8080 = map[uint8]func(rcs.StmtEval){
+	0x00: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x01: func(e rcs.StmtEval) { op1(e, "lxi", "b", "&0000") },
+	0x02: func(e rcs.StmtEval) { op1(e, "stax", "b") },
+	0x03: func(e rcs.StmtEval) { op1(e, "inx", "b") },
+	0x04: func(e rcs.StmtEval) { op1(e, "inr", "b") },
+	0x05: func(e rcs.StmtEval) { op1(e, "dcr", "b") },
+	0x06: func(e rcs.StmtEval) { op1(e, "mvi", "b", "&00") },
+	0x07: func(e rcs.StmtEval) { op1(e, "rlc") },
+	0x08: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x09: func(e rcs.StmtEval) { op1(e, "dad", "b") },
+	0x0a: func(e rcs.StmtEval) { op1(e, "ldax", "b") },
+	0x0b: func(e rcs.StmtEval) { op1(e, "dcx", "b") },
+	0x0c: func(e rcs.StmtEval) { op1(e, "inr", "c") },
+	0x0d: func(e rcs.StmtEval) { op1(e, "dcr", "c") },
+	0x0e: func(e rcs.StmtEval) { op1(e, "mvi", "c", "&00") },
+	0x0f: func(e rcs.StmtEval) { op1(e, "rrc") },
+	0x10: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x11: func(e rcs.StmtEval) { op1(e, "lxi", "d", "&0000") },
+	0x12: func(e rcs.StmtEval) { op1(e, "stax", "d") },
+	0x13: func(e rcs.StmtEval) { op1(e, "inx", "d") },
+	0x14: func(e rcs.StmtEval) { op1(e, "inr", "d") },
+	0x15: func(e rcs.StmtEval) { op1(e, "dcr", "d") },
+	0x16: func(e rcs.StmtEval) { op1(e, "mvi", "d", "&00") },
+	0x17: func(e rcs.StmtEval) { op1(e, "ral") },
+	0x18: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x19: func(e rcs.StmtEval) { op1(e, "dad", "d") },
+	0x1a: func(e rcs.StmtEval) { op1(e, "ldax", "d") },
+	0x1b: func(e rcs.StmtEval) { op1(e, "dcx", "d") },
+	0x1c: func(e rcs.StmtEval) { op1(e, "inr", "e") },
+	0x1d: func(e rcs.StmtEval) { op1(e, "dcr", "e") },
+	0x1e: func(e rcs.StmtEval) { op1(e, "mvi", "e", "&00") },
+	0x1f: func(e rcs.StmtEval) { op1(e, "rar") },
+	0x20: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x21: func(e rcs.StmtEval) { op1(e, "lxi", "h", "&0000") },
+	0x22: func(e rcs.StmtEval) { op1(e, "shld", "&0000") },
+	0x23: func(e rcs.StmtEval) { op1(e, "inx", "h") },
+	0x24: func(e rcs.StmtEval) { op1(e, "inr", "h") },
+	0x25: func(e rcs.StmtEval) { op1(e, "dcr", "h") },
+	0x26: func(e rcs.StmtEval) { op1(e, "mvi", "h", "&00") },
+	0x27: func(e rcs.StmtEval) { op1(e, "daa") },
+	0x28: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x29: func(e rcs.StmtEval) { op1(e, "dad", "h") },
+	0x2a: func(e rcs.StmtEval) { op1(e, "lhld", "&0000") },
+	0x2b: func(e rcs.StmtEval) { op1(e, "dcx", "h") },
+	0x2c: func(e rcs.StmtEval) { op1(e, "inr", "l") },
+	0x2d: func(e rcs.StmtEval) { op1(e, "dcr", "l") },
+	0x2e: func(e rcs.StmtEval) { op1(e, "mvi", "l", "&00") },
+	0x2f: func(e rcs.StmtEval) { op1(e, "cma") },
+	0x30: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x31: func(e rcs.StmtEval) { op1(e, "lxi", "sp", "&0000") },
+	0x32: func(e rcs.StmtEval) { op1(e, "sta", "&0000") },
+	0x33: func(e rcs.StmtEval) { op1(e, "inx", "sp") },
+	0x34: func(e rcs.StmtEval) { op1(e, "inr", "m") },
+	0x35: func(e rcs.StmtEval) { op1(e, "dcr", "m") },
+	0x36: func(e rcs.StmtEval) { op1(e, "mvi", "m", "&00") },
+	0x37: func(e rcs.StmtEval) { op1(e, "stc") },
+	0x38: func(e rcs.StmtEval) { op1(e, "nop") },
+	0x39: func(e rcs.StmtEval) { op1(e, "dad", "sp") },
+	0x3a: func(e rcs.StmtEval) { op1(e, "lda", "&0000") },
+	0x3b: func(e rcs.StmtEval) { op1(e, "dcx", "sp") },
+	0x3c: func(e rcs.StmtEval) { op1(e, "inr", "a") },
+	0x3d: func(e rcs.StmtEval) { op1(e, "dcr", "a") },
+	0x3e: func(e rcs.StmtEval) { op1(e, "mvi", "a", "&00") },
+	0x3f: func(e rcs.StmtEval) { op1(e, "cmc") },
+	0x40: func(e rcs.StmtEval) { op1(e, "mov", "b", "b") },
+	0x41: func(e rcs.StmtEval) { op1(e, "mov", "b", "c") },
+	0x42: func(e rcs.StmtEval) { op1(e, "mov", "b", "d") },
+	0x43: func(e rcs.StmtEval) { op1(e, "mov", "b", "e") },
+	0x44: func(e rcs.StmtEval) { op1(e, "mov", "b", "h") },
+	0x45: func(e rcs.StmtEval) { op1(e, "mov", "b", "l") },
+	0x46: func(e rcs.StmtEval) { op1(e, "mov", "b", "m") },
+	0x47: func(e rcs.StmtEval) { op1(e, "mov", "b", "a") },
+	0x48: func(e rcs.StmtEval) { op1(e, "mov", "c", "b") },
+	0x49: func(e rcs.StmtEval) { op1(e, "mov", "c", "c") },
+	0x4a: func(e rcs.StmtEval) { op1(e, "mov", "c", "d") },
+	0x4b: func(e rcs.StmtEval) { op1(e, "mov", "c", "e") },
+	0x4c: func(e rcs.StmtEval) { op1(e, "mov", "c", "h") },
+	0x4d: func(e rcs.StmtEval) { op1(e, "mov", "c", "l") },
+	0x4e: func(e rcs.StmtEval) { op1(e, "mov", "c", "m") },
+	0x4f: func(e rcs.StmtEval) { op1(e, "mov", "c", "a") },
+	0x50: func(e rcs.StmtEval) { op1(e, "mov", "d", "b") },
+	0x51: func(e rcs.StmtEval) { op1(e, "mov", "d", "c") },
+	0x52: func(e rcs.StmtEval) { op1(e, "mov", "d", "d") },
+	0x53: func(e rcs.StmtEval) { op1(e, "mov", "d", "e") },
+	0x54: func(e rcs.StmtEval) { op1(e, "mov", "d", "h") },
+	0x55: func(e rcs.StmtEval) { op1(e, "mov", "d", "l") },
+	0x56: func(e rcs.StmtEval) { op1(e, "mov", "d", "m") },
+	0x57: func(e rcs.StmtEval) { op1(e, "mov", "d", "a") },
+	0x58: func(e rcs.StmtEval) { op1(e, "mov", "e", "b") },
+	0x59: func(e rcs.StmtEval) { op1(e, "mov", "e", "c") },
+	0x5a: func(e rcs.StmtEval) { op1(e, "mov", "e", "d") },
+	0x5b: func(e rcs.StmtEval) { op1(e, "mov", "e", "e") },
+	0x5c: func(e rcs.StmtEval) { op1(e, "mov", "e", "h") },
+	0x5d: func(e rcs.StmtEval) { op1(e, "mov", "e", "l") },
+	0x5e: func(e rcs.StmtEval) { op1(e, "mov", "e", "m") },
+	0x5f: func(e rcs.StmtEval) { op1(e, "mov", "e", "a") },
+	0x60: func(e rcs.StmtEval) { op1(e, "mov", "h", "b") },
+	0x61: func(e rcs.StmtEval) { op1(e, "mov", "h", "c") },
+	0x62: func(e rcs.StmtEval) { op1(e, "mov", "h", "d") },
+	0x63: func(e rcs.StmtEval) { op1(e, "mov", "h", "e") },
+	0x64: func(e rcs.StmtEval) { op1(e, "mov", "h", "h") },
+	0x65: func(e rcs.StmtEval) { op1(e, "mov", "h", "l") },
+	0x66: func(e rcs.StmtEval) { op1(e, "mov", "h", "m") },
+	0x67: func(e rcs.StmtEval) { op1(e, "mov", "h", "a") },
+	0x68: func(e rcs.StmtEval) { op1(e, "mov", "l", "b") },
+	0x69: func(e rcs.StmtEval) { op1(e, "mov", "l", "c") },
+	0x6a: func(e rcs.StmtEval) { op1(e, "mov", "l", "d") },
+	0x6b: func(e rcs.StmtEval) { op1(e, "mov", "l", "e") },
+	0x6c: func(e rcs.StmtEval) { op1(e, "mov", "l", "h") },
+	0x6d: func(e rcs.StmtEval) { op1(e, "mov", "l", "l") },
+	0x6e: func(e rcs.StmtEval) { op1(e, "mov", "l", "m") },
+	0x6f: func(e rcs.StmtEval) { op1(e, "mov", "l", "a") },
+	0x70: func(e rcs.StmtEval) { op1(e, "mov", "m", "b") },
+	0x71: func(e rcs.StmtEval) { op1(e, "mov", "m", "c") },
+	0x72: func(e rcs.StmtEval) { op1(e, "mov", "m", "d") },
+	0x73: func(e rcs.StmtEval) { op1(e, "mov", "m", "e") },
+	0x74: func(e rcs.StmtEval) { op1(e, "mov", "m", "h") },
+	0x75: func(e rcs.StmtEval) { op1(e, "mov", "m", "l") },
+	0x76: func(e rcs.StmtEval) { op1(e, "hlt") },
+	0x77: func(e rcs.StmtEval) { op1(e, "mov", "m", "a") },
+	0x78: func(e rcs.StmtEval) { op1(e, "mov", "a", "b") },
+	0x79: func(e rcs.StmtEval) { op1(e, "mov", "a", "c") },
+	0x7a: func(e rcs.StmtEval) { op1(e, "mov", "a", "d") },
+	0x7b: func(e rcs.StmtEval) { op1(e, "mov", "a", "e") },
+	0x7c: func(e rcs.StmtEval) { op1(e, "mov", "a", "h") },
+	0x7d: func(e rcs.StmtEval) { op1(e, "mov", "a", "l") },
+	0x7e: func(e rcs.StmtEval) { op1(e, "mov", "a", "m") },
+	0x7f: func(e rcs.StmtEval) { op1(e, "mov", "a", "a") },
+	0x80: func(e rcs.StmtEval) { op1(e, "add", "b") },
+	0x81: func(e rcs.StmtEval) { op1(e, "add", "c") },
+	0x82: func(e rcs.StmtEval) { op1(e, "add", "d") },
+	0x83: func(e rcs.StmtEval) { op1(e, "add", "e") },
+	0x84: func(e rcs.StmtEval) { op1(e, "add", "h") },
+	0x85: func(e rcs.StmtEval) { op1(e, "add", "l") },
+	0x86: func(e rcs.StmtEval) { op1(e, "add", "m") },
+	0x87: func(e rcs.StmtEval) { op1(e, "add", "a") },
+	0x88: func(e rcs.StmtEval) { op1(e, "adc", "b") },
+	0x89: func(e rcs.StmtEval) { op1(e, "adc", "c") },
+	0x8a: func(e rcs.StmtEval) { op1(e, "adc", "d") },
+	0x8b: func(e rcs.StmtEval) { op1(e, "adc", "e") },
+	0x8c: func(e rcs.StmtEval) { op1(e, "adc", "h") },
+	0x8d: func(e rcs.StmtEval) { op1(e, "adc", "l") },
+	0x8e: func(e rcs.StmtEval) { op1(e, "adc", "m") },
+	0x8f: func(e rcs.StmtEval) { op1(e, "adc", "a") },
+	0x90: func(e rcs.StmtEval) { op1(e, "sub", "b") },
+	0x91: func(e rcs.StmtEval) { op1(e, "sub", "c") },
+	0x92: func(e rcs.StmtEval) { op1(e, "sub", "d") },
+	0x93: func(e rcs.StmtEval) { op1(e, "sub", "e") },
+	0x94: func(e rcs.StmtEval) { op1(e, "sub", "h") },
+	0x95: func(e rcs.StmtEval) { op1(e, "sub", "l") },
+	0x96: func(e rcs.StmtEval) { op1(e, "sub", "m") },
+	0x97: func(e rcs.StmtEval) { op1(e, "sub", "a") },
+	0x98: func(e rcs.StmtEval) { op1(e, "sbb", "b") },
+	0x99: func(e rcs.StmtEval) { op1(e, "sbb", "c") },
+	0x9a: func(e rcs.StmtEval) { op1(e, "sbb", "d") },
+	0x9b: func(e rcs.StmtEval) { op1(e, "sbb", "e") },
+	0x9c: func(e rcs.StmtEval) { op1(e, "sbb", "h") },
+	0x9d: func(e rcs.StmtEval) { op1(e, "sbb", "l") },
+	0x9e: func(e rcs.StmtEval) { op1(e, "sbb", "m") },
+	0x9f: func(e rcs.StmtEval) { op1(e, "sbb", "a") },
+	0xa0: func(e rcs.StmtEval) { op1(e, "ana", "b") },
+	0xa1: func(e rcs.StmtEval) { op1(e, "ana", "c") },
+	0xa2: func(e rcs.StmtEval) { op1(e, "ana", "d") },
+	0xa3: func(e rcs.StmtEval) { op1(e, "ana", "e") },
+	0xa4: func(e rcs.StmtEval) { op1(e, "ana", "h") },
+	0xa5: func(e rcs.StmtEval) { op1(e, "ana", "l") },
+	0xa6: func(e rcs.StmtEval) { op1(e, "ana", "m") },
+	0xa7: func(e rcs.StmtEval) { op1(e, "ana", "a") },
+	0xa8: func(e rcs.StmtEval) { op1(e, "xra", "b") },
+	0xa9: func(e rcs.StmtEval) { op1(e, "xra", "c") },
+	0xaa: func(e rcs.StmtEval) { op1(e, "xra", "d") },
+	0xab: func(e rcs.StmtEval) { op1(e, "xra", "e") },
+	0xac: func(e rcs.StmtEval) { op1(e, "xra", "h") },
+	0xad: func(e rcs.StmtEval) { op1(e, "xra", "l") },
+	0xae: func(e rcs.StmtEval) { op1(e, "xra", "m") },
+	0xaf: func(e rcs.StmtEval) { op1(e, "xra", "a") },
+	0xb0: func(e rcs.StmtEval) { op1(e, "ora", "b") },
+	0xb1: func(e rcs.StmtEval) { op1(e, "ora", "c") },
+	0xb2: func(e rcs.StmtEval) { op1(e, "ora", "d") },
+	0xb3: func(e rcs.StmtEval) { op1(e, "ora", "e") },
+	0xb4: func(e rcs.StmtEval) { op1(e, "ora", "h") },
+	0xb5: func(e rcs.StmtEval) { op1(e, "ora", "l") },
+	0xb6: func(e rcs.StmtEval) { op1(e, "ora", "m") },
+	0xb7: func(e rcs.StmtEval) { op1(e, "ora", "a") },
+	0xb8: func(e rcs.StmtEval) { op1(e, "cmp", "b") },
+	0xb9: func(e rcs.StmtEval) { op1(e, "cmp", "c") },
+	0xba: func(e rcs.StmtEval) { op1(e, "cmp", "d") },
+	0xbb: func(e rcs.StmtEval) { op1(e, "cmp", "e") },
+	0xbc: func(e rcs.StmtEval) { op1(e, "cmp", "h") },
+	0xbd: func(e rcs.StmtEval) { op1(e, "cmp", "l") },
+	0xbe: func(e rcs.StmtEval) { op1(e, "cmp", "m") },
+	0xbf: func(e rcs.StmtEval) { op1(e, "cmp", "a") },
+	0xc0: func(e rcs.StmtEval) { op1(e, "rnz") },
+	0xc1: func(e rcs.StmtEval) { op1(e, "pop", "b") },
+	0xc2: func(e rcs.StmtEval) { op1(e, "jnz", "&0000") },
+	0xc3: func(e rcs.StmtEval) { op1(e, "jmp", "&0000") },
+	0xc4: func(e rcs.StmtEval) { op1(e, "cnz", "&0000") },
+	0xc5: func(e rcs.StmtEval) { op1(e, "push", "b") },
+	0xc6: func(e rcs.StmtEval) { op1(e, "adi", "&00") },
+	0xc7: func(e rcs.StmtEval) { opRst8080(e, 0) },
+	0xc8: func(e rcs.StmtEval) { op1(e, "rz") },
+	0xc9: func(e rcs.StmtEval) { op1(e, "ret") },
+	0xca: func(e rcs.StmtEval) { op1(e, "jz", "&0000") },
+	0xcb: func(e rcs.StmtEval) { op1(e, "jmp", "&0000") },
+	0xcc: func(e rcs.StmtEval) { op1(e, "cz", "&0000") },
+	0xcd: func(e rcs.StmtEval) { op1(e, "call", "&0000") },
+	0xce: func(e rcs.StmtEval) { op1(e, "aci", "&00") },
+	0xcf: func(e rcs.StmtEval) { opRst8080(e, 1) },
+	0xd0: func(e rcs.StmtEval) { op1(e, "rnc") },
+	0xd1: func(e rcs.StmtEval) { op1(e, "pop", "d") },
+	0xd2: func(e rcs.StmtEval) { op1(e, "jnc", "&0000") },
+	0xd3: func(e rcs.StmtEval) { op1(e, "out", "&00") },
+	0xd4: func(e rcs.StmtEval) { op1(e, "cnc", "&0000") },
+	0xd5: func(e rcs.StmtEval) { op1(e, "push", "d") },
+	0xd6: func(e rcs.StmtEval) { op1(e, "sui", "&00") },
+	0xd7: func(e rcs.StmtEval) { opRst8080(e, 2) },
+	0xd8: func(e rcs.StmtEval) { op1(e, "rc") },
+	0xd9: func(e rcs.StmtEval) { op1(e, "ret") },
+	0xda: func(e rcs.StmtEval) { op1(e, "jc", "&0000") },
+	0xdb: func(e rcs.StmtEval) { op1(e, "in", "&00") },
+	0xdc: func(e rcs.StmtEval) { op1(e, "cc", "&0000") },
+	0xdd: func(e rcs.StmtEval) { op1(e, "call", "&0000") },
+	0xde: func(e rcs.StmtEval) { op1(e, "sbi", "&00") },
+	0xdf: func(e rcs.StmtEval) { opRst8080(e, 3) },
+	0xe0: func(e rcs.StmtEval) { op1(e, "rpo") },
+	0xe1: func(e rcs.StmtEval) { op1(e, "pop", "h") },
+	0xe2: func(e rcs.StmtEval) { op1(e, "jpo", "&0000") },
+	0xe3: func(e rcs.StmtEval) { op1(e, "xthl") },
+	0xe4: func(e rcs.StmtEval) { op1(e, "cpo", "&0000") },
+	0xe5: func(e rcs.StmtEval) { op1(e, "push", "h") },
+	0xe6: func(e rcs.StmtEval) { op1(e, "ani", "&00") },
+	0xe7: func(e rcs.StmtEval) { opRst8080(e, 4) },
+	0xe8: func(e rcs.StmtEval) { op1(e, "rpe") },
+	0xe9: func(e rcs.StmtEval) { op1(e, "pchl") },
+	0xea: func(e rcs.StmtEval) { op1(e, "jpe", "&0000") },
+	0xeb: func(e rcs.StmtEval) { op1(e, "xchg") },
+	0xec: func(e rcs.StmtEval) { op1(e, "cpe", "&0000") },
+	0xed: func(e rcs.StmtEval) { op1(e, "call", "&0000") },
+	0xee: func(e rcs.StmtEval) { op1(e, "xri", "&00") },
+	0xef: func(e rcs.StmtEval) { opRst8080(e, 5) },
+	0xf0: func(e rcs.StmtEval) { op1(e, "rp") },
+	0xf1: func(e rcs.StmtEval) { op1(e, "pop", "psw") },
+	0xf2: func(e rcs.StmtEval) { op1(e, "jp", "&0000") },
+	0xf3: func(e rcs.StmtEval) { op1(e, "di") },
+	0xf4: func(e rcs.StmtEval) { op1(e, "cp", "&0000") },
+	0xf5: func(e rcs.StmtEval) { op1(e, "push", "psw") },
+	0xf6: func(e rcs.StmtEval) { op1(e, "ori", "&00") },
+	0xf7: func(e rcs.StmtEval) { opRst8080(e, 6) },
+	0xf8: func(e rcs.StmtEval) { op1(e, "rm") },
+	0xf9: func(e rcs.StmtEval) { op1(e, "sphl") },
+	0xfa: func(e rcs.StmtEval) { op1(e, "jm", "&0000") },
+	0xfb: func(e rcs.StmtEval) { op1(e, "ei") },
+	0xfc: func(e rcs.StmtEval) { op1(e, "cm", "&0000") },
+	0xfd: func(e rcs.StmtEval) { op1(e, "call", "&0000") },
+	0xfe: func(e rcs.StmtEval) { op1(e, "cpi", "&00") },
+	0xff: func(e rcs.StmtEval) { opRst8080(e, 7) },
+}
@@ -7,12 +7,23 @@ import (
 	"github.com/blackchip-org/retro-cs/rcs/cbm"
 	"github.com/blackchip-org/retro-cs/rcs/cbm/petscii"
 	"github.com/blackchip-org/retro-cs/rcs/m6502"
+	"github.com/blackchip-org/retro-cs/rcs/z80"
+)
+
+// Sources for the IRQ line on the 8502
+const (
+	irqVBlank = iota
 )
 
 type System struct {
+	mach   *rcs.Mach
 	cpu    *m6502.CPU
+	z80    *z80.CPU
 	mem    *rcs.Memory
+	z80mem *rcs.Memory
 	mmu    *MMU
+	kbd    *Keyboard
+	fast   uint8 // $d030, 2MHz mode when bit 0 is set
 	screen rcs.Screen
 	vdc    *VDC
 	vic    *cbm.VIC
@@ -21,6 +32,7 @@ type System struct {
 	BasicHi []uint8
 	CharGen []uint8
 	Kernal  []uint8
+	Z80BIOS []uint8
 	RAM0    []uint8
 	RAM1    []uint8
 	IORAM   []uint8
@@ -34,18 +46,31 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 		return nil, err
 	}
 	s.mem = rcs.NewMemory(256, 0x10000)
+	s.z80mem = rcs.NewMemory(256, 0x10000)
+	s.z80mem.Name = "z80mem"
 	s.BasicLo = roms["basiclo"]
 	s.BasicHi = roms["basichi"]
 	s.CharGen = roms["chargen"]
 	s.Kernal = roms["kernal"]
+	s.Z80BIOS = s.Kernal[0x1000:0x2000]
 	s.RAM0 = make([]uint8, 0x10000, 0x10000)
 	s.RAM1 = make([]uint8, 0xc000, 0xc000)
 
 	s.IORAM = make([]uint8, 0x1000, 0x1000)
 	s.IO = rcs.NewMemory(1, 0x1000)
+	s.IO.Name = "io"
 	s.IO.MapRAM(0, s.IORAM)
 
-	s.mmu = NewMMU(s.mem)
+	for _, mem := range []*rcs.Memory{s.mem, s.z80mem} {
+		mem.Label("ram0", s.RAM0)
+		mem.Label("ram1", s.RAM1)
+		for _, name := range []string{"basiclo", "basichi", "chargen", "kernal"} {
+			mem.Label(name, roms[name])
+		}
+	}
+
+	s.mmu = NewMMU(s.mem, s.z80mem)
+	s.kbd = NewKeyboard()
 	s.vdc = NewVDC()
 	v, err := cbm.NewVIC(ctx.Renderer, s.mem, roms["chargen"])
 	if err != nil {
@@ -60,9 +85,22 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 		Draw:      v.Draw,
 	}
 
+	s.cpu = m6502.New(s.mem)
+	s.z80 = z80.New(s.z80mem)
+	s.z80.Ports = rcs.NewMemory(1, 0x10000)
+
 	// IO mappings
+	s.IO.MapLoad(0x011, func() uint8 {
+		// bit 7 is the high bit of the raster line
+		return s.IORAM[0x011]&0x7f | uint8(s.vic.Raster>>8)<<7
+	})
+	s.IO.MapLoad(0x012, s.vic.ReadRaster)
 	s.IO.MapRW(0x020, &s.vic.BorderColor)
 	s.IO.MapRW(0x021, &s.vic.BgColor)
+	s.IO.MapLoad(0x02f, s.kbd.ReadExtSel)
+	s.IO.MapStore(0x02f, s.kbd.WriteExtSel)
+	s.IO.MapLoad(0x030, func() uint8 { return s.fast | 0xfc })
+	s.IO.MapStore(0x030, s.writeFast)
 	s.IO.MapLoad(0x500, s.mmu.ReadCR)
 	s.IO.MapStore(0x500, s.mmu.WriteCR)
 	// PCR
@@ -71,105 +109,73 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 		s.IO.MapLoad(0x501+i, func() uint8 { return s.mmu.ReadPCR(i) })
 		s.IO.MapStore(0x501+i, func(v uint8) { s.mmu.WritePCR(i, v) })
 	}
-	// HACK
 	s.IO.MapLoad(0x505, func() uint8 {
-		return (1 << 7) | (1 << 4) | (1 << 5)
+		// no cartridge
+		v := s.mmu.ReadMode() | ModeGame | ModeExROM
+		if !s.kbd.Key4080 {
+			v |= Mode4080
+		}
+		return v
+	})
+	s.IO.MapStore(0x505, func(v uint8) {
+		s.mmu.WriteMode(v)
+		s.selectCPU()
 	})
 	// HACK
 	s.IO.MapLoad(0xd00, func() uint8 {
+		// Reading the interrupt control register on CIA1 acknowledges
+		// the interrupt
+		s.cpu.SetIRQ(irqVBlank, false)
 		return (1 << 7) | (1 << 6)
 	})
+	// Writing the interrupt register on the VIC acknowledges the
+	// interrupt
+	s.IO.MapStore(0x019, func(v uint8) {
+		s.cpu.SetIRQ(irqVBlank, false)
+	})
 
 	s.IO.MapLoad(0x600, s.vdc.ReadStatus)
 	s.IO.MapStore(0x600, s.vdc.WriteAddr)
 	s.IO.MapLoad(0x601, s.vdc.ReadData)
 	s.IO.MapStore(0x601, s.vdc.WriteData)
+	s.IO.MapLoad(0xc00, s.kbd.ReadColSel)
+	s.IO.MapStore(0xc00, s.kbd.WriteColSel)
+	s.IO.MapLoad(0xc01, s.kbd.ReadRows)
+
+	// The Z80 accesses the chips with I/O ports found at the same
+	// addresses as the memory mapped I/O.
+	s.z80.Ports.Map(0xd000, s.IO)
 
 	// map banks
 	for _, i := range usedBanks {
 		s.mem.SetBank(i)
-		cr := uint8(i)
-		blockRAM := rcs.SliceBits(cr, 6, 7)
-		blockC000 := rcs.SliceBits(cr, 4, 5)
-		block8000 := rcs.SliceBits(cr, 2, 3)
-		block4000 := rcs.SliceBits(cr, 1, 1)
-		blockIO := rcs.SliceBits(cr, 0, 0)
-
-		switch blockRAM {
-		case 0:
-			s.mem.MapRAM(0x0000, s.RAM0)
-		case 1:
-			s.mem.MapRAM(0x0000, s.RAM0)
-			s.mem.MapRAM(0x0400, s.RAM1)
-		// no block RAM 2 or 3. If set, accesses 0 and 1 instead.
-		case 2:
-			s.mem.MapRAM(0x0000, s.RAM0)
-		case 3:
-			s.mem.MapRAM(0x0000, s.RAM0)
-			s.mem.MapRAM(0x0400, s.RAM1)
-		}
-
-		switch blockC000 {
-		case 0:
-			s.mem.MapROM(0xd000, s.CharGen)
-			s.mem.MapROM(0xc000, s.Kernal)
-		case 1:
-			// internal function ROM
-		case 2:
-			// external function ROM
-		case 3:
-			// RAM
-		}
-
-		switch block8000 {
-		case 0:
-			s.mem.MapROM(0x8000, s.BasicHi)
-		case 1:
-			// internal function ROM
-		case 2:
-			// external function ROM
-		case 3:
-			// RAM
-		}
-
-		switch block4000 {
-		case 0:
-			s.mem.MapROM(0x4000, s.BasicLo)
-		case 1:
-			// RAM
-		}
-
-		switch blockIO {
-		case 0:
-			s.mem.Map(0xd000, s.IO)
-		case 1:
-			// RAM or ROM as selected by bits 4 and 5
-		}
-
-		s.mem.MapLoad(0xff00, s.mmu.ReadCR)
-		s.mem.MapStore(0xff00, s.mmu.WriteCR)
-		for i := 0; i < 4; i++ {
-			i := i
-			s.mem.MapLoad(0xff01+i, func() uint8 { return s.mmu.ReadLCR(i) })
-			s.mem.MapStore(0xff01+i, func(v uint8) { s.mmu.WriteLCR(i, v) })
+		s.z80mem.SetBank(i)
+		s.mapBank(s.mem, uint8(i))
+		s.mapBank(s.z80mem, uint8(i))
+		// The Z80 BIOS is in the kernal ROM hidden behind the I/O area.
+		// It appears at the bottom of memory for the Z80 when the system
+		// ROM is selected.
+		if rcs.SliceBits(uint8(i), 4, 5) == 0 {
+			s.z80mem.MapROM(0x0000, s.Z80BIOS)
 		}
+		// The CAPS LOCK key is sensed on the 8502 processor port
+		s.mem.MapLoad(0x0001, s.readPort)
 	}
 	s.mem.SetBank(0) // bank 15
+	s.z80mem.SetBank(0)
 
-	s.mem.Write(0xd011, 0xff) // HACK
-	s.mem.Write(0xd011, (1 << 7))
-	s.mem.Write(0xd012, 0x09) // HACK: bcc on $8
 	s.mem.Write(0xd600, 0xff) // HACK
-	s.mem.Write(0xdc01, 0xff) // HACK: keyboard no press
 
-	s.cpu = m6502.New(s.mem)
 	mach := &rcs.Mach{
 		Sys: s,
 		Comps: []rcs.Component{
 			rcs.NewComponent("cpu", "m6502", "mem", s.cpu),
+			rcs.NewComponent("z80", "z80", "z80mem", s.z80),
 			rcs.NewComponent("mem", "mem", "", s.mem),
+			rcs.NewComponent("z80mem", "mem", "", s.z80mem),
 			rcs.NewComponent("mmu", "c128/mmu", "", s.mmu),
 			rcs.NewComponent("vdc", "c128/vdc", "", s.vdc),
+			rcs.NewComponent("vic", "cbm/vic", "", s.vic),
 		},
 		CharDecoders: map[string]rcs.CharDecoder{
 			"petscii":         petscii.Decoder,
@@ -180,13 +186,128 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 		DefaultEncoding: "petscii",
 		Ctx:             ctx,
 		VBlankFunc: func() {
-			s.cpu.IRQ = true
+			if s.mmu.Mode&ModeCPU != 0 {
+				s.cpu.SetIRQ(irqVBlank, true)
+			} else {
+				s.z80.IRQ = true
+			}
 		},
-		Screen: s.screen,
+		Screen:   s.screen,
+		Keyboard: s.kbd.handle,
 	}
+	s.mach = mach
+
+	// The real machine powers up with the Z80 which runs the BIOS boot
+	// code and then hands the bus over to the 8502. That boot path is not
+	// emulated: the machine starts with the 8502 and the Z80 only runs
+	// when selected with the mode configuration register. Booting into
+	// CP/M is not supported since there is no disk drive to load a disk
+	// image from.
+	s.mmu.Mode = ModeCPU
+	s.selectCPU()
 	return mach, nil
 }
 
+// selectCPU suspends the processor that does not have the bus as
+// selected by the mode configuration register.
+func (s *System) selectCPU() {
+	if s.mmu.Mode&ModeCPU != 0 {
+		s.mach.Suspend("z80")
+		s.mach.Resume("cpu")
+	} else {
+		s.mach.Suspend("cpu")
+		s.mach.Resume("z80")
+	}
+}
+
+func (s *System) readPort() uint8 {
+	v := s.RAM0[0x0001]
+	if s.kbd.CapsLock {
+		return v &^ (1 << 6)
+	}
+	return v | (1 << 6)
+}
+
+// writeFast sets the 8502 to run at 2MHz when bit 0 is set. The VIC
+// cannot keep up at this speed and its display is blanked.
+func (s *System) writeFast(v uint8) {
+	s.fast = v
+	if v&(1<<0) != 0 {
+		s.mach.SetSpeed("cpu", 2)
+		s.vic.Blank = true
+	} else {
+		s.mach.SetSpeed("cpu", 1)
+		s.vic.Blank = false
+	}
+}
+
+func (s *System) mapBank(mem *rcs.Memory, cr uint8) {
+	blockRAM := rcs.SliceBits(cr, 6, 7)
+	blockC000 := rcs.SliceBits(cr, 4, 5)
+	block8000 := rcs.SliceBits(cr, 2, 3)
+	block4000 := rcs.SliceBits(cr, 1, 1)
+	blockIO := rcs.SliceBits(cr, 0, 0)
+
+	switch blockRAM {
+	case 0:
+		mem.MapRAM(0x0000, s.RAM0)
+	case 1:
+		mem.MapRAM(0x0000, s.RAM0)
+		mem.MapRAM(0x0400, s.RAM1)
+	// no block RAM 2 or 3. If set, accesses 0 and 1 instead.
+	case 2:
+		mem.MapRAM(0x0000, s.RAM0)
+	case 3:
+		mem.MapRAM(0x0000, s.RAM0)
+		mem.MapRAM(0x0400, s.RAM1)
+	}
+
+	switch blockC000 {
+	case 0:
+		mem.MapROM(0xd000, s.CharGen)
+		mem.MapROM(0xc000, s.Kernal)
+	case 1:
+		// internal function ROM
+	case 2:
+		// external function ROM
+	case 3:
+		// RAM
+	}
+
+	switch block8000 {
+	case 0:
+		mem.MapROM(0x8000, s.BasicHi)
+	case 1:
+		// internal function ROM
+	case 2:
+		// external function ROM
+	case 3:
+		// RAM
+	}
+
+	switch block4000 {
+	case 0:
+		mem.MapROM(0x4000, s.BasicLo)
+	case 1:
+		// RAM
+	}
+
+	switch blockIO {
+	case 0:
+		mem.Map(0xd000, s.IO)
+	case 1:
+		// RAM or ROM as selected by bits 4 and 5
+	}
+
+	mem.MapLoad(0xff00, s.mmu.ReadCR)
+	mem.MapStore(0xff00, s.mmu.WriteCR)
+	for i := 0; i < 4; i++ {
+		i := i
+		mem.MapLoad(0xff01+i, func() uint8 { return s.mmu.ReadLCR(i) })
+		mem.MapStore(0xff01+i, func(v uint8) { s.mmu.WriteLCR(i, v) })
+	}
+}
+
 var usedBanks = []int{
 	0x3f, // bank 0
 	0x7f, // bank 1
@@ -0,0 +1,190 @@
+package c128
+
+import (
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Keyboard is the key matrix scanned by the KERNAL. A column is selected
+// by clearing a bit in CIA 1 port A ($dc00) for the standard C64 rows or
+// by clearing a bit in the VIC extended keyboard register ($d02f) for the
+// three additional rows found on the C128. Pressed keys in the selected
+// columns then read as clear bits in CIA 1 port B ($dc01).
+//
+// The CAPS LOCK and 40/80 DISPLAY keys are not in the matrix. They latch
+// down when pressed and are sensed on the processor port and the MMU
+// mode register.
+type Keyboard struct {
+	ColSel   uint8 // column select, CIA 1 port A
+	ExtSel   uint8 // extended column select, VIC $d02f
+	CapsLock bool  // CAPS LOCK (ASCII/DIN) key is down
+	Key4080  bool  // 40/80 DISPLAY key is down
+
+	matrix [11]uint8 // set bits are pressed keys
+}
+
+type keyPos struct {
+	col int
+	row uint
+}
+
+func NewKeyboard() *Keyboard {
+	return &Keyboard{
+		ColSel: 0xff,
+		ExtSel: 0xff,
+	}
+}
+
+// ReadRows returns the rows of the matrix for all selected columns.
+func (k *Keyboard) ReadRows() uint8 {
+	v := uint8(0xff)
+	for col := 0; col < 8; col++ {
+		if k.ColSel&(1<<uint(col)) == 0 {
+			v &^= k.matrix[col]
+		}
+	}
+	for col := 0; col < 3; col++ {
+		if k.ExtSel&(1<<uint(col)) == 0 {
+			v &^= k.matrix[8+col]
+		}
+	}
+	return v
+}
+
+func (k *Keyboard) ReadColSel() uint8   { return k.ColSel }
+func (k *Keyboard) WriteColSel(v uint8) { k.ColSel = v }
+func (k *Keyboard) ReadExtSel() uint8   { return k.ExtSel | 0xf8 }
+func (k *Keyboard) WriteExtSel(v uint8) { k.ExtSel = v }
+
+func (k *Keyboard) handle(e *sdl.KeyboardEvent) error {
+	sym := e.Keysym.Sym
+	if e.Type == sdl.KEYDOWN {
+		switch sym {
+		case sdl.K_CAPSLOCK:
+			k.CapsLock = !k.CapsLock
+			return nil
+		case keyDisplay4080:
+			k.Key4080 = !k.Key4080
+			return nil
+		}
+	}
+	pos, ok := keyMatrix[sym]
+	if !ok {
+		return nil
+	}
+	if e.Type == sdl.KEYDOWN {
+		k.matrix[pos.col] |= 1 << pos.row
+	} else {
+		k.matrix[pos.col] &^= 1 << pos.row
+	}
+	return nil
+}
+
+// Keys that do not have an obvious match on a modern keyboard
+const (
+	keyDisplay4080 = sdl.K_F10
+	keyHelp        = sdl.K_F9
+	keyEsc         = sdl.K_F12 // escape is used to quit
+	keyLineFeed    = sdl.K_INSERT
+	keyRunStop     = sdl.K_PAUSE
+	keyCommodore   = sdl.K_LGUI
+)
+
+// Positional mapping of the host keyboard to the C128 matrix.
+var keyMatrix = map[sdl.Keycode]keyPos{
+	sdl.K_BACKSPACE: {0, 0}, // INST/DEL
+	sdl.K_RETURN:    {0, 1},
+	sdl.K_F7:        {0, 3},
+	sdl.K_F1:        {0, 4},
+	sdl.K_F3:        {0, 5},
+	sdl.K_F5:        {0, 6},
+
+	sdl.K_3:      {1, 0},
+	sdl.K_w:      {1, 1},
+	sdl.K_a:      {1, 2},
+	sdl.K_4:      {1, 3},
+	sdl.K_z:      {1, 4},
+	sdl.K_s:      {1, 5},
+	sdl.K_e:      {1, 6},
+	sdl.K_LSHIFT: {1, 7},
+
+	sdl.K_5: {2, 0},
+	sdl.K_r: {2, 1},
+	sdl.K_d: {2, 2},
+	sdl.K_6: {2, 3},
+	sdl.K_c: {2, 4},
+	sdl.K_f: {2, 5},
+	sdl.K_t: {2, 6},
+	sdl.K_x: {2, 7},
+
+	sdl.K_7: {3, 0},
+	sdl.K_y: {3, 1},
+	sdl.K_g: {3, 2},
+	sdl.K_8: {3, 3},
+	sdl.K_b: {3, 4},
+	sdl.K_h: {3, 5},
+	sdl.K_u: {3, 6},
+	sdl.K_v: {3, 7},
+
+	sdl.K_9: {4, 0},
+	sdl.K_i: {4, 1},
+	sdl.K_j: {4, 2},
+	sdl.K_0: {4, 3},
+	sdl.K_m: {4, 4},
+	sdl.K_k: {4, 5},
+	sdl.K_o: {4, 6},
+	sdl.K_n: {4, 7},
+
+	sdl.K_EQUALS:      {5, 0}, // +
+	sdl.K_p:           {5, 1},
+	sdl.K_l:           {5, 2},
+	sdl.K_MINUS:       {5, 3},
+	sdl.K_PERIOD:      {5, 4},
+	sdl.K_SEMICOLON:   {5, 5}, // :
+	sdl.K_LEFTBRACKET: {5, 6}, // @
+	sdl.K_COMMA:       {5, 7},
+
+	sdl.K_BACKSLASH:    {6, 0}, // british pound
+	sdl.K_RIGHTBRACKET: {6, 1}, // *
+	sdl.K_QUOTE:        {6, 2}, // ;
+	sdl.K_HOME:         {6, 3}, // CLR/HOME
+	sdl.K_RSHIFT:       {6, 4},
+	sdl.K_END:          {6, 5}, // =
+	sdl.K_PAGEUP:       {6, 6}, // up arrow
+	sdl.K_SLASH:        {6, 7},
+
+	sdl.K_1:         {7, 0},
+	sdl.K_BACKQUOTE: {7, 1}, // left arrow
+	sdl.K_LCTRL:     {7, 2},
+	sdl.K_2:         {7, 3},
+	sdl.K_SPACE:     {7, 4},
+	keyCommodore:    {7, 5},
+	sdl.K_q:         {7, 6},
+	keyRunStop:      {7, 7},
+
+	keyHelp:    {8, 0},
+	sdl.K_KP_8: {8, 1},
+	sdl.K_KP_5: {8, 2},
+	sdl.K_TAB:  {8, 3},
+	sdl.K_KP_2: {8, 4},
+	sdl.K_KP_4: {8, 5},
+	sdl.K_KP_7: {8, 6},
+	sdl.K_KP_1: {8, 7},
+
+	keyEsc:         {9, 0},
+	sdl.K_KP_PLUS:  {9, 1},
+	sdl.K_KP_MINUS: {9, 2},
+	keyLineFeed:    {9, 3},
+	sdl.K_KP_ENTER: {9, 4},
+	sdl.K_KP_6:     {9, 5},
+	sdl.K_KP_9:     {9, 6},
+	sdl.K_KP_3:     {9, 7},
+
+	sdl.K_LALT:       {10, 0},
+	sdl.K_KP_0:       {10, 1},
+	sdl.K_KP_PERIOD:  {10, 2},
+	sdl.K_UP:         {10, 3},
+	sdl.K_DOWN:       {10, 4},
+	sdl.K_LEFT:       {10, 5},
+	sdl.K_RIGHT:      {10, 6},
+	sdl.K_SCROLLLOCK: {10, 7}, // NO SCROLL
+}
@@ -0,0 +1,63 @@
+package c128
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func key(typ uint32, sym sdl.Keycode) *sdl.KeyboardEvent {
+	return &sdl.KeyboardEvent{
+		Type:   typ,
+		Keysym: sdl.Keysym{Sym: sym},
+	}
+}
+
+func TestKeyboardMatrix(t *testing.T) {
+	var tests = []struct {
+		name   string
+		sym    sdl.Keycode
+		colSel uint8
+		extSel uint8
+		want   uint8
+	}{
+		{"a", sdl.K_a, 0xfd, 0xff, 0xfb},
+		{"a other column", sdl.K_a, 0xfe, 0xff, 0xff},
+		{"a all columns", sdl.K_a, 0x00, 0xff, 0xfb},
+		{"return", sdl.K_RETURN, 0xfe, 0xff, 0xfd},
+		{"keypad 1", sdl.K_KP_1, 0xff, 0xfe, 0x7f},
+		{"keypad enter", sdl.K_KP_ENTER, 0xff, 0xfd, 0xef},
+		{"cursor up", sdl.K_UP, 0xff, 0xfb, 0xf7},
+		{"cursor up not selected", sdl.K_UP, 0x00, 0xff, 0xff},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			k := NewKeyboard()
+			k.handle(key(sdl.KEYDOWN, test.sym))
+			k.WriteColSel(test.colSel)
+			k.WriteExtSel(test.extSel)
+			have := k.ReadRows()
+			if have != test.want {
+				t.Errorf("\n have: %08b \n want: %08b", have, test.want)
+			}
+			k.handle(key(sdl.KEYUP, test.sym))
+			have = k.ReadRows()
+			if have != 0xff {
+				t.Errorf("\n have: %08b \n want: %08b", have, 0xff)
+			}
+		})
+	}
+}
+
+func TestKeyboardLatch(t *testing.T) {
+	k := NewKeyboard()
+	k.handle(key(sdl.KEYDOWN, sdl.K_CAPSLOCK))
+	k.handle(key(sdl.KEYUP, sdl.K_CAPSLOCK))
+	if !k.CapsLock {
+		t.Errorf("expected caps lock to be down")
+	}
+	k.handle(key(sdl.KEYDOWN, sdl.K_CAPSLOCK))
+	if k.CapsLock {
+		t.Errorf("expected caps lock to be up")
+	}
+}
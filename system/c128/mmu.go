@@ -8,22 +8,45 @@ import (
 
 var mmuRegs = []string{"A", "B", "C", "D"}
 
+// Mode configuration register bits
+const (
+	// ModeCPU selects the 8502 when set and the Z80 when clear
+	ModeCPU = uint8(1 << 0)
+
+	// ModeFSDIR is the fast serial direction
+	ModeFSDIR = uint8(1 << 3)
+
+	// ModeGame is the cartridge /GAME line
+	ModeGame = uint8(1 << 4)
+
+	// ModeExROM is the cartridge /EXROM line
+	ModeExROM = uint8(1 << 5)
+
+	// ModeC64 is set when in C64 mode
+	ModeC64 = uint8(1 << 6)
+
+	// Mode4080 is the 40/80 DISPLAY key sense, clear when pressed down
+	Mode4080 = uint8(1 << 7)
+)
+
 // MMU is the memory management unit
 type MMU struct {
-	Mem *rcs.Memory // configuration register is the bank number
-	LCR [4]uint8    // load configuration register
-	PCR [4]uint8    // pre-configuration register
-	// FIXME: What is this for?
-	// Mode uint8
-
-	WatchCR  rcs.FlagRW
-	WatchLCR rcs.FlagRW
-	WatchPCR rcs.FlagRW
+	Mem    *rcs.Memory // configuration register is the bank number
+	Z80Mem *rcs.Memory // same configuration as seen by the Z80
+	LCR    [4]uint8    // load configuration register
+	PCR    [4]uint8    // pre-configuration register
+	Mode   uint8       // mode configuration register
+
+	WatchCR   rcs.FlagRW
+	WatchLCR  rcs.FlagRW
+	WatchPCR  rcs.FlagRW
+	WatchMode rcs.FlagRW
 }
 
-func NewMMU(mem *rcs.Memory) *MMU {
+func NewMMU(mem *rcs.Memory, z80mem *rcs.Memory) *MMU {
 	return &MMU{
-		Mem: mem,
+		Mem:    mem,
+		Z80Mem: z80mem,
 	}
 }
 
@@ -40,6 +63,7 @@ func (m *MMU) WriteCR(v uint8) {
 		log.Printf("mmu:cr <= 0x%02x", v)
 	}
 	m.Mem.SetBank(int(v))
+	m.Z80Mem.SetBank(int(v))
 }
 
 func (m *MMU) ReadLCR(i int) uint8 {
@@ -73,3 +97,18 @@ func (m *MMU) WritePCR(i int, v uint8) {
 	}
 	m.PCR[i] = v
 }
+
+func (m *MMU) ReadMode() uint8 {
+	v := m.Mode
+	if m.WatchMode.R {
+		log.Printf("0x%02x <= mmu:mode", v)
+	}
+	return v
+}
+
+func (m *MMU) WriteMode(v uint8) {
+	if m.WatchMode.W {
+		log.Printf("mmu:mode <= 0x%02x", v)
+	}
+	m.Mode = v
+}
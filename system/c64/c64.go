@@ -9,6 +9,16 @@ import (
 	"github.com/blackchip-org/retro-cs/rcs/m6502"
 )
 
+// Sources for the NMI line on the CPU
+const (
+	nmiRestore = iota
+)
+
+// Sources for the IRQ line on the CPU
+const (
+	irqCIA1 = iota
+)
+
 type system struct {
 	cpu    *m6502.CPU
 	mem    *rcs.Memory
@@ -58,6 +68,7 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 		s.mem.MapRAM(0x0277, kb.buf)
 
 		s.mem.MapRW(0xdc00, &kb.joy2)
+		s.mem.MapLoad(0xdc0d, s.icrLoad)
 	}
 	// Initialize to bank 31
 	s.mem.SetBank(31)
@@ -69,6 +80,9 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 	// CPU should be created after memory is completely setup to obtain
 	// the correct reset vector
 	s.cpu = m6502.New(s.mem)
+	kb.restore = func(pressed bool) {
+		s.cpu.SetNMI(nmiRestore, pressed)
+	}
 
 	mach := &rcs.Mach{
 		Sys: s,
@@ -86,7 +100,7 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 		DefaultEncoding: "petscii",
 		Ctx:             ctx,
 		VBlankFunc: func() {
-			s.cpu.IRQ = true
+			s.cpu.SetIRQ(irqCIA1, true)
 		},
 		Screen:   s.screen,
 		Keyboard: kb.handle,
@@ -95,6 +109,18 @@ func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
 	return mach, nil
 }
 
+// icrLoad reads the interrupt control register on CIA1. The timer that
+// drives the jiffy clock is not emulated and the interrupt is raised at
+// each vblank instead. Reading the register acknowledges the interrupt
+// and releases the line.
+func (s *system) icrLoad() uint8 {
+	if !s.cpu.IRQLine() {
+		return 0
+	}
+	s.cpu.SetIRQ(irqCIA1, false)
+	return 0x81
+}
+
 func (s *system) ioPortStore(v uint8) {
 	// PLA information is in the bottom 3 bits
 	s.bank &^= 0x7
@@ -8,10 +8,11 @@ import (
 const kbBufLen = 10 // length of keyboard buffer
 
 type keyboard struct {
-	buf   []uint8
-	ndx   uint8 // Number of characters in keyboard buffer
-	stkey uint8 // Was STOP Key Pressed?
-	joy2  uint8 // HACK: joystick 2, move elsewhere
+	buf     []uint8
+	ndx     uint8      // Number of characters in keyboard buffer
+	stkey   uint8      // Was STOP Key Pressed?
+	joy2    uint8      // HACK: joystick 2, move elsewhere
+	restore func(bool) // RESTORE key, wired to the NMI line
 }
 
 func newKeyboard() *keyboard {
@@ -193,6 +194,9 @@ func (k *keyboard) lookup(e *sdl.KeyboardEvent) (uint8, bool) {
 	case keysym.Sym == sdl.K_c && e.Type == sdl.KEYUP:
 		k.stkey = 0xff
 
+	case keysym.Sym == sdl.K_PAGEUP && k.restore != nil:
+		k.restore(e.Type == sdl.KEYDOWN)
+
 	case keysym.Sym == sdl.K_UP && e.Type == sdl.KEYDOWN:
 		k.joy2 &^= (1 << 0)
 	case keysym.Sym == sdl.K_DOWN && e.Type == sdl.KEYDOWN:
@@ -10,6 +10,7 @@ func newMemory(ram []uint8, io []uint8, roms map[string][]byte) *rcs.Memory {
 	chargen := roms["chargen"]
 
 	iomem := rcs.NewMemory(1, 0x1000)
+	iomem.Name = "io"
 	iomem.MapRAM(0, io)
 
 	var cartlo, carthi []uint8
@@ -20,6 +21,10 @@ func newMemory(ram []uint8, io []uint8, roms map[string][]byte) *rcs.Memory {
 	}
 
 	mem := rcs.NewMemory(32, 0x10000)
+	mem.Label("ram", ram)
+	for _, name := range []string{"basic", "kernal", "chargen", "cart"} {
+		mem.Label(name, roms[name])
+	}
 
 	// https://www.c64-wiki.com/wiki/Bank_Switching
 	mem.SetBank(31)
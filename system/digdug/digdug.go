@@ -0,0 +1,149 @@
+// Package digdug is the hardware cabinet for Dig Dug.
+package digdug
+
+import (
+	"path/filepath"
+
+	"github.com/blackchip-org/retro-cs/config"
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/blackchip-org/retro-cs/rcs/namco"
+)
+
+type System struct {
+	*namco.Board
+	ram   []uint8
+	n53xx *namco.N53XX
+	earom *earom
+
+	video *Video
+
+	dswA uint8
+	dswB uint8
+
+	DIP *rcs.DIPSwitches
+}
+
+func new(ctx rcs.SDLContext, set string) (*rcs.Mach, error) {
+	s := &System{}
+	roms, err := rcs.LoadROMs(config.DataDir, ROM[set])
+	if err != nil {
+		return nil, err
+	}
+
+	// construct the common memory first
+	mem := rcs.NewMemory(1, 0x10000)
+	ram := make([]uint8, 0x2000, 0x2000)
+
+	board, err := namco.NewBoard(mem, roms["waveforms"], ctx.AudioSpec)
+	if err != nil {
+		return nil, err
+	}
+	s.Board = board
+
+	s.n53xx = namco.NewN53XX(&s.dswA, &s.dswB)
+	s.N06XX.DeviceR[1] = s.n53xx.Read
+	s.N06XX.SelectR[1] = s.n53xx.Select
+
+	// Tile memory is the first 1K of RAM and the rest is work RAM and
+	// sprite attributes.
+	mem.MapRAM(0x8000, ram)
+
+	s.earom = newEAROM()
+	for i := 0; i < 0x40; i++ {
+		mem.MapLoad(0xb800+i, s.earom.read)
+		mem.MapStore(0xb800+i, s.earom.write(i))
+	}
+	mem.MapStore(0xb840, s.earom.ctrl)
+
+	var screen rcs.Screen
+	var video *Video
+	if ctx.Renderer != nil {
+		video, err = NewVideo(ctx.Renderer, roms)
+		if err != nil {
+			return nil, err
+		}
+		video.TileMemory = ram[0x0000:0x0400]
+		video.SpriteRAM = [3][]uint8{
+			ram[0x0b80:0x0c00],
+			ram[0x1380:0x1400],
+			ram[0x1b80:0x1c00],
+		}
+		for i := 0; i < 8; i++ {
+			mem.MapStore(0xa000+i, video.WriteLatch(i))
+		}
+		screen = rcs.Screen{
+			W:         namco.W,
+			H:         namco.H,
+			Texture:   video.Texture,
+			ScanLineV: true,
+			Draw:      video.Draw,
+		}
+	}
+
+	s.DIP = rcs.NewDIPSwitches(DIP[set], &s.dswA, &s.dswB)
+	if err := s.DIP.LoadFile(filepath.Join(config.DataDir, "dip")); err != nil {
+		return nil, err
+	}
+
+	s.InitCPUs([3][]uint8{roms["code1"], roms["code2"], roms["code3"]})
+	s.ram = ram
+	s.video = video
+
+	inputs := namco.NewInputs(s.N51XX, namco.JoyUp|namco.JoyRight|namco.JoyDown|namco.JoyLeft)
+
+	mach := &rcs.Mach{
+		Sys: s,
+		Comps: []rcs.Component{
+			rcs.NewComponent("digdug", "digdug", "", s),
+			rcs.NewComponent("dip", "dip", "", s.DIP),
+			rcs.NewComponent("mem1", "mem", "", s.Mem[0]),
+			rcs.NewComponent("mem2", "mem", "", s.Mem[1]),
+			rcs.NewComponent("mem3", "mem", "", s.Mem[2]),
+			rcs.NewComponent("cpu1", "z80", "mem1", s.CPU[0]),
+			rcs.NewComponent("cpu2", "z80", "mem2", s.CPU[1]),
+			rcs.NewComponent("cpu3", "z80", "mem3", s.CPU[2]),
+			rcs.NewComponent("n06xx", "n06xx", "", s.N06XX),
+			rcs.NewComponent("n51xx", "n51xx", "", s.N51XX),
+			rcs.NewComponent("n53xx", "n53xx", "", s.n53xx),
+			rcs.NewComponent("wsg", "wsg", "", s.WSG),
+		},
+		Ctx:    ctx,
+		Screen: screen,
+		NVRAM: &rcs.NVRAM{
+			Name:       "earom",
+			Mem:        s.earom.mem,
+			Ranges:     []rcs.NVRAMRange{{Addr: 0, Len: len(s.earom.data)}},
+			Persistent: true,
+		},
+		VBlankFunc:    s.VBlank,
+		QueueAudio:    s.WSG.Queue,
+		Keyboard:      inputs.Keyboard,
+		ButtonHandler: inputs.Button,
+	}
+	s.Attach(mach)
+	return mach, nil
+}
+
+func (s *System) Save(enc *rcs.Encoder) {
+	s.Board.Save(enc)
+	if s.video != nil {
+		s.video.Save(enc)
+	}
+	enc.Encode(s.ram)
+	enc.Encode(s.dswA)
+	enc.Encode(s.dswB)
+}
+
+func (s *System) Load(dec *rcs.Decoder) {
+	s.Board.Load(dec)
+	if s.video != nil {
+		s.video.Load(dec)
+	}
+	dec.Decode(&s.ram)
+	dec.Decode(&s.dswA)
+	dec.Decode(&s.dswB)
+}
+
+func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
+	return new(ctx, "digdug")
+}
@@ -0,0 +1,98 @@
+package digdug
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+// Switch banks, both are read through the 53XX
+const (
+	dswA = 0 // coin B, bonus life, lives
+	dswB = 1 // coin A, freeze, demo sounds, continue, cabinet, difficulty
+)
+
+// DIP contains the switch settings for each ROM set. The bonus life
+// values are those used with 1 to 3 lives. The game awards a different
+// set of bonuses when playing with 5 lives.
+var DIP = map[string][]rcs.DIPSetting{
+	"digdug": []rcs.DIPSetting{
+		{
+			Name: "coin-b", Bank: dswA, Mask: 0x07, Default: "1c1c",
+			Values: []rcs.DIPValue{
+				{Name: "1c7c", Bits: 0x00},
+				{Name: "1c1c", Bits: 0x01},
+				{Name: "1c3c", Bits: 0x02},
+				{Name: "2c1c", Bits: 0x03},
+				{Name: "1c6c", Bits: 0x04},
+				{Name: "2c3c", Bits: 0x05},
+				{Name: "1c2c", Bits: 0x06},
+				{Name: "3c1c", Bits: 0x07},
+			},
+		},
+		{
+			Name: "bonus-life", Bank: dswA, Mask: 0x38, Default: "20k-60k",
+			Values: []rcs.DIPValue{
+				{Name: "none", Bits: 0x00},
+				{Name: "20k-70k-every", Bits: 0x08},
+				{Name: "10k-50k-every", Bits: 0x10},
+				{Name: "20k-60k", Bits: 0x18},
+				{Name: "10k-40k-every", Bits: 0x20},
+				{Name: "10k-40k", Bits: 0x28},
+				{Name: "20k-60k-every", Bits: 0x30},
+				{Name: "10k", Bits: 0x38},
+			},
+		},
+		{
+			Name: "lives", Bank: dswA, Mask: 0xc0, Default: "3",
+			Values: []rcs.DIPValue{
+				{Name: "1", Bits: 0x00},
+				{Name: "2", Bits: 0x40},
+				{Name: "3", Bits: 0x80},
+				{Name: "5", Bits: 0xc0},
+			},
+		},
+		{
+			Name: "difficulty", Bank: dswB, Mask: 0x03, Default: "easy",
+			Values: []rcs.DIPValue{
+				{Name: "easy", Bits: 0x00},
+				{Name: "hard", Bits: 0x01},
+				{Name: "medium", Bits: 0x02},
+				{Name: "hardest", Bits: 0x03},
+			},
+		},
+		{
+			Name: "cabinet", Bank: dswB, Mask: 0x04, Default: "upright",
+			Values: []rcs.DIPValue{
+				{Name: "cocktail", Bits: 0x00},
+				{Name: "upright", Bits: 0x04},
+			},
+		},
+		{
+			Name: "continue", Bank: dswB, Mask: 0x08, Default: "on",
+			Values: []rcs.DIPValue{
+				{Name: "on", Bits: 0x00},
+				{Name: "off", Bits: 0x08},
+			},
+		},
+		{
+			Name: "demo-sounds", Bank: dswB, Mask: 0x10, Default: "on",
+			Values: []rcs.DIPValue{
+				{Name: "on", Bits: 0x00},
+				{Name: "off", Bits: 0x10},
+			},
+		},
+		{
+			Name: "freeze", Bank: dswB, Mask: 0x20, Default: "off",
+			Values: []rcs.DIPValue{
+				{Name: "on", Bits: 0x00},
+				{Name: "off", Bits: 0x20},
+			},
+		},
+		{
+			Name: "coin-a", Bank: dswB, Mask: 0xc0, Default: "1c1c",
+			Values: []rcs.DIPValue{
+				{Name: "1c1c", Bits: 0x00},
+				{Name: "2c1c", Bits: 0x40},
+				{Name: "1c2c", Bits: 0x80},
+				{Name: "2c3c", Bits: 0xc0},
+			},
+		},
+	},
+}
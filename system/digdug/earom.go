@@ -0,0 +1,46 @@
+package digdug
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+// earom is the 64 byte electrically alterable ROM used to keep the high
+// scores when the power is off. An address and value are latched on a
+// write and the control register either loads the value at the address
+// into the latch or stores the latch at the address.
+type earom struct {
+	data  []uint8
+	addr  int
+	latch uint8
+	mem   *rcs.Memory // view of the data used to keep it between runs
+}
+
+func newEAROM() *earom {
+	e := &earom{data: make([]uint8, 0x40, 0x40)}
+	e.mem = rcs.NewMemory(1, len(e.data))
+	e.mem.MapRAM(0, e.data)
+	return e
+}
+
+func (e *earom) read() uint8 {
+	return e.latch
+}
+
+func (e *earom) write(addr int) rcs.Store8 {
+	return func(v uint8) {
+		e.addr = addr
+		e.latch = v
+	}
+}
+
+// Control bits:
+//
+//	bit 0: clock, load the value at the address into the latch
+//	bit 2: write mode
+//	bit 3: store the latch at the address when in write mode
+func (e *earom) ctrl(v uint8) {
+	if v&0x01 != 0 {
+		e.latch = e.data[e.addr]
+	}
+	if v&0x0c == 0x0c {
+		e.data[e.addr] = e.latch
+	}
+}
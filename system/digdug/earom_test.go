@@ -0,0 +1,20 @@
+package digdug
+
+import "testing"
+
+func TestEAROM(t *testing.T) {
+	e := newEAROM()
+	e.write(0x12)(0x34)
+	e.ctrl(0x0c)
+	e.ctrl(0x00)
+	if e.data[0x12] != 0x34 {
+		t.Errorf("\n have: %02x \n want: %02x", e.data[0x12], 0x34)
+	}
+
+	e.write(0x00)(0x00)
+	e.write(0x12)(0x00)
+	e.ctrl(0x01)
+	if have := e.read(); have != 0x34 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x34)
+	}
+}
@@ -0,0 +1,26 @@
+package digdug
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+var ROM = map[string][]rcs.ROM{
+	"digdug": []rcs.ROM{
+		rcs.NewROM("code1         ", "dd1a.1    ", "86689980410b9429cd7582c7a76342721c87d030"),
+		rcs.NewROM("code1         ", "dd1a.2    ", "fde17785df21956d6fd06bcfe675c392dadb1524"),
+		rcs.NewROM("code1         ", "dd1a.3    ", "57b8a5777f8bb9773caf0cafe5408c8b9768cb25"),
+		rcs.NewROM("code1         ", "dd1a.4    ", "b0a615fe4a5c8742c1e4ef234ef34c369d2723b9"),
+		rcs.NewROM("code2         ", "dd1a.5    ", "c16144de7633595ddc1450ddadf12e9f1e7b9d1c"),
+		rcs.NewROM("code2         ", "dd1a.6    ", "89e2ba7f0c0ac9c9bdd5e6c8e3ac1bf4f36a3a4e"),
+		rcs.NewROM("code3         ", "dd1.7     ", "2b9b74f56aa7939d9d47cf29497ae11f10d78598"),
+		rcs.NewROM("chars         ", "dd1.9     ", "0aa63300c2cb887196de590aceb98f3cf06fead4"),
+		rcs.NewROM("sprites       ", "dd1.15    ", "4700c63f4f680cb8ab8c44e6f3e1712aabd5daa4"),
+		rcs.NewROM("sprites       ", "dd1.14    ", "3e435c1afb2e44487cd7ba28a93ada2e5ccbb86d"),
+		rcs.NewROM("sprites       ", "dd1.13    ", "578bd839f9218c3cf4feee1223a461144e455df8"),
+		rcs.NewROM("sprites       ", "dd1.12    ", "bd173de1a8b8de0a6cb27ffe36d0fd1a0db9f2a9"),
+		rcs.NewROM("tiles         ", "dd1.11    ", "57f1e8f5171d0914bc2e8b3d4e7d2f5db6aae9cd"),
+		rcs.NewROM("playfield     ", "dd1.10b   ", "317c48818992f757b1bd0e3997fa99937f81b52c"),
+		rcs.NewROM("colors        ", "136007.113", "91a5852a15d4672c29fdcbae75921794651f960c"),
+		rcs.NewROM("spritepalettes", "136007.111", "7ea149e8eb08dbd9da1d69b1f1f9f1b6c7ea4f2d"),
+		rcs.NewROM("palettes      ", "136007.112", "a294cc4da846eb702d61678e8df8be8a9d5f7e36"),
+		rcs.NewROM("waveforms     ", "136007.110", "085ada18c498fdb18ecedef0ea8fe9217edb7b46"),
+	},
+}
@@ -0,0 +1,319 @@
+package digdug
+
+import (
+	"image/color"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+	"github.com/blackchip-org/retro-cs/rcs/namco"
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// Video latch registers at $a000-$a007. Only the low bit of each is used.
+const (
+	LatchBGSelect0 = 0 // playfield layout, bits 0-1
+	LatchBGSelect1 = 1
+	LatchTextColor = 2 // set to use the low nibble of a character for its color
+	LatchBGDisable = 3
+	LatchBGColor0  = 4 // playfield color bank, bits 0-1
+	LatchBGColor1  = 5
+	LatchFlip      = 7
+)
+
+// VideoConfig is used for the color PROMs, the playfield tiles, and the
+// sprites. The characters are 1 bit per pixel and use the colors
+// directly.
+var VideoConfig = namco.Config{
+	TileLayout: namco.SheetLayout{
+		TileW:        8,
+		TileH:        8,
+		TextureW:     8 * 16,
+		TextureH:     8 * 16,
+		PixelLayout:  tilePixels,
+		PixelReader:  pixelReader,
+		BytesPerCell: 16,
+	},
+	SpriteLayout: namco.SheetLayout{
+		TileW:        16,
+		TileH:        16,
+		TextureW:     16 * 16,
+		TextureH:     16 * 16,
+		PixelLayout:  spritePixels,
+		PixelReader:  pixelReader,
+		BytesPerCell: 64,
+	},
+	PaletteEntries: 64,
+	PaletteColors:  4,
+	Colors:         32,
+	ColorWeights:   colorWeights,
+	Transparent:    []int{0x1f},
+	TileColorBase:  0x00,
+}
+
+var CharLayout = namco.SheetLayout{
+	TileW:        8,
+	TileH:        8,
+	TextureW:     8 * 16,
+	TextureH:     8 * 16,
+	PixelLayout:  charPixels,
+	PixelReader:  charReader,
+	BytesPerCell: 8,
+}
+
+// Video has three layers. The playfield in the back is made from a
+// layout found in ROM, the characters are in the middle, and the sprites
+// are in the front.
+type Video struct {
+	TileMemory []uint8
+	SpriteRAM  [3][]uint8
+	Latch      [8]uint8
+
+	Texture   *sdl.Texture
+	playfield []uint8
+	chars     [16]rcs.TileSheet
+	tiles     [64]rcs.TileSheet
+	sprites   [64]rcs.TileSheet
+}
+
+func NewVideo(r *sdl.Renderer, roms map[string][]byte) (*Video, error) {
+	tex, err := r.CreateTexture(sdl.PIXELFORMAT_RGBA8888, sdl.TEXTUREACCESS_TARGET,
+		namco.W, namco.H)
+	if err != nil {
+		return nil, err
+	}
+	v := &Video{
+		Texture:    tex,
+		TileMemory: make([]uint8, 0x400, 0x400),
+		playfield:  roms["playfield"],
+	}
+
+	config := VideoConfig
+	colors := namco.ColorTable(config, roms["colors"])
+	tilePalettes := namco.PaletteTable(config, roms["palettes"], colors)
+	spritePalettes := namco.PaletteTable(config, roms["spritepalettes"], colors[0x10:])
+	for pal := 0; pal < config.PaletteEntries; pal++ {
+		t, err := namco.NewTileSheet(r, roms["tiles"], config.TileLayout, tilePalettes[pal])
+		if err != nil {
+			return nil, err
+		}
+		v.tiles[pal] = t
+		s, err := namco.NewTileSheet(r, roms["sprites"], config.SpriteLayout, spritePalettes[pal])
+		if err != nil {
+			return nil, err
+		}
+		v.sprites[pal] = s
+	}
+	for pal := range v.chars {
+		palette := []color.RGBA{color.RGBA{}, colors[pal]}
+		c, err := namco.NewTileSheet(r, roms["chars"], CharLayout, palette)
+		if err != nil {
+			return nil, err
+		}
+		v.chars[pal] = c
+	}
+	return v, nil
+}
+
+// WriteLatch returns a function that stores the low bit of a value to
+// the video latch.
+func (v *Video) WriteLatch(reg int) func(uint8) {
+	return func(val uint8) {
+		v.Latch[reg] = val & 1
+	}
+}
+
+func (v *Video) flipped() bool {
+	return v.Latch[LatchFlip] != 0
+}
+
+func (v *Video) Draw(r *sdl.Renderer) error {
+	r.SetRenderTarget(v.Texture)
+	r.SetDrawColorArray(0, 0, 0, 0xff)
+	r.Clear()
+	v.drawLayer(r, v.drawPlayfield)
+	v.drawLayer(r, v.drawChar)
+	namco.DrawSprites(r, v.sprites[:], VideoConfig.SpriteLayout, v.SpriteRAM, v.flipped(), digdugSprite)
+	r.SetRenderTarget(nil)
+	return nil
+}
+
+// drawLayer calls draw for each of the tiles on the screen. The memory
+// address of the tile is passed along with the destination.
+func (v *Video) drawLayer(r *sdl.Renderer, draw func(*sdl.Renderer, int, *sdl.Rect)) {
+	for ty := 0; ty < 36; ty++ {
+		for tx := 0; tx < 28; tx++ {
+			var addr int
+			if ty == 0 || ty == 1 {
+				addr = 0x3dd + (ty * 0x20) - tx
+			} else if ty == 34 || ty == 35 {
+				addr = 0x01d + ((ty - 34) * 0x20) - tx
+			} else {
+				addr = 0x3a0 + (ty - 2) - (tx * 0x20)
+			}
+			dest := sdl.Rect{
+				X: int32(tx) * 8,
+				Y: int32(ty) * 8,
+				W: 8,
+				H: 8,
+			}
+			draw(r, addr, &dest)
+		}
+	}
+}
+
+// drawPlayfield draws a tile from the playfield layout selected by the
+// latch. When disabled, the playfield is still drawn but with a palette
+// that is all black.
+func (v *Video) drawPlayfield(r *sdl.Renderer, addr int, dest *sdl.Rect) {
+	sel := int(v.Latch[LatchBGSelect0] | v.Latch[LatchBGSelect1]<<1)
+	code := v.playfield[addr|sel<<10]
+	bank := v.Latch[LatchBGColor0] | v.Latch[LatchBGColor1]<<1
+	pal := code>>4 | bank<<4
+	if v.Latch[LatchBGDisable] != 0 {
+		pal = 0x0f
+	}
+	v.drawTile(r, v.tiles[pal&0x3f], int32(code), dest)
+}
+
+// drawChar draws a character from tile memory. The color is either the
+// low nibble of the character or comes from the upper bits depending on
+// the latch.
+func (v *Video) drawChar(r *sdl.Renderer, addr int, dest *sdl.Rect) {
+	code := v.TileMemory[addr]
+	var pal uint8
+	if v.Latch[LatchTextColor] != 0 {
+		pal = code & 0x0f
+	} else {
+		pal = (code>>4)&0x0e | (code>>3)&0x02
+	}
+	v.drawTile(r, v.chars[pal], int32(code&0x7f), dest)
+}
+
+func (v *Video) drawTile(r *sdl.Renderer, sheet rcs.TileSheet, n int32, dest *sdl.Rect) {
+	rowTiles := sheet.TextureW / sheet.TileW
+	src := sdl.Rect{
+		X: (n % rowTiles) * sheet.TileW,
+		Y: (n / rowTiles) * sheet.TileH,
+		W: sheet.TileW,
+		H: sheet.TileH,
+	}
+	if v.flipped() {
+		flip(dest)
+		r.CopyEx(sheet.Texture, &src, dest, 0, nil,
+			sdl.FLIP_HORIZONTAL|sdl.FLIP_VERTICAL)
+	} else {
+		r.Copy(sheet.Texture, &src, dest)
+	}
+}
+
+// digdugSprite decodes the sprite attributes for Dig Dug:
+//
+//	bank 0: sprite number and size, palette
+//	bank 1: y position, x position
+//	bank 2: flip
+//
+// A large sprite is both double width and double height and uses four
+// sprites in the sheet. There is no ninth bit for the x position and
+// sprites wrap around the edge of the screen.
+func digdugSprite(ram [3][]uint8, offs int) namco.Sprite {
+	bank0, bank1, bank2 := ram[0], ram[1], ram[2]
+	spriteN := int32(bank0[offs])
+	size := spriteN >> 7
+	if size != 0 {
+		spriteN = spriteN&0xc0 | (spriteN&^0xc0)<<2
+	}
+	return namco.Sprite{
+		N:     spriteN,
+		Pal:   bank0[offs+1] & 0x3f,
+		X:     int32(bank1[offs+1]) - 40 + 1,
+		Y:     256 - int32(bank1[offs]) + 1,
+		FlipX: bank2[offs]&0x01 != 0,
+		FlipY: bank2[offs]&0x02 != 0,
+		SizeX: size,
+		SizeY: size,
+		WrapX: true,
+	}
+}
+
+// flip moves the destination to where it appears when the screen is
+// rotated 180 degrees.
+func flip(dest *sdl.Rect) {
+	dest.X = namco.W - dest.X - dest.W
+	dest.Y = namco.H - dest.Y - dest.H
+}
+
+func (v *Video) Save(enc *rcs.Encoder) {
+	enc.Encode(v.Latch)
+}
+
+func (v *Video) Load(dec *rcs.Decoder) {
+	dec.Decode(&v.Latch)
+}
+
+// Blue has two bits instead of the three found for red and green
+var colorWeights = [][]uint8{
+	[]uint8{0x21, 0x00, 0x00},
+	[]uint8{0x47, 0x00, 0x00},
+	[]uint8{0x97, 0x00, 0x00},
+	[]uint8{0x00, 0x21, 0x00},
+	[]uint8{0x00, 0x47, 0x00},
+	[]uint8{0x00, 0x97, 0x00},
+	[]uint8{0x00, 0x00, 0x47},
+	[]uint8{0x00, 0x00, 0x97},
+}
+
+// Each character is 8 bytes with one byte for each column.
+var charPixels = [][]int{
+	[]int{56, 48, 40, 32, 24, 16, 8, 0},
+	[]int{57, 49, 41, 33, 25, 17, 9, 1},
+	[]int{58, 50, 42, 34, 26, 18, 10, 2},
+	[]int{59, 51, 43, 35, 27, 19, 11, 3},
+	[]int{60, 52, 44, 36, 28, 20, 12, 4},
+	[]int{61, 53, 45, 37, 29, 21, 13, 5},
+	[]int{62, 54, 46, 38, 30, 22, 14, 6},
+	[]int{63, 55, 47, 39, 31, 23, 15, 7},
+}
+
+func charReader(d []byte, base int, pixel int) uint8 {
+	addr := base + (pixel / 8)
+	return (d[addr] >> uint(pixel%8)) & 1
+}
+
+var tilePixels = [][]int{
+	[]int{63, 59, 55, 51, 47, 43, 39, 35},
+	[]int{62, 58, 54, 50, 46, 42, 38, 34},
+	[]int{61, 57, 53, 49, 45, 41, 37, 33},
+	[]int{60, 56, 52, 48, 44, 40, 36, 32},
+
+	[]int{31, 27, 23, 19, 15, 11, 7, 3},
+	[]int{30, 26, 22, 18, 14, 10, 6, 2},
+	[]int{29, 25, 21, 17, 13, 9, 5, 1},
+	[]int{28, 24, 20, 16, 12, 8, 4, 0},
+}
+
+var spritePixels = [][]int{
+	[]int{159, 155, 151, 147, 143, 139, 135, 131, 31, 27, 23, 19, 15, 11, 7, 3},
+	[]int{158, 154, 150, 146, 142, 138, 134, 130, 30, 26, 22, 18, 14, 10, 6, 2},
+	[]int{157, 153, 149, 145, 141, 137, 133, 129, 29, 25, 21, 17, 13, 9, 5, 1},
+	[]int{156, 152, 148, 144, 140, 136, 132, 128, 28, 24, 20, 16, 12, 8, 4, 0},
+
+	[]int{191, 187, 183, 179, 175, 171, 167, 163, 63, 59, 55, 51, 47, 43, 39, 35},
+	[]int{190, 186, 182, 178, 174, 170, 166, 162, 62, 58, 54, 50, 46, 42, 38, 34},
+	[]int{189, 185, 181, 177, 173, 169, 165, 161, 61, 57, 53, 49, 45, 41, 37, 33},
+	[]int{188, 184, 180, 176, 172, 168, 164, 160, 60, 56, 52, 48, 44, 40, 36, 32},
+
+	[]int{223, 219, 215, 211, 207, 203, 199, 195, 95, 91, 87, 83, 79, 75, 71, 67},
+	[]int{222, 218, 214, 210, 206, 202, 198, 194, 94, 90, 86, 82, 78, 74, 70, 66},
+	[]int{221, 217, 213, 209, 205, 201, 197, 193, 93, 89, 85, 81, 77, 73, 69, 65},
+	[]int{220, 216, 212, 208, 204, 200, 196, 192, 92, 88, 84, 80, 76, 72, 68, 64},
+
+	[]int{255, 251, 247, 243, 239, 235, 231, 227, 127, 123, 119, 115, 111, 107, 103, 99},
+	[]int{254, 250, 246, 242, 238, 234, 230, 226, 126, 122, 118, 114, 110, 106, 102, 98},
+	[]int{253, 249, 245, 241, 237, 233, 229, 225, 125, 121, 117, 113, 109, 105, 101, 97},
+	[]int{252, 248, 244, 240, 236, 232, 228, 224, 124, 120, 116, 112, 108, 104, 100, 96},
+}
+
+func pixelReader(d []byte, base int, pixel int) uint8 {
+	addr := base + (pixel / 4)
+	offset := pixel % 4
+	return rcs.BitPlane4(d[addr], offset)
+}
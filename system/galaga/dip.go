@@ -0,0 +1,93 @@
+package galaga
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+// Switch banks
+const (
+	dswA = 0 // coinage, bonus life, lives
+	dswB = 1 // difficulty, demo sounds, freeze, rack test, cabinet
+)
+
+// dswBUnused are the switches in bank B that are not used by the game.
+// They are left in the off position which reads as a one.
+const dswBUnused = 0x44
+
+// DIP contains the switch settings for each ROM set. The bonus life
+// values are those used with 2 to 4 lives. The game awards a different
+// set of bonuses when playing with 5 lives.
+var DIP = map[string][]rcs.DIPSetting{
+	"galaga": []rcs.DIPSetting{
+		{
+			Name: "coinage", Bank: dswA, Mask: 0x07, Default: "1c1c",
+			Values: []rcs.DIPValue{
+				{Name: "free-play", Bits: 0x00},
+				{Name: "2c3c", Bits: 0x01},
+				{Name: "3c1c", Bits: 0x02},
+				{Name: "1c2c", Bits: 0x03},
+				{Name: "4c1c", Bits: 0x04},
+				{Name: "1c3c", Bits: 0x05},
+				{Name: "2c1c", Bits: 0x06},
+				{Name: "1c1c", Bits: 0x07},
+			},
+		},
+		{
+			Name: "bonus-life", Bank: dswA, Mask: 0x38, Default: "20k-70k-every",
+			Values: []rcs.DIPValue{
+				{Name: "none", Bits: 0x00},
+				{Name: "30k-100k-every", Bits: 0x08},
+				{Name: "20k-70k-every", Bits: 0x10},
+				{Name: "20k-60k", Bits: 0x18},
+				{Name: "20k-60k-every", Bits: 0x20},
+				{Name: "30k-120k-every", Bits: 0x28},
+				{Name: "20k-80k-every", Bits: 0x30},
+				{Name: "30k-80k", Bits: 0x38},
+			},
+		},
+		{
+			Name: "lives", Bank: dswA, Mask: 0xc0, Default: "3",
+			Values: []rcs.DIPValue{
+				{Name: "2", Bits: 0x00},
+				{Name: "4", Bits: 0x40},
+				{Name: "3", Bits: 0x80},
+				{Name: "5", Bits: 0xc0},
+			},
+		},
+		{
+			Name: "difficulty", Bank: dswB, Mask: 0x03, Default: "easy",
+			Values: []rcs.DIPValue{
+				{Name: "medium", Bits: 0x00},
+				{Name: "hard", Bits: 0x01},
+				{Name: "hardest", Bits: 0x02},
+				{Name: "easy", Bits: 0x03},
+			},
+		},
+		{
+			Name: "demo-sounds", Bank: dswB, Mask: 0x08, Default: "on",
+			Values: []rcs.DIPValue{
+				{Name: "on", Bits: 0x00},
+				{Name: "off", Bits: 0x08},
+			},
+		},
+		{
+			Name: "freeze", Bank: dswB, Mask: 0x10, Default: "off",
+			Values: []rcs.DIPValue{
+				{Name: "on", Bits: 0x00},
+				{Name: "off", Bits: 0x10},
+			},
+		},
+		{
+			Name: "rack-test", Bank: dswB, Mask: 0x20, Default: "off",
+			Values: []rcs.DIPValue{
+				{Name: "on", Bits: 0x00},
+				{Name: "off", Bits: 0x20},
+			},
+		},
+		{
+			Name: "cabinet", Bank: dswB, Mask: 0x80, Default: "upright",
+			Values: []rcs.DIPValue{
+				{Name: "cocktail", Bits: 0x00},
+				{Name: "upright", Bits: 0x80},
+			},
+		},
+	},
+}
@@ -2,32 +2,30 @@
 package galaga
 
 import (
+	"path/filepath"
+
 	"github.com/blackchip-org/retro-cs/config"
 	"github.com/blackchip-org/retro-cs/rcs"
 	"github.com/blackchip-org/retro-cs/rcs/namco"
-	"github.com/blackchip-org/retro-cs/rcs/z80"
 )
 
 type System struct {
-	cpu   [3]*z80.CPU
-	mem   [3]*rcs.Memory
+	*namco.Board
 	ram   []uint8
-	n06xx *namco.N06XX
-	n51xx *namco.N51XX
+	n05xx *namco.N05XX
 	n54xx *namco.N54XX
 
 	video *namco.Video
 
-	InterruptEnable0 uint8 // low bit
-	InterruptEnable1 uint8 // low bit
-	InterruptEnable2 uint8 // low bit
-	reset            uint8
-	dipSwitches      [8]uint8
+	dswA uint8
+	dswB uint8
+
+	DIP *rcs.DIPSwitches
 }
 
-func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
+func new(ctx rcs.SDLContext, set string) (*rcs.Mach, error) {
 	s := &System{}
-	roms, err := rcs.LoadROMs(config.DataDir, set)
+	roms, err := rcs.LoadROMs(config.DataDir, ROM[set])
 	if err != nil {
 		return nil, err
 	}
@@ -37,45 +35,45 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 	ram := make([]uint8, 0x2000, 0x2000)
 
 	mem.MapRAM(0x6800, make([]uint8, 0x100, 0x100)) // temporary
+	// Each address reads one switch from each bank: bank B in bit 0 and
+	// bank A in bit 1.
 	for i := 0; i < 8; i++ {
-		mem.MapRW(0x6800+i, &s.dipSwitches[i])
+		n := uint(i)
+		mem.MapLoad(0x6800+i, func() uint8 {
+			return (s.dswB>>n)&1 | ((s.dswA>>n)&1)<<1
+		})
 	}
-	mem.MapRW(0x6820, &s.InterruptEnable0)
-	mem.MapRW(0x6821, &s.InterruptEnable1)
-	mem.MapRW(0x6822, &s.InterruptEnable2)
-	mem.MapRW(0x6823, &s.reset)
-
 	mem.MapRAM(0x7000, make([]uint8, 0x1000, 0x1000))
+
+	board, err := namco.NewBoard(mem, roms["waveforms"], ctx.AudioSpec)
+	if err != nil {
+		return nil, err
+	}
+	s.Board = board
+
 	mem.MapRAM(0x8000, ram)
 	mem.MapRAM(0xa000, make([]uint8, 0x1000, 0x1000))
 
-	s.n51xx = namco.NewN51XX()
-	s.n54xx = namco.NewN54XX()
-
-	s.n06xx = namco.NewN06XX()
-	s.n06xx.DeviceW[0] = s.n51xx.Write
-	s.n06xx.DeviceR[0] = s.n51xx.Read
-	s.n06xx.DeviceW[3] = s.n54xx.Write
-	s.n06xx.DeviceR[3] = s.n54xx.Read
-	for i, addr := 0, 0x7000; addr < 0x7100; addr, i = addr+1, i+1 {
-		j := i
-		mem.MapLoad(addr, s.n06xx.ReadData(j))
-		mem.MapStore(addr, s.n06xx.WriteData(j))
-	}
-	for i, addr := 0, 0x7100; addr < 0x7200; addr, i = addr+1, i+1 {
-		j := i
-		mem.MapLoad(addr, s.n06xx.ReadCtrl(j))
-		mem.MapStore(addr, s.n06xx.WriteCtrl(j))
+	// starfield control latch
+	s.n05xx = namco.NewN05XX()
+	for i := 0; i < 6; i++ {
+		mem.MapStore(0xa000+i, s.n05xx.WriteControl(i))
 	}
 
+	s.n54xx = namco.NewN54XX(ctx.AudioSpec)
+	s.WSG.Mixers = append(s.WSG.Mixers, s.n54xx)
+	s.N06XX.DeviceW[3] = s.n54xx.Write
+	s.N06XX.DeviceR[3] = s.n54xx.Read
+
 	var screen rcs.Screen
 	var video *namco.Video
 	if ctx.Renderer != nil {
 		data := namco.Data{
-			Palettes: roms["palettes"],
-			Colors:   roms["colors"],
-			Tiles:    roms["tiles"],
-			Sprites:  roms["sprites"],
+			Palettes:       roms["palettes"],
+			SpritePalettes: roms["spritepalettes"],
+			Colors:         roms["colors"],
+			Tiles:          roms["tiles"],
+			Sprites:        roms["sprites"],
 		}
 		video, err = newVideo(ctx.Renderer, data)
 		if err != nil {
@@ -83,6 +81,12 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 		}
 		mem.MapRAM(0x8000, video.TileMemory)
 		mem.MapRAM(0x8400, video.ColorMemory)
+		video.SpriteRAM = [3][]uint8{
+			ram[0x0b80:0x0c00],
+			ram[0x1380:0x1400],
+			ram[0x1b80:0x1c00],
+		}
+		video.Starfield = s.n05xx
 
 		screen = rcs.Screen{
 			W:         namco.W,
@@ -93,85 +97,87 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 		}
 	}
 
-	s.dipSwitches[3] = 1
-	s.dipSwitches[4] = 2
-	s.dipSwitches[5] = 1
-	s.dipSwitches[6] = 1
+	s.dswB = dswBUnused
+	s.DIP = rcs.NewDIPSwitches(DIP[set], &s.dswA, &s.dswB)
+	if err := s.DIP.LoadFile(filepath.Join(config.DataDir, "dip")); err != nil {
+		return nil, err
+	}
 
 	// HACK
 	mem.Write(0x9100, 0xff)
 	mem.Write(0x9101, 0xff)
 
-	// memory for each CPU
-	s.mem[0] = rcs.NewMemory(1, 0x10000)
-	s.mem[0].Name = "mem1"
-	s.mem[0].Map(0, mem)
-	s.mem[0].MapROM(0x0000, roms["code1"])
-
-	s.mem[1] = rcs.NewMemory(1, 0x10000)
-	s.mem[1].Name = "mem2"
-	s.mem[1].Map(0, mem)
-	s.mem[1].MapROM(0x0000, roms["code2"])
-
-	s.mem[2] = rcs.NewMemory(1, 0x10000)
-	s.mem[2].Name = "mem3"
-	s.mem[2].Map(0, mem)
-	s.mem[2].MapROM(0x0000, roms["code3"])
-
-	s.cpu[0] = z80.New(s.mem[0])
-	s.cpu[0].Name = "cpu1"
-	s.cpu[1] = z80.New(s.mem[1])
-	s.cpu[1].Name = "cpu2"
-	s.cpu[2] = z80.New(s.mem[2])
-	s.cpu[2].Name = "cpu3"
+	s.InitCPUs([3][]uint8{roms["code1"], roms["code2"], roms["code3"]})
+	s.ram = ram
+	s.video = video
 
 	vblank := func() {
-		if s.InterruptEnable0 != 0 {
-			s.cpu[0].IRQ = true
-		}
-		if s.InterruptEnable1 != 0 {
-			s.cpu[1].IRQ = true
-		}
-		//s.cpu[2].IRQ = true
-		if s.InterruptEnable2 != 0 {
-			// FIXME: Is this correct??? Probably not
-			s.cpu[2].NMI = true
-		}
-		if s.reset != 0 {
-			s.reset = 0
-			s.cpu[1].RESET = true
-			s.cpu[2].RESET = true
-		}
+		s.n05xx.VBlank()
+		s.VBlank()
 	}
 
-	s.n06xx.NMI = func() {
-		s.cpu[0].NMI = true
-	}
+	inputs := namco.NewInputs(s.N51XX, namco.JoyLeft|namco.JoyRight)
 
 	mach := &rcs.Mach{
 		Sys: s,
 		Comps: []rcs.Component{
 			rcs.NewComponent("galaga", "galaga", "", s),
-			rcs.NewComponent("mem1", "mem", "", s.mem[0]),
-			rcs.NewComponent("mem2", "mem", "", s.mem[1]),
-			rcs.NewComponent("mem3", "mem", "", s.mem[2]),
-			rcs.NewComponent("cpu1", "z80", "mem1", s.cpu[0]),
-			rcs.NewComponent("cpu2", "z80", "mem2", s.cpu[1]),
-			rcs.NewComponent("cpu3", "z80", "mem3", s.cpu[2]),
-			rcs.NewComponent("n06xx", "n06xx", "", s.n06xx),
-			rcs.NewComponent("n51xx", "n51xx", "", s.n51xx),
+			rcs.NewComponent("dip", "dip", "", s.DIP),
+			rcs.NewComponent("mem1", "mem", "", s.Mem[0]),
+			rcs.NewComponent("mem2", "mem", "", s.Mem[1]),
+			rcs.NewComponent("mem3", "mem", "", s.Mem[2]),
+			rcs.NewComponent("cpu1", "z80", "mem1", s.CPU[0]),
+			rcs.NewComponent("cpu2", "z80", "mem2", s.CPU[1]),
+			rcs.NewComponent("cpu3", "z80", "mem3", s.CPU[2]),
+			rcs.NewComponent("n05xx", "n05xx", "", s.n05xx),
+			rcs.NewComponent("n06xx", "n06xx", "", s.N06XX),
+			rcs.NewComponent("n51xx", "n51xx", "", s.N51XX),
 			rcs.NewComponent("n54xx", "n54xx", "", s.n54xx),
+			rcs.NewComponent("wsg", "wsg", "", s.WSG),
 		},
 		CharDecoders: map[string]rcs.CharDecoder{
 			"galaga": GalagaDecoder,
 		},
-		Ctx:        ctx,
-		Screen:     screen,
-		VBlankFunc: vblank,
+		Ctx:    ctx,
+		Screen: screen,
+		NVRAM: &rcs.NVRAM{
+			Name:   "hiscore",
+			Mem:    s.Mem[0],
+			Ranges: Hiscore[set],
+		},
+		VBlankFunc:    vblank,
+		QueueAudio:    s.WSG.Queue,
+		Keyboard:      inputs.Keyboard,
+		ButtonHandler: inputs.Button,
 	}
+	s.Attach(mach)
 	return mach, nil
 }
 
+func (s *System) Save(enc *rcs.Encoder) {
+	s.Board.Save(enc)
+	if s.video != nil {
+		s.video.Save(enc)
+	}
+	enc.Encode(s.ram)
+	enc.Encode(s.dswA)
+	enc.Encode(s.dswB)
+	enc.Encode(s.n05xx.Control)
+	enc.Encode(s.n05xx.Scroll)
+}
+
+func (s *System) Load(dec *rcs.Decoder) {
+	s.Board.Load(dec)
+	if s.video != nil {
+		s.video.Load(dec)
+	}
+	dec.Decode(&s.ram)
+	dec.Decode(&s.dswA)
+	dec.Decode(&s.dswB)
+	dec.Decode(&s.n05xx.Control)
+	dec.Decode(&s.n05xx.Scroll)
+}
+
 func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
-	return new(ctx, ROM["galaga"])
+	return new(ctx, "galaga")
 }
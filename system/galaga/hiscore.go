@@ -0,0 +1,13 @@
+package galaga
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+// Hiscore contains the memory saved between runs for each ROM set. The
+// table of the top five scores and initials starts at $8a4c and the
+// high score shown at the top of the screen is at $83ed.
+var Hiscore = map[string][]rcs.NVRAMRange{
+	"galaga": []rcs.NVRAMRange{
+		{Addr: 0x8a4c, Len: 0x18, Start: 0x24, End: 0x18},
+		{Addr: 0x83ed, Len: 5, Start: 0x24, End: 0x24},
+	},
+}
@@ -29,9 +29,25 @@ var VideoConfig = namco.Config{
 		PixelReader:  pixelReader,
 		BytesPerCell: 64,
 	},
-	PaletteEntries: 32,
-	PaletteColors:  8,
-	Hack:           true,
+	PaletteEntries: 64,
+	PaletteColors:  4,
+	Colors:         32,
+	ColorWeights:   colorWeights,
+	Transparent:    []int{0x0f, 0x1f},
+	TileColorBase:  0x10,
+	Sprites:        namco.GalagaSprites,
+}
+
+// Blue has two bits instead of the three found for red and green
+var colorWeights = [][]uint8{
+	[]uint8{0x21, 0x00, 0x00},
+	[]uint8{0x47, 0x00, 0x00},
+	[]uint8{0x97, 0x00, 0x00},
+	[]uint8{0x00, 0x21, 0x00},
+	[]uint8{0x00, 0x47, 0x00},
+	[]uint8{0x00, 0x97, 0x00},
+	[]uint8{0x00, 0x00, 0x47},
+	[]uint8{0x00, 0x00, 0x97},
 }
 
 var tilePixels = [][]int{
@@ -0,0 +1,45 @@
+package pacman
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+// mapMakeTrax installs the protection found on Crush Roller and Make Trax.
+// The top two bits of the DIP switches and the values read from $50c0
+// depend on the offset read and on the instruction doing the reading.
+func (s *System) mapMakeTrax(mach *rcs.Mach) {
+	for i := 0x5080; i <= 0x50bf; i++ {
+		offset := i & 0x3f
+		s.mem.MapLoad(i, func() uint8 {
+			data := s.dipSwitches
+			if mach.At == 0x1973 || mach.At == 0x2389 {
+				return data | 0x40
+			}
+			switch offset {
+			case 0x01, 0x04:
+				return data | 0x40
+			case 0x05:
+				return data | 0xc0
+			}
+			return data & 0x3f
+		})
+	}
+	for i := 0x50c0; i <= 0x50ff; i++ {
+		offset := i & 0x3f
+		s.mem.MapLoad(i, func() uint8 {
+			switch mach.At {
+			case 0x040e:
+				return 0x20
+			case 0x115e, 0x3ae2:
+				return 0x00
+			}
+			switch offset {
+			case 0x00:
+				return 0x1f
+			case 0x09:
+				return 0x30
+			case 0x0c:
+				return 0x00
+			}
+			return 0x20
+		})
+	}
+}
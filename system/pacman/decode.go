@@ -0,0 +1,146 @@
+package pacman
+
+// Decode contains the functions for each ROM set that need to modify the
+// contents of the ROMs after loading. This is used for encrypted code and
+// for graphics that are not in the usual layout.
+var Decode = map[string]func(roms map[string][]byte){
+	"pacplus":     decodePacPlus,
+	"mspacmanaux": decodeMsPacManAux,
+	"ponpoko":     decodePonpoko,
+}
+
+// bitswap returns the bits in v selected by each position in bits. The
+// first position is the most significant bit in the result.
+func bitswap(v int, bits ...uint) int {
+	r := 0
+	for _, b := range bits {
+		r = r<<1 | v>>b&1
+	}
+	return r
+}
+
+// Each row has the source bit for result bits 7 through 0 followed by the
+// value to exclusive-or with the result.
+var pacPlusSwapXor = [6][9]int{
+	{7, 6, 5, 4, 3, 2, 1, 0, 0x00},
+	{7, 6, 5, 4, 3, 2, 1, 0, 0x28},
+	{6, 1, 3, 2, 5, 7, 0, 4, 0x96},
+	{6, 1, 5, 2, 3, 7, 0, 4, 0xbe},
+	{0, 3, 7, 6, 4, 2, 1, 5, 0xd5},
+	{0, 3, 4, 6, 7, 2, 1, 5, 0xdd},
+}
+
+var pacPlusPick = [32]int{
+	0, 2, 4, 2, 4, 0, 4, 2, 2, 0, 2, 2, 4, 0, 4, 2,
+	2, 2, 4, 0, 4, 2, 4, 0, 0, 4, 0, 4, 4, 2, 4, 2,
+}
+
+// pacPlusDecrypt returns the decrypted value found at addr. The method used
+// is picked by address lines 0, 2, 5, 7, and 9 and switched to its pair
+// when line 11 is set.
+func pacPlusDecrypt(addr int, v uint8) uint8 {
+	method := pacPlusPick[addr&0x001|
+		(addr&0x004)>>1|
+		(addr&0x020)>>3|
+		(addr&0x080)>>4|
+		(addr&0x200)>>5]
+	if addr&0x800 != 0 {
+		method ^= 1
+	}
+	t := pacPlusSwapXor[method]
+	r := 0
+	for _, b := range t[:8] {
+		r = r<<1 | int(v)>>uint(b)&1
+	}
+	return uint8(r ^ t[8])
+}
+
+func decodePacPlus(roms map[string][]byte) {
+	code := roms["code"]
+	for addr, v := range code {
+		code[addr] = pacPlusDecrypt(addr, v)
+	}
+}
+
+// Data lines and address lines on the Ms. Pac-Man auxiliary board are
+// scrambled.
+func msDecryptData(v uint8) uint8 {
+	return uint8(bitswap(int(v), 0, 4, 5, 7, 6, 3, 2, 1))
+}
+
+func msAddr11(i int) int {
+	return bitswap(i, 8, 7, 5, 9, 10, 6, 3, 4, 2, 1, 0)
+}
+
+func msAddr12(i int) int {
+	return bitswap(i, 11, 3, 7, 9, 10, 8, 6, 5, 4, 2, 1, 0)
+}
+
+// msPatches are the locations in the Pac-Man code that are replaced with
+// eight bytes from the auxiliary ROMs. The first value is the address in
+// the Pac-Man code and the second value is the source.
+var msPatches = [][2]int{
+	{0x0410, 0x8008}, {0x08e0, 0x81d8}, {0x0a30, 0x8118}, {0x0bd0, 0x80d8},
+	{0x0c20, 0x8120}, {0x0e58, 0x8168}, {0x0ea8, 0x8198}, {0x1000, 0x8020},
+	{0x1008, 0x8010}, {0x1288, 0x8098}, {0x1348, 0x8048}, {0x1688, 0x8088},
+	{0x16b0, 0x8188}, {0x16d8, 0x80c8}, {0x16f8, 0x81c8}, {0x19a8, 0x80a8},
+	{0x19b8, 0x81a8}, {0x2060, 0x8148}, {0x2108, 0x8018}, {0x21a0, 0x81a0},
+	{0x2298, 0x80a0}, {0x23e0, 0x80e8}, {0x2418, 0x8000}, {0x2448, 0x8058},
+	{0x2470, 0x8140}, {0x2488, 0x8080}, {0x24b0, 0x8180}, {0x24d8, 0x80c0},
+	{0x24f8, 0x81c0}, {0x2748, 0x8050}, {0x2780, 0x8090}, {0x27b8, 0x8190},
+	{0x2800, 0x8028}, {0x2b20, 0x8100}, {0x2b30, 0x8110}, {0x2bf0, 0x81d0},
+	{0x2cc0, 0x80d0}, {0x2cd8, 0x80e0}, {0x2cf0, 0x81e0}, {0x2d60, 0x8160},
+}
+
+// decodeMsPacManAux builds the code for Ms. Pac-Man from the original
+// Pac-Man ROMs and the encrypted ROMs found on the auxiliary board (u5,
+// u6, and u7). The board switches between the Pac-Man code and this code
+// during startup but the decrypted code is used from the start here, the
+// same as the bootleg ROMs.
+func decodeMsPacManAux(roms map[string][]byte) {
+	pac := roms["code"]
+	u5, u6, u7 := roms["u5"], roms["u6"], roms["u7"]
+
+	rom := make([]byte, 0xc000)
+	copy(rom[0x0000:], pac[0x0000:0x3000])
+	for i := 0; i < 0x1000; i++ {
+		rom[0x3000+i] = msDecryptData(u7[msAddr12(i)])
+	}
+	for i := 0; i < 0x800; i++ {
+		rom[0x8000+i] = msDecryptData(u5[msAddr11(i)])
+		rom[0x8800+i] = msDecryptData(u6[0x800+msAddr12(i)])
+		rom[0x9000+i] = msDecryptData(u6[msAddr12(i)])
+		rom[0x9800+i] = pac[0x1800+i]
+	}
+	copy(rom[0xa000:], pac[0x2000:0x4000])
+
+	for _, p := range msPatches {
+		copy(rom[p[0]:p[0]+8], rom[p[1]:p[1]+8])
+	}
+
+	roms["code"] = rom[0x0000:0x4000]
+	roms["code2"] = rom[0x8000:0xc000]
+	delete(roms, "u5")
+	delete(roms, "u6")
+	delete(roms, "u7")
+}
+
+// decodePonpoko reverts the graphics to the layout used by the other games
+// on this board. The two halves of each tile are swapped and the four
+// quarters of each sprite are rotated.
+func decodePonpoko(roms map[string][]byte) {
+	tiles := roms["tiles"]
+	for i := 0; i+0x10 <= len(tiles); i += 0x10 {
+		for j := 0; j < 8; j++ {
+			tiles[i+j], tiles[i+j+0x08] = tiles[i+j+0x08], tiles[i+j]
+		}
+	}
+	sprites := roms["sprites"]
+	for i := 0; i+0x20 <= len(sprites); i += 0x20 {
+		for j := 0; j < 8; j++ {
+			a := i + j
+			sprites[a], sprites[a+0x08], sprites[a+0x10], sprites[a+0x18] =
+				sprites[a+0x18], sprites[a], sprites[a+0x08], sprites[a+0x10]
+		}
+	}
+}
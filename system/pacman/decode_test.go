@@ -0,0 +1,76 @@
+package pacman
+
+import "testing"
+
+func TestPacPlusDecrypt(t *testing.T) {
+	tests := []struct {
+		addr int
+		v    uint8
+		want uint8
+	}{
+		{0x000, 0x5a, 0x5a}, // method 0, unchanged
+		{0x800, 0x5a, 0x72}, // method 1, xor only
+		{0x001, 0x00, 0x96}, // method 2
+		{0x001, 0x40, 0x16}, // method 2, bit 6 moves to bit 7
+	}
+	for _, test := range tests {
+		have := pacPlusDecrypt(test.addr, test.v)
+		if have != test.want {
+			t.Errorf("$%03x: have %02x, want %02x", test.addr, have, test.want)
+		}
+	}
+}
+
+func TestMsDecryptData(t *testing.T) {
+	have := msDecryptData(0x01)
+	if have != 0x80 {
+		t.Errorf("\n have: %02x \n want: %02x", have, 0x80)
+	}
+}
+
+func TestDecodeMsPacManAux(t *testing.T) {
+	roms := map[string][]byte{
+		"code": make([]byte, 0x4000),
+		"u5":   make([]byte, 0x800),
+		"u6":   make([]byte, 0x1000),
+		"u7":   make([]byte, 0x1000),
+	}
+	// Address line 3 is wired to line 4 on u5
+	roms["u5"][0x10] = 0x01
+	roms["code"][0x2000] = 0x42
+	decodeMsPacManAux(roms)
+
+	code, code2 := roms["code"], roms["code2"]
+	if code2[0x0008] != 0x80 {
+		t.Errorf("u5: have %02x, want %02x", code2[0x0008], 0x80)
+	}
+	if code[0x0410] != 0x80 {
+		t.Errorf("patch: have %02x, want %02x", code[0x0410], 0x80)
+	}
+	if code2[0x2000] != 0x42 {
+		t.Errorf("mirror: have %02x, want %02x", code2[0x2000], 0x42)
+	}
+	if _, ok := roms["u5"]; ok {
+		t.Errorf("u5 not removed")
+	}
+}
+
+func TestDecodePonpoko(t *testing.T) {
+	roms := map[string][]byte{
+		"tiles":   make([]byte, 0x10),
+		"sprites": make([]byte, 0x20),
+	}
+	roms["tiles"][0x00] = 1
+	roms["sprites"][0x00] = 1
+	roms["sprites"][0x18] = 2
+	decodePonpoko(roms)
+	if roms["tiles"][0x08] != 1 {
+		t.Errorf("tiles: have %02x, want %02x", roms["tiles"][0x08], 1)
+	}
+	if roms["sprites"][0x08] != 1 {
+		t.Errorf("sprites: have %02x, want %02x", roms["sprites"][0x08], 1)
+	}
+	if roms["sprites"][0x00] != 2 {
+		t.Errorf("sprites: have %02x, want %02x", roms["sprites"][0x00], 2)
+	}
+}
@@ -0,0 +1,209 @@
+package pacman
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+// Switch banks
+const (
+	dipBank  = 0 // DIP switches at $5080
+	in1Bank  = 1 // cabinet switch in IN1
+	dip2Bank = 2 // DIP switches at $50c0, Ponpoko only
+)
+
+var coinage = rcs.DIPSetting{
+	Name: "coinage", Bank: dipBank, Mask: 0x03, Default: "1c1c",
+	Values: []rcs.DIPValue{
+		{Name: "free-play", Bits: 0x00},
+		{Name: "1c1c", Bits: 0x01},
+		{Name: "1c2c", Bits: 0x02},
+		{Name: "2c1c", Bits: 0x03},
+	},
+}
+
+var lives = rcs.DIPSetting{
+	Name: "lives", Bank: dipBank, Mask: 0x0c, Default: "3",
+	Values: []rcs.DIPValue{
+		{Name: "1", Bits: 0x00},
+		{Name: "2", Bits: 0x04},
+		{Name: "3", Bits: 0x08},
+		{Name: "5", Bits: 0x0c},
+	},
+}
+
+var bonusLife = rcs.DIPSetting{
+	Name: "bonus-life", Bank: dipBank, Mask: 0x30, Default: "10000",
+	Values: []rcs.DIPValue{
+		{Name: "10000", Bits: 0x00},
+		{Name: "15000", Bits: 0x10},
+		{Name: "20000", Bits: 0x20},
+		{Name: "none", Bits: 0x30},
+	},
+}
+
+var difficulty = rcs.DIPSetting{
+	Name: "difficulty", Bank: dipBank, Mask: 0x40, Default: "normal",
+	Values: []rcs.DIPValue{
+		{Name: "normal", Bits: 0x40},
+		{Name: "hard", Bits: 0x00},
+	},
+}
+
+var ghostNames = rcs.DIPSetting{
+	Name: "ghost-names", Bank: dipBank, Mask: 0x80, Default: "normal",
+	Values: []rcs.DIPValue{
+		{Name: "normal", Bits: 0x80},
+		{Name: "alternate", Bits: 0x00},
+	},
+}
+
+// The players sit opposite each other at a cocktail table and the screen
+// is flipped for the second player.
+var cabinet = rcs.DIPSetting{
+	Name: "cabinet", Bank: in1Bank, Mask: 0x80, Default: "upright",
+	Values: []rcs.DIPValue{
+		{Name: "upright", Bits: 0x80},
+		{Name: "cocktail", Bits: 0x00},
+	},
+}
+
+// Crush Roller has more lives, no bonus life, and the top two switches
+// are used by the protection.
+var crushLives = rcs.DIPSetting{
+	Name: "lives", Bank: dipBank, Mask: 0x0c, Default: "3",
+	Values: []rcs.DIPValue{
+		{Name: "3", Bits: 0x00},
+		{Name: "4", Bits: 0x04},
+		{Name: "5", Bits: 0x08},
+		{Name: "6", Bits: 0x0c},
+	},
+}
+
+var crushFirstPattern = rcs.DIPSetting{
+	Name: "first-pattern", Bank: dipBank, Mask: 0x10, Default: "easy",
+	Values: []rcs.DIPValue{
+		{Name: "easy", Bits: 0x10},
+		{Name: "hard", Bits: 0x00},
+	},
+}
+
+var crushTeleportHoles = rcs.DIPSetting{
+	Name: "teleport-holes", Bank: dipBank, Mask: 0x20, Default: "off",
+	Values: []rcs.DIPValue{
+		{Name: "off", Bits: 0x20},
+		{Name: "on", Bits: 0x00},
+	},
+}
+
+var crushCabinet = rcs.DIPSetting{
+	Name: "cabinet", Bank: in1Bank, Mask: 0x10, Default: "upright",
+	Values: []rcs.DIPValue{
+		{Name: "upright", Bits: 0x10},
+		{Name: "cocktail", Bits: 0x00},
+	},
+}
+
+var ponpokoBonusLife = rcs.DIPSetting{
+	Name: "bonus-life", Bank: dipBank, Mask: 0x03, Default: "10000",
+	Values: []rcs.DIPValue{
+		{Name: "none", Bits: 0x00},
+		{Name: "10000", Bits: 0x01},
+		{Name: "30000", Bits: 0x02},
+		{Name: "50000", Bits: 0x03},
+	},
+}
+
+var ponpokoLives = rcs.DIPSetting{
+	Name: "lives", Bank: dipBank, Mask: 0x30, Default: "3",
+	Values: []rcs.DIPValue{
+		{Name: "2", Bits: 0x00},
+		{Name: "3", Bits: 0x10},
+		{Name: "4", Bits: 0x20},
+		{Name: "5", Bits: 0x30},
+	},
+}
+
+var ponpokoCabinet = rcs.DIPSetting{
+	Name: "cabinet", Bank: dipBank, Mask: 0x40, Default: "upright",
+	Values: []rcs.DIPValue{
+		{Name: "upright", Bits: 0x40},
+		{Name: "cocktail", Bits: 0x00},
+	},
+}
+
+// Only the common coinage settings are listed for each coin slot.
+var ponpokoCoinA = rcs.DIPSetting{
+	Name: "coin-a", Bank: dip2Bank, Mask: 0x0f, Default: "1c1c",
+	Values: []rcs.DIPValue{
+		{Name: "free-play", Bits: 0x00},
+		{Name: "1c1c", Bits: 0x01},
+		{Name: "2c1c", Bits: 0x02},
+		{Name: "1c2c", Bits: 0x03},
+		{Name: "3c1c", Bits: 0x04},
+	},
+}
+
+var ponpokoCoinB = rcs.DIPSetting{
+	Name: "coin-b", Bank: dip2Bank, Mask: 0xf0, Default: "1c1c",
+	Values: []rcs.DIPValue{
+		{Name: "free-play", Bits: 0x00},
+		{Name: "1c1c", Bits: 0x10},
+		{Name: "2c1c", Bits: 0x20},
+		{Name: "1c2c", Bits: 0x30},
+		{Name: "3c1c", Bits: 0x40},
+	},
+}
+
+// DIP contains the switch settings for each ROM set.
+var DIP = map[string][]rcs.DIPSetting{
+	"pacman": []rcs.DIPSetting{
+		coinage,
+		lives,
+		bonusLife,
+		difficulty,
+		ghostNames,
+		cabinet,
+	},
+	// The ghost names switch is not used
+	"mspacman": []rcs.DIPSetting{
+		coinage,
+		lives,
+		bonusLife,
+		difficulty,
+		cabinet,
+	},
+	"puckman": []rcs.DIPSetting{
+		coinage,
+		lives,
+		bonusLife,
+		difficulty,
+		ghostNames,
+		cabinet,
+	},
+	"pacplus": []rcs.DIPSetting{
+		coinage,
+		lives,
+		bonusLife,
+		difficulty,
+		cabinet,
+	},
+	"mspacmanaux": []rcs.DIPSetting{
+		coinage,
+		lives,
+		bonusLife,
+		difficulty,
+		cabinet,
+	},
+	"crush": []rcs.DIPSetting{
+		coinage,
+		crushLives,
+		crushFirstPattern,
+		crushTeleportHoles,
+		crushCabinet,
+	},
+	"ponpoko": []rcs.DIPSetting{
+		ponpokoCoinA,
+		ponpokoCoinB,
+		ponpokoBonusLife,
+		ponpokoLives,
+		ponpokoCabinet,
+	},
+}
@@ -0,0 +1,30 @@
+package pacman
+
+import "github.com/blackchip-org/retro-cs/rcs"
+
+// Hiscore contains the memory saved between runs for each ROM set. The
+// high score is kept at $4e88 and the digits shown at the top of the
+// screen are at $43ed. The high score is not saved for sets that are not
+// listed.
+var Hiscore = map[string][]rcs.NVRAMRange{
+	"pacman": []rcs.NVRAMRange{
+		{Addr: 0x4e88, Len: 3, Start: 0x00, End: 0x00},
+		{Addr: 0x43ed, Len: 6, Start: 0x40, End: 0x40},
+	},
+	"mspacman": []rcs.NVRAMRange{
+		{Addr: 0x4e88, Len: 3, Start: 0x00, End: 0x00},
+		{Addr: 0x43ed, Len: 6, Start: 0x40, End: 0x40},
+	},
+	"puckman": []rcs.NVRAMRange{
+		{Addr: 0x4e88, Len: 3, Start: 0x00, End: 0x00},
+		{Addr: 0x43ed, Len: 6, Start: 0x40, End: 0x40},
+	},
+	"pacplus": []rcs.NVRAMRange{
+		{Addr: 0x4e88, Len: 3, Start: 0x00, End: 0x00},
+		{Addr: 0x43ed, Len: 6, Start: 0x40, End: 0x40},
+	},
+	"mspacmanaux": []rcs.NVRAMRange{
+		{Addr: 0x4e88, Len: 3, Start: 0x00, End: 0x00},
+		{Addr: 0x43ed, Len: 6, Start: 0x40, End: 0x40},
+	},
+}
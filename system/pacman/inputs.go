@@ -5,10 +5,10 @@ import (
 )
 
 type keyboard struct {
-	s *system
+	s *System
 }
 
-func newKeyboard(s *system) *keyboard {
+func newKeyboard(s *System) *keyboard {
 	return &keyboard{s: s}
 }
 
@@ -17,40 +17,57 @@ func (k *keyboard) handle(e *sdl.KeyboardEvent) error {
 	if e.Type == sdl.KEYDOWN {
 		switch e.Keysym.Sym {
 		case sdl.K_1:
-			s.in1 |= 1 << 5
+			s.IN1 |= 1 << 5
 		case sdl.K_2:
-			s.in1 |= 1 << 6
+			s.IN1 |= 1 << 6
 		case sdl.K_c:
-			s.in0 |= 1 << 5
+			s.IN0 |= 1 << 5
 		case sdl.K_r:
-			s.in0 |= 1 << 4
+			s.IN0 |= 1 << 4
 		case sdl.K_UP:
-			s.in0 &^= 1 << 0
+			s.IN0 &^= 1 << 0
 		case sdl.K_LEFT:
-			s.in0 &^= 1 << 1
+			s.IN0 &^= 1 << 1
 		case sdl.K_RIGHT:
-			s.in0 &^= 1 << 2
+			s.IN0 &^= 1 << 2
 		case sdl.K_DOWN:
-			s.in0 &^= 1 << 3
+			s.IN0 &^= 1 << 3
+		// player 2 joystick, only used in a cocktail cabinet
+		case sdl.K_w:
+			s.IN1 &^= 1 << 0
+		case sdl.K_a:
+			s.IN1 &^= 1 << 1
+		case sdl.K_d:
+			s.IN1 &^= 1 << 2
+		case sdl.K_s:
+			s.IN1 &^= 1 << 3
 		}
 	} else if e.Type == sdl.KEYUP {
 		switch e.Keysym.Sym {
 		case sdl.K_1:
-			s.in1 &^= 1 << 5
+			s.IN1 &^= 1 << 5
 		case sdl.K_2:
-			s.in1 &^= 1 << 6
+			s.IN1 &^= 1 << 6
 		case sdl.K_c:
-			s.in0 &^= 1 << 5
+			s.IN0 &^= 1 << 5
 		case sdl.K_r:
-			s.in0 &^= 1 << 4
+			s.IN0 &^= 1 << 4
 		case sdl.K_UP:
-			s.in0 |= 1 << 0
+			s.IN0 |= 1 << 0
 		case sdl.K_LEFT:
-			s.in0 |= 1 << 1
+			s.IN0 |= 1 << 1
 		case sdl.K_RIGHT:
-			s.in0 |= 1 << 2
+			s.IN0 |= 1 << 2
 		case sdl.K_DOWN:
-			s.in0 |= 1 << 3
+			s.IN0 |= 1 << 3
+		case sdl.K_w:
+			s.IN1 |= 1 << 0
+		case sdl.K_a:
+			s.IN1 |= 1 << 1
+		case sdl.K_d:
+			s.IN1 |= 1 << 2
+		case sdl.K_s:
+			s.IN1 |= 1 << 3
 		}
 	}
 	return nil
@@ -66,69 +83,101 @@ const (
 	joyDown
 )
 
+// The first controller used is player 1 and the second controller is
+// player 2. The player 2 joystick is found in IN1 and is only read
+// by the game in a cocktail cabinet.
 type joystick struct {
-	s   *system
-	pos joyPos
+	s       *System
+	pos     [2]joyPos
+	players map[sdl.JoystickID]int
 }
 
-func newJoystick(s *system) *joystick {
-	return &joystick{s: s}
+func newJoystick(s *System) *joystick {
+	return &joystick{
+		s:       s,
+		players: make(map[sdl.JoystickID]int),
+	}
+}
+
+func (j *joystick) player(id sdl.JoystickID) (int, bool) {
+	p, ok := j.players[id]
+	if ok {
+		return p, true
+	}
+	if len(j.players) >= len(j.pos) {
+		return 0, false
+	}
+	p = len(j.players)
+	j.players[id] = p
+	return p, true
 }
 
 func (j *joystick) buttonHandler(e *sdl.ControllerButtonEvent) error {
 	s := j.s
+	p, ok := j.player(e.Which)
+	if !ok {
+		return nil
+	}
+	in := &s.IN0
+	start := uint8(1 << 5)
+	coin := uint8(1 << 5)
+	if p == 1 {
+		in = &s.IN1
+		start = 1 << 6
+		coin = 1 << 6
+	}
 	if e.Type == sdl.CONTROLLERBUTTONDOWN {
 		switch e.Button {
 		case sdl.CONTROLLER_BUTTON_BACK:
-			s.in0 |= 1 << 5
+			s.IN0 |= coin
 		case sdl.CONTROLLER_BUTTON_START:
-			s.in1 |= 1 << 5
+			s.IN1 |= start
 		case sdl.CONTROLLER_BUTTON_DPAD_UP:
-			if j.pos == joyNone {
-				j.pos = joyUp
-				s.in0 &^= 1 << 0
+			if j.pos[p] == joyNone {
+				j.pos[p] = joyUp
+				*in &^= 1 << 0
 			}
 		case sdl.CONTROLLER_BUTTON_DPAD_LEFT:
-			if j.pos == joyNone {
-				j.pos = joyLeft
-				s.in0 &^= 1 << 1
+			if j.pos[p] == joyNone {
+				j.pos[p] = joyLeft
+				*in &^= 1 << 1
 			}
 		case sdl.CONTROLLER_BUTTON_DPAD_RIGHT:
-			if j.pos == joyNone {
-				j.pos = joyRight
-				s.in0 &^= 1 << 2
+			if j.pos[p] == joyNone {
+				j.pos[p] = joyRight
+				*in &^= 1 << 2
 			}
 		case sdl.CONTROLLER_BUTTON_DPAD_DOWN:
-			if j.pos == joyNone {
-				j.pos = joyDown
-				s.in0 &^= 1 << 3
+			if j.pos[p] == joyNone {
+				j.pos[p] = joyDown
+				*in &^= 1 << 3
 			}
 		}
 	} else if e.Type == sdl.CONTROLLERBUTTONUP {
 		switch e.Button {
 		case sdl.CONTROLLER_BUTTON_BACK:
-			s.in0 &^= 1 << 5
+			s.IN0 &^= coin
 		case sdl.CONTROLLER_BUTTON_START:
-			s.in1 &^= 1 << 5
+			s.IN1 &^= start
 		case sdl.CONTROLLER_BUTTON_DPAD_UP:
-			if j.pos == joyUp {
-				j.pos = joyNone
-				s.in0 |= 1 << 0
+			if j.pos[p] == joyUp {
+				j.pos[p] = joyNone
+				*in |= 1 << 0
 			}
 		case sdl.CONTROLLER_BUTTON_DPAD_LEFT:
-			if j.pos == joyLeft {
-				j.pos = joyNone
-				s.in0 |= 1 << 1
+			if j.pos[p] == joyLeft {
+				j.pos[p] = joyNone
+				*in |= 1 << 1
 			}
 		case sdl.CONTROLLER_BUTTON_DPAD_RIGHT:
-			if j.pos == joyRight {
-				j.pos = joyNone
-				s.in0 |= 1 << 2
+			if j.pos[p] == joyRight {
+				j.pos[p] = joyNone
+				*in |= 1 << 2
 			}
 		case sdl.CONTROLLER_BUTTON_DPAD_DOWN:
-			if j.pos == joyDown {
-				j.pos = joyNone
-				s.in0 |= 1 << 3
+			if j.pos[p] == joyDown {
+				j.pos[p] = joyNone
+				*in |= 1 << 3
 			}
 		}
 	}
@@ -1,43 +1,65 @@
-// Package pacman is the hardware cabinet for Pac-Man and Ms. Pac-Man.
+// Package pacman is the hardware cabinet for Pac-Man, Ms. Pac-Man, and
+// other games that run on the same board.
 package pacman
 
 import (
+	"log"
+	"path/filepath"
+
 	"github.com/blackchip-org/retro-cs/config"
 	"github.com/blackchip-org/retro-cs/rcs"
 	"github.com/blackchip-org/retro-cs/rcs/namco"
 	"github.com/blackchip-org/retro-cs/rcs/z80"
+	"github.com/veandco/go-sdl2/sdl"
 )
 
-type system struct {
+// watchdogLimit is the number of vblanks without a write to the watchdog
+// before the board is reset
+const watchdogLimit = 16
+
+type System struct {
 	cpu   *z80.CPU
 	mem   *rcs.Memory
 	ram   []uint8
 	video *namco.Video
+	wsg   *namco.WSG
 
 	intSelect       uint8 // value sent during interrupt to select vector (port 0)
-	in0             uint8 // joystick #1, rack advance, coin slot, service button
-	interruptEnable uint8
-	soundEnable     uint8
+	IN0             uint8 // joystick #1, rack advance, coin slot, service button
+	InterruptEnable uint8
 	unknown0        uint8
-	flipScreen      uint8
-	lampPlayer1     uint8
-	lampPlayer2     uint8
-	coinLockout     uint8
-	coinCounter     uint8
-	in1             uint8 // joystick #2, board test, start buttons, cabinet mode
+	FlipScreen      uint8 // low bit, rotate screen 180 degrees
+	LampPlayer1     uint8
+	LampPlayer2     uint8
+	CoinLockout     uint8
+	CoinCounter     uint8
+	IN1             uint8 // joystick #2, board test, start buttons, cabinet mode
 	dipSwitches     uint8
+	dipSwitches2    uint8 // only used by Ponpoko
 	watchdogReset   uint8
+
+	DIP *rcs.DIPSwitches
+
+	Watchdog      bool // reset the board if the watchdog is not cleared
+	WatchWatchdog bool
+	watchdog      int // vblanks since the watchdog was last cleared
 }
 
-func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
-	s := &system{}
-	roms, err := rcs.LoadROMs(config.DataDir, set)
+func new(ctx rcs.SDLContext, set string) (*rcs.Mach, error) {
+	s := &System{}
+	roms, err := rcs.LoadROMs(config.DataDir, ROM[set])
 	if err != nil {
 		return nil, err
 	}
+	if decode, ok := Decode[set]; ok {
+		decode(roms)
+	}
 
 	s.mem = rcs.NewMemory(1, 0x10000)
 	ram := make([]uint8, 0x1000, 0x1000)
+	s.mem.Label("ram", ram)
+	s.mem.Label("code", roms["code"])
+	s.mem.Label("code2", roms["code2"])
 
 	// This should be ROM, but make it RAM for demo purposes
 	//s.mem.MapROM(0x0000, roms["code"])
@@ -46,29 +68,28 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 	s.mem.MapRAM(0x4000, ram)
 
 	// Register range. Nil mappings first then add real mappings
-	for i := 0x5000; i < 0x6000; i++ {
-		s.mem.MapNil(i)
-	}
-	s.mem.MapWO(0x5000, &s.interruptEnable)
-	for i := 0x5000; i < 0x503f; i++ {
-		s.mem.MapRO(i, &s.in0)
-	}
-	s.mem.MapWO(0x5001, &s.soundEnable)
+	s.mem.MapNil(0x5000)
+	s.mem.MirrorN(0x5000, 1, 0x1000)
+
+	// Inputs and DIP switches only decode the upper address lines in
+	// each block of 64. Mirror them before mapping the write registers.
+	s.mem.MapRO(0x5000, &s.IN0)
+	s.mem.Mirror(0x5000, 1, 0x3f)
+	s.mem.MapRO(0x5040, &s.IN1)
+	s.mem.Mirror(0x5040, 1, 0x3f)
+	s.mem.MapRO(0x5080, &s.dipSwitches)
+	s.mem.Mirror(0x5080, 1, 0x3f)
+	s.mem.MapRO(0x50c0, &s.dipSwitches2)
+	s.mem.MapStore(0x50c0, s.clearWatchdog)
+	s.mem.Mirror(0x50c0, 1, 0x3f)
+
+	s.mem.MapWO(0x5000, &s.InterruptEnable)
 	s.mem.MapWO(0x5002, &s.unknown0)
-	s.mem.MapRW(0x5003, &s.flipScreen)
-	s.mem.MapRW(0x5004, &s.lampPlayer1)
-	s.mem.MapRW(0x5005, &s.lampPlayer2)
-	s.mem.MapRW(0x5006, &s.coinLockout)
-	s.mem.MapRW(0x5007, &s.coinCounter)
-	for i := 0x5040; i <= 0x507f; i++ {
-		s.mem.MapRO(i, &s.in1)
-	}
-	for i := 0x5080; i <= 0x50bf; i++ {
-		s.mem.MapRO(i, &s.dipSwitches)
-	}
-	for i := 0x50c0; i <= 0x50ff; i++ {
-		s.mem.MapWO(i, &s.watchdogReset)
-	}
+	s.mem.MapRW(0x5003, &s.FlipScreen)
+	s.mem.MapRW(0x5004, &s.LampPlayer1)
+	s.mem.MapRW(0x5005, &s.LampPlayer2)
+	s.mem.MapRW(0x5006, &s.CoinLockout)
+	s.mem.MapRW(0x5007, &s.CoinCounter)
 
 	if code2, ok := roms["code2"]; ok {
 		s.mem.MapROM(0x8000, code2)
@@ -86,6 +107,15 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 	cpu := z80.New(s.mem)
 	cpu.Ports.MapRW(0x00, &s.intSelect)
 
+	// The vblank interrupt is held until accepted. Clearing the interrupt
+	// enable also clears a pending interrupt.
+	s.mem.MapStore(0x5000, func(v uint8) {
+		s.InterruptEnable = v
+		if v == 0 {
+			cpu.IRQ = false
+		}
+	})
+
 	var screen rcs.Screen
 	var video *namco.Video
 	if ctx.Renderer != nil {
@@ -99,6 +129,8 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 		if err != nil {
 			return nil, err
 		}
+		s.mem.Label("tiles", video.TileMemory)
+		s.mem.Label("colors", video.ColorMemory)
 		s.mem.MapRAM(0x4000, video.TileMemory)
 		s.mem.MapRAM(0x4400, video.ColorMemory)
 
@@ -107,8 +139,7 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 		// memory so it has an A15 line but it appears to have the RAM mapped at
 		// $c000 as well. Text for HIGH SCORE and CREDIT accesses this high
 		// memory when writing to video memory. Copy protection?
-		s.mem.MapRAM(0xc000, video.TileMemory)
-		s.mem.MapRAM(0xc400, video.ColorMemory)
+		s.mem.Mirror(0x4000, 0x800, 0x8000)
 
 		for i := 0; i < 8; i++ {
 			s.mem.MapRW(0x5060+(i*2), &video.SpriteCoords[i].X)
@@ -121,79 +152,53 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 			H:         namco.H,
 			Texture:   video.Texture,
 			ScanLineV: true,
-			Draw:      video.Draw,
+			Draw: func(r *sdl.Renderer) error {
+				video.Flip = s.FlipScreen&1 != 0
+				return video.Draw(r)
+			},
 		}
 	}
 
-	var synth *audio
-	if ctx.AudioSpec.Channels > 0 {
-		data := audioData{
-			waveforms: roms["waveforms"],
-		}
-		synth, err = newAudio(ctx.AudioSpec, data)
-		if err != nil {
-			return nil, err
-		}
-		s.mem.MapWO(0x5040, &synth.voices[0].acc[0])
-		s.mem.MapWO(0x5041, &synth.voices[0].acc[1])
-		s.mem.MapWO(0x5042, &synth.voices[0].acc[2])
-		s.mem.MapWO(0x5043, &synth.voices[0].acc[3])
-		s.mem.MapWO(0x5044, &synth.voices[0].acc[4])
-		s.mem.MapWO(0x5045, &synth.voices[0].waveform)
-		s.mem.MapWO(0x5046, &synth.voices[1].acc[0])
-		s.mem.MapWO(0x5047, &synth.voices[1].acc[1])
-		s.mem.MapWO(0x5048, &synth.voices[1].acc[2])
-		s.mem.MapWO(0x5049, &synth.voices[1].acc[3])
-		s.mem.MapWO(0x504a, &synth.voices[1].waveform)
-		s.mem.MapWO(0x504b, &synth.voices[2].acc[0])
-		s.mem.MapWO(0x504c, &synth.voices[2].acc[1])
-		s.mem.MapWO(0x504d, &synth.voices[2].acc[2])
-		s.mem.MapWO(0x504e, &synth.voices[2].acc[3])
-		s.mem.MapRW(0x504f, &synth.voices[2].waveform)
-
-		s.mem.MapWO(0x5050, &synth.voices[0].freq[0])
-		s.mem.MapWO(0x5051, &synth.voices[0].freq[1])
-		s.mem.MapWO(0x5052, &synth.voices[0].freq[2])
-		s.mem.MapWO(0x5053, &synth.voices[0].freq[3])
-		s.mem.MapWO(0x5054, &synth.voices[0].freq[4])
-		s.mem.MapWO(0x5055, &synth.voices[0].vol)
-		s.mem.MapWO(0x5056, &synth.voices[1].freq[0])
-		s.mem.MapWO(0x5057, &synth.voices[1].freq[1])
-		s.mem.MapWO(0x5058, &synth.voices[1].freq[2])
-		s.mem.MapWO(0x5059, &synth.voices[1].freq[3])
-		s.mem.MapWO(0x505a, &synth.voices[1].vol)
-		s.mem.MapWO(0x505b, &synth.voices[2].freq[0])
-		s.mem.MapWO(0x505c, &synth.voices[2].freq[1])
-		s.mem.MapWO(0x505d, &synth.voices[2].freq[2])
-		s.mem.MapWO(0x505e, &synth.voices[2].freq[3])
-		s.mem.MapRW(0x505f, &synth.voices[2].vol)
+	wsg, err := namco.NewWSG(roms["waveforms"], ctx.AudioSpec)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < 0x20; i++ {
+		offset := i
+		s.mem.MapStore(0x5040+i, func(v uint8) { wsg.Write(offset, v) })
 	}
+	s.mem.MapWO(0x5001, &wsg.Enable)
+	s.wsg = wsg
 
 	keyboard := newKeyboard(s)
 	joystick := newJoystick(s)
 
-	// Note: If in0 and in1 are not initialized to valid values, the
+	// Note: If IN0 and IN1 are not initialized to valid values, the
 	// game will crash during the game demo in attract mode.
 
 	// Joystick #1 in neutral position
 	// Rack advance not pressed
 	// Coin slots clear
 	// Service button released
-	s.in0 = 0xbf
+	s.IN0 = 0xbf
 
 	// Joystick #2 in neutral position
 	// Board test off
 	// Player start buttons released
 	// Upright cabinet
-	s.in1 = 0xff
+	s.IN1 = 0xff
 
-	s.dipSwitches |= (1 << 0)  // 1 coin per game
-	s.dipSwitches &^= (1 << 1) // ...
-	s.dipSwitches |= (1 << 3)  // 3 lives
-	s.dipSwitches |= (1 << 7)  // Normal ghost names
+	s.dipSwitches2 = 0xff
+
+	s.DIP = rcs.NewDIPSwitches(DIP[set], &s.dipSwitches, &s.IN1, &s.dipSwitches2)
+	if err := s.DIP.LoadFile(filepath.Join(config.DataDir, "dip")); err != nil {
+		return nil, err
+	}
 
+	s.Watchdog = true
 	vblank := func() {
-		if s.interruptEnable != 0 {
+		s.checkWatchdog()
+		if s.InterruptEnable != 0 {
 			cpu.IRQ = true
 			cpu.IRQData = s.intSelect
 		}
@@ -206,8 +211,11 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 	mach := &rcs.Mach{
 		Sys: s,
 		Comps: []rcs.Component{
+			rcs.NewComponent("pacman", "pacman", "", s),
+			rcs.NewComponent("dip", "dip", "", s.DIP),
 			rcs.NewComponent("mem", "mem", "", s.mem),
 			rcs.NewComponent("cpu", "z80", "mem", s.cpu),
+			rcs.NewComponent("wsg", "wsg", "", s.wsg),
 		},
 		CharDecoders: map[string]rcs.CharDecoder{
 			"pacman": PacmanDecoder,
@@ -215,64 +223,139 @@ func new(ctx rcs.SDLContext, set []rcs.ROM) (*rcs.Mach, error) {
 		Ctx:           ctx,
 		Screen:        screen,
 		VBlankFunc:    vblank,
-		QueueAudio:    synth.queue,
+		QueueAudio:    s.wsg.Queue,
 		Keyboard:      keyboard.handle,
 		ButtonHandler: joystick.buttonHandler,
 	}
+	if ranges, ok := Hiscore[set]; ok {
+		mach.NVRAM = &rcs.NVRAM{
+			Name:   "hiscore",
+			Mem:    s.mem,
+			Ranges: ranges,
+		}
+	}
+	if set == "crush" {
+		s.mapMakeTrax(mach)
+	}
 
 	return mach, nil
 }
 
-func (s *system) Components() []*rcs.Component {
+// SoundEnable returns the value of the sound enable latch at $5001.
+func (s *System) SoundEnable() uint8 {
+	return s.wsg.Enable
+}
+
+func (s *System) clearWatchdog(v uint8) {
+	s.watchdogReset = v
+	s.watchdog = 0
+}
+
+func (s *System) checkWatchdog() {
+	if !s.Watchdog {
+		return
+	}
+	s.watchdog++
+	if s.watchdog < watchdogLimit {
+		return
+	}
+	if s.WatchWatchdog {
+		log.Printf("watchdog reset, pc %v", rcs.X16(uint16(s.cpu.PC())))
+	}
+	s.reset()
+}
+
+// reset is the same as the watchdog timer pulling the reset line on the
+// board. The CPU is reset and all the latches are cleared.
+func (s *System) reset() {
+	s.cpu.RESET = true
+	s.InterruptEnable = 0
+	s.wsg.Enable = 0
+	s.unknown0 = 0
+	s.FlipScreen = 0
+	s.LampPlayer1 = 0
+	s.LampPlayer2 = 0
+	s.CoinLockout = 0
+	s.CoinCounter = 0
+	s.watchdog = 0
+}
+
+func (s *System) Components() []*rcs.Component {
 	return []*rcs.Component{}
 }
 
-func (s *system) Save(enc *rcs.Encoder) {
+func (s *System) Save(enc *rcs.Encoder) {
 	s.cpu.Save(enc)
 	if s.video != nil {
 		s.video.Save(enc)
 	}
 	enc.Encode(s.ram)
 	enc.Encode(s.intSelect)
-	enc.Encode(s.in0)
-	enc.Encode(s.interruptEnable)
-	enc.Encode(s.soundEnable)
+	enc.Encode(s.IN0)
+	enc.Encode(s.InterruptEnable)
+	s.wsg.Save(enc)
 	enc.Encode(s.unknown0)
-	enc.Encode(s.flipScreen)
-	enc.Encode(s.lampPlayer1)
-	enc.Encode(s.lampPlayer2)
-	enc.Encode(s.coinLockout)
-	enc.Encode(s.coinCounter)
-	enc.Encode(s.in1)
+	enc.Encode(s.FlipScreen)
+	enc.Encode(s.LampPlayer1)
+	enc.Encode(s.LampPlayer2)
+	enc.Encode(s.CoinLockout)
+	enc.Encode(s.CoinCounter)
+	enc.Encode(s.IN1)
 	enc.Encode(s.dipSwitches)
+	enc.Encode(s.dipSwitches2)
 	enc.Encode(s.watchdogReset)
+	enc.Encode(s.watchdog)
 }
 
-func (s *system) Load(dec *rcs.Decoder) {
+func (s *System) Load(dec *rcs.Decoder) {
 	s.cpu.Load(dec)
 	if s.video != nil {
 		s.video.Load(dec)
 	}
 	dec.Decode(&s.ram)
 	dec.Decode(&s.intSelect)
-	dec.Decode(&s.in0)
-	dec.Decode(&s.interruptEnable)
-	dec.Decode(&s.soundEnable)
+	dec.Decode(&s.IN0)
+	dec.Decode(&s.InterruptEnable)
+	s.wsg.Load(dec)
 	dec.Decode(&s.unknown0)
-	dec.Decode(&s.flipScreen)
-	dec.Decode(&s.lampPlayer1)
-	dec.Decode(&s.lampPlayer2)
-	dec.Decode(&s.coinLockout)
-	dec.Decode(&s.coinCounter)
-	dec.Decode(&s.in1)
+	dec.Decode(&s.FlipScreen)
+	dec.Decode(&s.LampPlayer1)
+	dec.Decode(&s.LampPlayer2)
+	dec.Decode(&s.CoinLockout)
+	dec.Decode(&s.CoinCounter)
+	dec.Decode(&s.IN1)
 	dec.Decode(&s.dipSwitches)
+	dec.Decode(&s.dipSwitches2)
 	dec.Decode(&s.watchdogReset)
+	dec.Decode(&s.watchdog)
 }
 
 func New(ctx rcs.SDLContext) (*rcs.Mach, error) {
-	return new(ctx, ROM["pacman"])
+	return new(ctx, "pacman")
 }
 
 func NewMs(ctx rcs.SDLContext) (*rcs.Mach, error) {
-	return new(ctx, ROM["mspacman"])
+	return new(ctx, "mspacman")
+}
+
+func NewPuckman(ctx rcs.SDLContext) (*rcs.Mach, error) {
+	return new(ctx, "puckman")
+}
+
+func NewPacPlus(ctx rcs.SDLContext) (*rcs.Mach, error) {
+	return new(ctx, "pacplus")
+}
+
+// NewMsAux is Ms. Pac-Man from the Pac-Man ROMs and the ROMs found on the
+// original auxiliary board.
+func NewMsAux(ctx rcs.SDLContext) (*rcs.Mach, error) {
+	return new(ctx, "mspacmanaux")
+}
+
+func NewCrush(ctx rcs.SDLContext) (*rcs.Mach, error) {
+	return new(ctx, "crush")
+}
+
+func NewPonpoko(ctx rcs.SDLContext) (*rcs.Mach, error) {
+	return new(ctx, "ponpoko")
 }
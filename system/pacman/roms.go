@@ -2,6 +2,9 @@ package pacman
 
 import "github.com/blackchip-org/retro-cs/rcs"
 
+// ROM contains the ROMs for each set. The sets with an empty checksum
+// cannot be loaded until the checksum is filled in and are not registered
+// as systems until then.
 var ROM = map[string][]rcs.ROM{
 	"pacman": []rcs.ROM{
 		rcs.NewROM("code     ", "pacman.6e", "e87e059c5be45753f7e9f33dff851f16d6751181"),
@@ -29,4 +32,80 @@ var ROM = map[string][]rcs.ROM{
 		rcs.NewROM("waveforms", "82s126.1m", "bbcec0570aeceb582ff8238a4bc8546a23430081"),
 		rcs.NewROM("waveforms", "82s126.3m", "0c4d0bee858b97632411c440bea6948a74759746"),
 	},
+	"puckman": []rcs.ROM{
+		rcs.NewROM("code     ", "pm1_prg1.6e", ""),
+		rcs.NewROM("code     ", "pm1_prg2.6k", ""),
+		rcs.NewROM("code     ", "pm1_prg3.6f", ""),
+		rcs.NewROM("code     ", "pm1_prg4.6m", ""),
+		rcs.NewROM("code     ", "pm1_prg5.6h", ""),
+		rcs.NewROM("code     ", "pm1_prg6.6n", ""),
+		rcs.NewROM("code     ", "pm1_prg7.6j", ""),
+		rcs.NewROM("code     ", "pm1_prg8.6p", ""),
+		rcs.NewROM("tiles    ", "pm1_chg1.5e", ""),
+		rcs.NewROM("tiles    ", "pm1_chg2.5h", ""),
+		rcs.NewROM("sprites  ", "pm1_chg3.5f", ""),
+		rcs.NewROM("sprites  ", "pm1_chg4.5j", ""),
+		rcs.NewROM("colors   ", "pm1-1.7f  ", "8d0268dee78e47c712202b0ec4f1f51109b1f2a5"),
+		rcs.NewROM("palettes ", "pm1-4.4a  ", "19097b5f60d1030f8b82d9f1d3a241f93e5c75d6"),
+		rcs.NewROM("waveforms", "pm1-3.1m  ", "bbcec0570aeceb582ff8238a4bc8546a23430081"),
+		rcs.NewROM("waveforms", "pm1-2.3m  ", "0c4d0bee858b97632411c440bea6948a74759746"),
+	},
+	// Code is encrypted, see decodePacPlus
+	"pacplus": []rcs.ROM{
+		rcs.NewROM("code     ", "pacplus.6e", ""),
+		rcs.NewROM("code     ", "pacplus.6f", ""),
+		rcs.NewROM("code     ", "pacplus.6h", ""),
+		rcs.NewROM("code     ", "pacplus.6j", ""),
+		rcs.NewROM("tiles    ", "pacplus.5e", ""),
+		rcs.NewROM("sprites  ", "pacplus.5f", ""),
+		rcs.NewROM("colors   ", "pacplus.7f", ""),
+		rcs.NewROM("palettes ", "pacplus.4a", ""),
+		rcs.NewROM("waveforms", "82s126.1m ", "bbcec0570aeceb582ff8238a4bc8546a23430081"),
+		rcs.NewROM("waveforms", "82s126.3m ", "0c4d0bee858b97632411c440bea6948a74759746"),
+	},
+	// Pac-Man ROMs with the auxiliary board, see decodeMsPacManAux
+	"mspacmanaux": []rcs.ROM{
+		rcs.NewROM("code     ", "pacman.6e", "e87e059c5be45753f7e9f33dff851f16d6751181"),
+		rcs.NewROM("code     ", "pacman.6f", "674d3a7f00d8be5e38b1fdc208ebef5a92d38329"),
+		rcs.NewROM("code     ", "pacman.6h", "8e47e8c2c4d6117d174cdac150392042d3e0a881"),
+		rcs.NewROM("code     ", "pacman.6j", "d4a70d56bb01d27d094d73db8667ffb00ca69cb9"),
+		rcs.NewROM("u5       ", "u5       ", ""),
+		rcs.NewROM("u6       ", "u6       ", ""),
+		rcs.NewROM("u7       ", "u7       ", ""),
+		rcs.NewROM("tiles    ", "5e       ", "5e8b472b615f12efca3fe792410c23619f067845"),
+		rcs.NewROM("sprites  ", "5f       ", "fd6a1dde780b39aea76bf1c4befa5882573c2ef4"),
+		rcs.NewROM("colors   ", "82s123.7f", "8d0268dee78e47c712202b0ec4f1f51109b1f2a5"),
+		rcs.NewROM("palettes ", "82s126.4a", "19097b5f60d1030f8b82d9f1d3a241f93e5c75d6"),
+		rcs.NewROM("waveforms", "82s126.1m", "bbcec0570aeceb582ff8238a4bc8546a23430081"),
+		rcs.NewROM("waveforms", "82s126.3m", "0c4d0bee858b97632411c440bea6948a74759746"),
+	},
+	"crush": []rcs.ROM{
+		rcs.NewROM("code     ", "crushkrl.6e", ""),
+		rcs.NewROM("code     ", "crushkrl.6f", ""),
+		rcs.NewROM("code     ", "crushkrl.6h", ""),
+		rcs.NewROM("code     ", "crushkrl.6j", ""),
+		rcs.NewROM("tiles    ", "maketrax.5e", ""),
+		rcs.NewROM("sprites  ", "maketrax.5f", ""),
+		rcs.NewROM("colors   ", "82s123.7f  ", ""),
+		rcs.NewROM("palettes ", "2s140.4a   ", ""),
+		rcs.NewROM("waveforms", "82s126.1m  ", "bbcec0570aeceb582ff8238a4bc8546a23430081"),
+		rcs.NewROM("waveforms", "82s126.3m  ", "0c4d0bee858b97632411c440bea6948a74759746"),
+	},
+	// Graphics are not in the usual layout, see decodePonpoko
+	"ponpoko": []rcs.ROM{
+		rcs.NewROM("code     ", "ppokoj1.bin ", ""),
+		rcs.NewROM("code     ", "ppokoj2.bin ", ""),
+		rcs.NewROM("code     ", "ppokoj3.bin ", ""),
+		rcs.NewROM("code     ", "ppokoj4.bin ", ""),
+		rcs.NewROM("code2    ", "ppoko5.bin  ", ""),
+		rcs.NewROM("code2    ", "ppoko6.bin  ", ""),
+		rcs.NewROM("code2    ", "ppoko7.bin  ", ""),
+		rcs.NewROM("code2    ", "ppokoj8.bin ", ""),
+		rcs.NewROM("tiles    ", "ppoko9.bin  ", ""),
+		rcs.NewROM("sprites  ", "ppoko10.bin ", ""),
+		rcs.NewROM("colors   ", "82s123.7f   ", ""),
+		rcs.NewROM("palettes ", "82s126.4a   ", ""),
+		rcs.NewROM("waveforms", "82s126.1m   ", "bbcec0570aeceb582ff8238a4bc8546a23430081"),
+		rcs.NewROM("waveforms", "82s126.3m   ", "0c4d0bee858b97632411c440bea6948a74759746"),
+	},
 }
@@ -0,0 +1,126 @@
+package pacman
+
+// Locations in RAM used by the Pac-Man game code. These are not valid for
+// the other games on this board. Positions are stored as Y then X.
+const (
+	ramPosition    = 0x4d00 // pixel positions, ghosts then Pac-Man
+	ramTile        = 0x4d0a // tile positions, ghosts only
+	ramPacManTile  = 0x4d39
+	ramGhostHome   = 0x4da0 // zero while the ghost is in the pen
+	ramGhostBlue   = 0x4da7 // non-zero while the ghost is edible
+	ramGhostDead   = 0x4dac // non-zero while the ghost's eyes return home
+	ramModeIndex   = 0x4dc1 // even for scatter, odd for chase
+	ramModeTimer   = 0x4dc2 // 16-bit
+	ramBlueTimer   = 0x4dcb // 16-bit
+	ramPlayer      = 0x4e09
+	ramDotsEaten   = 0x4e0e
+	ramDotCounters = 0x4e0f // pink, blue, and orange ghosts
+	ramLevel       = 0x4e13
+	ramLives       = 0x4e14
+	ramCredits     = 0x4e6e
+	ramScore1      = 0x4e80
+	ramScore2      = 0x4e84
+)
+
+// Ghost modes
+const (
+	ModeHome       = "home"
+	ModeScatter    = "scatter"
+	ModeChase      = "chase"
+	ModeFrightened = "frightened"
+	ModeEyes       = "eyes"
+)
+
+// GhostNames are the nicknames of the ghosts in the order they are stored
+// in memory.
+var GhostNames = []string{"blinky", "pinky", "inky", "clyde"}
+
+// Actor is the position of Pac-Man or a ghost. The game's coordinates
+// decrease from bottom to top and from right to left.
+type Actor struct {
+	X, Y         int // pixels
+	TileX, TileY int
+}
+
+// Ghost is the state of one ghost.
+type Ghost struct {
+	Actor
+	Name string
+	Mode string
+	Dots int // dots eaten before leaving the pen, unused for blinky
+}
+
+// State is the game state as found in RAM.
+type State struct {
+	PacMan    Actor
+	Ghosts    [4]Ghost
+	Scatter   bool // ghosts head to their corners when not chasing
+	ModeTimer int
+	BlueTimer int
+	DotsEaten int
+	Level     int // starts at 1
+	Lives     int
+	Player    int // starts at 1
+	Score     [2]int
+	Credits   int
+}
+
+func bcd(v uint8) int {
+	return int(v>>4)*10 + int(v&0x0f)
+}
+
+// score decodes the three BCD bytes at addr which are stored with the
+// least significant digits first.
+func (s *System) score(addr int) int {
+	v := 0
+	for i := 2; i >= 0; i-- {
+		v = v*100 + bcd(s.mem.Read(addr+i))
+	}
+	return v
+}
+
+// State decodes the game state from RAM.
+func (s *System) State() State {
+	mem := s.mem
+	st := State{
+		Scatter:   mem.Read(ramModeIndex)%2 == 0,
+		ModeTimer: mem.ReadLE(ramModeTimer),
+		BlueTimer: mem.ReadLE(ramBlueTimer),
+		DotsEaten: int(mem.Read(ramDotsEaten)),
+		Level:     int(mem.Read(ramLevel)) + 1,
+		Lives:     int(mem.Read(ramLives)),
+		Player:    int(mem.Read(ramPlayer)) + 1,
+		Score:     [2]int{s.score(ramScore1), s.score(ramScore2)},
+		Credits:   bcd(mem.Read(ramCredits)),
+	}
+	st.PacMan = Actor{
+		Y:     int(mem.Read(ramPosition + 8)),
+		X:     int(mem.Read(ramPosition + 9)),
+		TileY: int(mem.Read(ramPacManTile)),
+		TileX: int(mem.Read(ramPacManTile + 1)),
+	}
+	for i := range st.Ghosts {
+		g := &st.Ghosts[i]
+		g.Name = GhostNames[i]
+		g.Y = int(mem.Read(ramPosition + i*2))
+		g.X = int(mem.Read(ramPosition + i*2 + 1))
+		g.TileY = int(mem.Read(ramTile + i*2))
+		g.TileX = int(mem.Read(ramTile + i*2 + 1))
+		if i > 0 {
+			g.Dots = int(mem.Read(ramDotCounters + i - 1))
+		}
+		switch {
+		case mem.Read(ramGhostDead+i) != 0:
+			g.Mode = ModeEyes
+		case mem.Read(ramGhostBlue+i) != 0:
+			g.Mode = ModeFrightened
+		case mem.Read(ramGhostHome+i) == 0:
+			g.Mode = ModeHome
+		case st.Scatter:
+			g.Mode = ModeScatter
+		default:
+			g.Mode = ModeChase
+		}
+	}
+	return st
+}
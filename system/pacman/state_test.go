@@ -0,0 +1,52 @@
+package pacman
+
+import (
+	"testing"
+
+	"github.com/blackchip-org/retro-cs/rcs"
+)
+
+func TestState(t *testing.T) {
+	s := &System{mem: rcs.NewMemory(1, 0x10000)}
+	s.mem.MapRAM(0x4000, make([]uint8, 0x1000))
+
+	s.mem.WriteN(ramPosition+8, 0x64, 0x80)
+	s.mem.WriteN(ramPacManTile, 0x2e, 0x2f)
+	s.mem.WriteN(ramPosition+2, 0x20, 0x30)
+	s.mem.WriteN(ramTile+2, 0x21, 0x31)
+	s.mem.Write(ramGhostHome+1, 1)
+	s.mem.Write(ramGhostBlue+2, 1)
+	s.mem.Write(ramGhostHome+3, 1)
+	s.mem.Write(ramGhostDead+3, 1)
+	s.mem.Write(ramDotCounters+1, 30)
+	s.mem.Write(ramModeIndex, 1)
+	s.mem.Write(ramLevel, 2)
+	s.mem.Write(ramLives, 3)
+	s.mem.WriteN(ramScore1, 0x90, 0x45, 0x01)
+	s.mem.Write(ramCredits, 0x12)
+
+	st := s.State()
+	want := Actor{X: 0x80, Y: 0x64, TileX: 0x2f, TileY: 0x2e}
+	if st.PacMan != want {
+		t.Errorf("pac-man\n have: %+v \n want: %+v", st.PacMan, want)
+	}
+	want = Actor{X: 0x30, Y: 0x20, TileX: 0x31, TileY: 0x21}
+	if st.Ghosts[1].Actor != want {
+		t.Errorf("pinky\n have: %+v \n want: %+v", st.Ghosts[1].Actor, want)
+	}
+	modes := []string{ModeHome, ModeChase, ModeFrightened, ModeEyes}
+	for i, mode := range modes {
+		if st.Ghosts[i].Mode != mode {
+			t.Errorf("%v: have %v, want %v", st.Ghosts[i].Name, st.Ghosts[i].Mode, mode)
+		}
+	}
+	if st.Ghosts[2].Dots != 30 {
+		t.Errorf("dots: have %v, want %v", st.Ghosts[2].Dots, 30)
+	}
+	if st.Level != 3 || st.Lives != 3 || st.Credits != 12 {
+		t.Errorf("level %v, lives %v, credits %v", st.Level, st.Lives, st.Credits)
+	}
+	if st.Score[0] != 14590 {
+		t.Errorf("score: have %v, want %v", st.Score[0], 14590)
+	}
+}
@@ -31,6 +31,9 @@ var VideoConfig = namco.Config{
 	},
 	PaletteEntries: 64,
 	PaletteColors:  4,
+	Colors:         16,
+	Transparent:    []int{0},
+	Sprites:        namco.PacmanSprites,
 }
 
 var spritePixels = [][]int{